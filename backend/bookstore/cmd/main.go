@@ -2,19 +2,46 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+	_ "time/tzdata" // streaks are counted in the user's IANA timezone
 
+	"github.com/bereke1t2/bookstore/internal/domain/achievement"
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+	"github.com/bereke1t2/bookstore/internal/domain/chat"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+	"github.com/bereke1t2/bookstore/internal/domain/trending"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/cache"
 	postgres "github.com/bereke1t2/bookstore/internal/infrastructure/database/postgres"
 	"github.com/bereke1t2/bookstore/internal/infrastructure/database/supabase"
 	Gemini "github.com/bereke1t2/bookstore/internal/infrastructure/externalapis"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/payment"
 	handler "github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
 	router "github.com/bereke1t2/bookstore/internal/infrastructure/server/router"
 	bookusecase "github.com/bereke1t2/bookstore/internal/usecase/book"
+	achievementusecase "github.com/bereke1t2/bookstore/internal/usecase/achievement"
+	activityusecase "github.com/bereke1t2/bookstore/internal/usecase/activity"
 	chatusecase "github.com/bereke1t2/bookstore/internal/usecase/chat"
+	entitlementusecase "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
+	goalusecase "github.com/bereke1t2/bookstore/internal/usecase/goal"
+	leaderboardusecase "github.com/bereke1t2/bookstore/internal/usecase/leaderboard"
+	libraryusecase "github.com/bereke1t2/bookstore/internal/usecase/library"
 	noteusecase "github.com/bereke1t2/bookstore/internal/usecase/note"
+	opdsusecase "github.com/bereke1t2/bookstore/internal/usecase/opds"
+	orderusecase "github.com/bereke1t2/bookstore/internal/usecase/order"
+	ratingusecase "github.com/bereke1t2/bookstore/internal/usecase/rating"
+	readingusecase "github.com/bereke1t2/bookstore/internal/usecase/reading"
+	recommendationusecase "github.com/bereke1t2/bookstore/internal/usecase/recommendation"
+	reviewusecase "github.com/bereke1t2/bookstore/internal/usecase/review"
+	shelfusecase "github.com/bereke1t2/bookstore/internal/usecase/shelf"
+	socialusecase "github.com/bereke1t2/bookstore/internal/usecase/social"
+	trendingusecase "github.com/bereke1t2/bookstore/internal/usecase/trending"
 	userusecase "github.com/bereke1t2/bookstore/internal/usecase/user"
 	"github.com/gin-gonic/gin"
 
@@ -40,10 +67,37 @@ func main() {
 		log.Fatal("❌ Error creating Gemini client:", err)
 	}
 
-	chatRepo := Gemini.NewChatResponseImpl(geminiClient)
 	bookRepo := postgres.NewBookRepositoryImpl(db)
 	userRepo := postgres.NewUserRepositoryPostgres(db)
 	noteRepo := postgres.NewNoteRepositoryPostgres(db)
+	reviewRepo := postgres.NewReviewCardRepositoryPostgres(db)
+	readingRepo := postgres.NewReadingProgressRepositoryPostgres(db)
+	activityStore := postgres.NewActivityRepositoryPostgres(db)
+	achievementRepo := postgres.NewAchievementRepositoryPostgres(db)
+	leaderboardRepo := postgres.NewLeaderboardRepositoryPostgres(db)
+	friendRepo := postgres.NewFriendRepositoryPostgres(db)
+	goalRepo := postgres.NewGoalRepositoryPostgres(db)
+	shelfRepo := postgres.NewShelfRepositoryPostgres(db)
+	importRepo := postgres.NewImportRepositoryPostgres(db)
+	ratingRepo := postgres.NewRatingRepositoryPostgres(db)
+	recommendationRepo := postgres.NewRecommendationRepositoryPostgres(db)
+	trendingRepo := postgres.NewTrendingRepositoryPostgres(db)
+	orderRepo := postgres.NewOrderRepositoryPostgres(db)
+	entitlementRepo := postgres.NewEntitlementRepositoryPostgres(db)
+	questionRepo := postgres.NewQuestionRepositoryPostgres(db)
+
+	if err := userRepo.MigrateUserTable(); err != nil {
+		log.Println("⚠️ Warning: Could not add user roles:", err)
+	} else {
+		log.Println("✅ User roles ready")
+		grantAdmins(userRepo)
+	}
+
+	if err := bookRepo.CreateBookColumns(); err != nil {
+		log.Println("⚠️ Warning: Could not add columns to books:", err)
+	} else {
+		log.Println("✅ Book columns ready")
+	}
 
 	// Create notes table if not exists
 	if err := noteRepo.CreateNoteTable(); err != nil {
@@ -52,17 +106,122 @@ func main() {
 		log.Println("✅ Notes table ready")
 	}
 
-	createBookUC := bookusecase.NewCreateBookUseCase(bookRepo, supabaseClient)
-	getBookByIDUC := bookusecase.NewGetBookByIDUseCase(bookRepo)
+	if err := reviewRepo.CreateReviewCardTable(); err != nil {
+		log.Println("⚠️ Warning: Could not create review_cards table:", err)
+	} else {
+		log.Println("✅ Review cards table ready")
+	}
+
+	if err := readingRepo.CreateReadingProgressTable(); err != nil {
+		log.Println("⚠️ Warning: Could not create reading_progress table:", err)
+	} else {
+		log.Println("✅ Reading progress table ready")
+	}
+
+	if err := activityStore.CreateActivityTable(); err != nil {
+		log.Println("⚠️ Warning: Could not create activity_events table:", err)
+	} else {
+		log.Println("✅ Activity events table ready")
+	}
+
+	if err := achievementRepo.CreateAchievementTable(); err != nil {
+		log.Println("⚠️ Warning: Could not create user_achievements table:", err)
+	} else {
+		log.Println("✅ Achievements table ready")
+	}
+
+	if err := friendRepo.CreateFriendTable(); err != nil {
+		log.Println("⚠️ Warning: Could not create user_friends table:", err)
+	} else {
+		log.Println("✅ Friends table ready")
+	}
+
+	if err := leaderboardRepo.CreateLeaderboardIndexes(); err != nil {
+		log.Println("⚠️ Warning: Could not create leaderboard indexes:", err)
+	} else {
+		log.Println("✅ Leaderboard indexes ready")
+	}
+
+	if err := goalRepo.CreateGoalTable(); err != nil {
+		log.Println("⚠️ Warning: Could not create reading_goals table:", err)
+	} else {
+		log.Println("✅ Reading goals table ready")
+	}
+
+	if err := shelfRepo.CreateShelfTables(); err != nil {
+		log.Println("⚠️ Warning: Could not create shelf tables:", err)
+	} else {
+		log.Println("✅ Shelf tables ready")
+	}
+
+	if err := importRepo.CreateImportTable(); err != nil {
+		log.Println("⚠️ Warning: Could not create library_imports table:", err)
+	} else if n, err := importRepo.FailInterrupted(); err != nil {
+		log.Println("⚠️ Warning: Could not fail interrupted imports:", err)
+	} else {
+		log.Printf("✅ Library imports table ready (%d interrupted imports failed)", n)
+	}
+
+	if err := ratingRepo.CreateBookReviewTables(); err != nil {
+		log.Println("⚠️ Warning: Could not create book review tables:", err)
+	} else {
+		log.Println("✅ Book review tables ready")
+	}
+
+	if err := trendingRepo.CreateTrendingTables(); err != nil {
+		log.Println("⚠️ Warning: Could not create trending tables:", err)
+	} else {
+		log.Println("✅ Trending tables ready")
+	}
+
+	if err := orderRepo.CreateOrderTables(); err != nil {
+		log.Println("⚠️ Warning: Could not create order tables:", err)
+	} else {
+		log.Println("✅ Order tables ready")
+	}
+
+	if err := entitlementRepo.CreateLicenseTable(); err != nil {
+		log.Println("⚠️ Warning: Could not create license table:", err)
+	} else {
+		log.Println("✅ License table ready")
+	}
+
+	if err := questionRepo.CreateIssuedQuestionTable(); err != nil {
+		log.Println("⚠️ Warning: Could not create issued questions table:", err)
+	} else {
+		log.Println("✅ Issued questions table ready")
+	}
+
+	// Every recorded activity event re-evaluates the achievement rules
+	badges := loadBadges()
+	evaluateAchievementsUC := achievementusecase.NewEvaluateAchievementsUseCase(achievementRepo, badges)
+	awardingRepo := achievementusecase.NewAwardingActivityRepository(activityStore, evaluateAchievementsUC)
+
+	// Reading sessions and finished books then check the user's goals
+	evaluateGoalsUC := goalusecase.NewEvaluateGoalsUseCase(goalRepo, awardingRepo)
+	activityRepo := goalusecase.NewGoalTrackingActivityRepository(awardingRepo, evaluateGoalsUC)
+
+	var chatRepo chat.ChatRepository = Gemini.NewChatResponseImpl(geminiClient)
+	aiCache := newAICache(db, chatRepo)
+	if aiCache != nil {
+		chatRepo = aiCache
+	}
+
+	// Paid books are gated on purchases and institutional licenses
+	accessUC := entitlementusecase.NewCheckAccessUseCase(entitlementRepo, bookRepo)
+
+	createBookUC := bookusecase.NewCreateBookUseCase(bookRepo, supabaseClient, activityRepo)
+	getBookByIDUC := bookusecase.NewGetBookByIDUseCase(bookRepo, accessUC)
 	updateBookUC := bookusecase.NewUpdateBookUseCase(bookRepo)
 	deleteBookUC := bookusecase.NewDeleteBookUsecase(bookRepo)
-	getAllBooksUC := bookusecase.NewGetAllBooksUseCase(bookRepo)
+	getAllBooksUC := bookusecase.NewGetAllBooksUseCase(bookRepo, accessUC)
 
 	getChatResponsesUC := chatusecase.NewGetChatResponseUseCase(chatRepo)
 	getChatResponseStreamUC := chatusecase.NewGetChatResponseStreamUseCase(chatRepo)
 	getMultipleChoiceUC := chatusecase.NewGetMultipleChoiceQuestionUseCase(chatRepo)
 	getTrueFalseUC := chatusecase.NewGetTrueFalseQuestionUseCase(chatRepo)
-	getShortAnswerUC := chatusecase.NewGetShortAnswerUseCase(chatRepo)
+	getShortAnswerUC := chatusecase.NewGetShortAnswerUseCase(chatRepo, questionRepo)
+	gradeShortAnswerUC := chatusecase.NewGradeShortAnswerUseCase(chatRepo, questionRepo, activityRepo)
 
 	createUserUC := userusecase.NewCreateUserUseCase(userRepo)
 	getUserByIDUC := userusecase.NewGetUserByIDUseCase(userRepo)
@@ -71,22 +230,125 @@ func main() {
 	getAllUsersUC := userusecase.NewGetAllUsersUseCase(userRepo)
 	// getUserByEmailUc := userusecase.NewGetUserByEmailUseCase(userRepo)
 	loginUC := userusecase.NewLoginUseCase(userRepo)
+	setUserRoleUC := userusecase.NewSetUserRoleUseCase(userRepo)
 
 	// Note UseCases
 	geminiSummarizer := noteusecase.NewGeminiSummarizer(geminiClient.Model())
-	createNoteUC := noteusecase.NewCreateNoteUseCase(noteRepo)
+	createNoteUC := noteusecase.NewCreateNoteUseCase(noteRepo, activityRepo)
 	getNotesUC := noteusecase.NewGetNotesUseCase(noteRepo)
 	deleteNoteUC := noteusecase.NewDeleteNoteUseCase(noteRepo)
-	generateAINoteUC := noteusecase.NewGenerateAINoteUseCase(noteRepo, geminiSummarizer)
+	generateAINoteUC := noteusecase.NewGenerateAINoteUseCase(noteRepo, geminiSummarizer, activityRepo, accessUC)
+	updateNoteUC := noteusecase.NewUpdateNoteUseCase(noteRepo)
+	searchNotesUC := noteusecase.NewSearchNotesUseCase(noteRepo)
+	exportNotesUC := noteusecase.NewExportNotesUseCase(noteRepo, bookRepo)
+	syncNotesUC := noteusecase.NewSyncNotesUseCase(noteRepo)
+
+	// Review UseCases
+	createCardsFromQuizUC := reviewusecase.NewCreateCardsFromQuizUseCase(reviewRepo)
+	createCardsFromNotesUC := reviewusecase.NewCreateCardsFromNotesUseCase(reviewRepo, noteRepo)
+	getDueCardsUC := reviewusecase.NewGetDueCardsUseCase(reviewRepo)
+	reviewCardUC := reviewusecase.NewReviewCardUseCase(reviewRepo)
+	getDecksUC := reviewusecase.NewGetDecksUseCase(reviewRepo)
+
+	// Reading UseCases
+	updateProgressUC := readingusecase.NewUpdateProgressUseCase(readingRepo, bookRepo, activityRepo, shelfRepo)
+	getReadingUC := readingusecase.NewGetReadingUseCase(readingRepo)
+
+	// Activity UseCases
+	getStatsUC := activityusecase.NewGetStatsUseCase(activityRepo)
+	getAchievementsUC := achievementusecase.NewGetAchievementsUseCase(achievementRepo, badges)
+
+	// Leaderboard UseCases
+	getLeaderboardUC := leaderboardusecase.NewGetLeaderboardUseCase(leaderboardRepo, activityRepo)
+	addFriendUC := socialusecase.NewAddFriendUseCase(friendRepo)
+	removeFriendUC := socialusecase.NewRemoveFriendUseCase(friendRepo)
+	listFriendsUC := socialusecase.NewListFriendsUseCase(friendRepo)
+
+	// Goal UseCases
+	createGoalUC := goalusecase.NewCreateGoalUseCase(goalRepo, awardingRepo, evaluateGoalsUC)
+	getGoalsUC := goalusecase.NewGetGoalsUseCase(goalRepo, activityRepo)
+	deleteGoalUC := goalusecase.NewDeleteGoalUseCase(goalRepo)
+
+	// Shelf UseCases
+	listShelvesUC := shelfusecase.NewListShelvesUseCase(shelfRepo)
+	createShelfUC := shelfusecase.NewCreateShelfUseCase(shelfRepo)
+	renameShelfUC := shelfusecase.NewRenameShelfUseCase(shelfRepo)
+	deleteShelfUC := shelfusecase.NewDeleteShelfUseCase(shelfRepo)
+	reorderShelvesUC := shelfusecase.NewReorderShelvesUseCase(shelfRepo)
+	getShelfUC := shelfusecase.NewGetShelfUseCase(shelfRepo)
+	addToShelfUC := shelfusecase.NewAddToShelfUseCase(shelfRepo, bookRepo)
+	removeFromShelfUC := shelfusecase.NewRemoveFromShelfUseCase(shelfRepo)
+	reorderShelfUC := shelfusecase.NewReorderShelfUseCase(shelfRepo)
+	exportShelvesUC := shelfusecase.NewExportShelvesUseCase(shelfRepo)
+
+	// Library import UseCases
+	externalProvider := newExternalProvider()
+	startImportUC := libraryusecase.NewStartImportUseCase(importRepo, shelfRepo, bookRepo, externalProvider)
+	getImportUC := libraryusecase.NewGetImportUseCase(importRepo)
+	listImportsUC := libraryusecase.NewListImportsUseCase(importRepo)
+
+	// Book review UseCases
+	submitReviewUC := ratingusecase.NewSubmitReviewUseCase(ratingRepo, bookRepo)
+	deleteReviewUC := ratingusecase.NewDeleteReviewUseCase(ratingRepo)
+	listReviewsUC := ratingusecase.NewListReviewsUseCase(ratingRepo, bookRepo)
+	reportReviewUC := ratingusecase.NewReportReviewUseCase(ratingRepo)
+	listReportedReviewsUC := ratingusecase.NewListReportedReviewsUseCase(ratingRepo)
+	moderateReviewUC := ratingusecase.NewModerateReviewUseCase(ratingRepo)
+
+	getRecommendationsUC := recommendationusecase.NewGetRecommendationsUseCase(recommendationRepo, bookRepo, accessUC)
+
+	// Trending UseCases; scores are recomputed in the background
+	trendingConfig := loadTrendingConfig()
+	getTrendingBooksUC := bookusecase.NewGetTrendingBooks(bookRepo, trendingRepo, externalProvider, trendingConfig, accessUC)
+	downloadBookUC := bookusecase.NewDownloadBookUseCase(bookRepo, trendingRepo, accessUC)
+	importBookUC := bookusecase.NewImportBookUseCase(bookRepo, externalProvider)
+	recordQuizActivityUC := trendingusecase.NewRecordQuizActivityUseCase(trendingRepo)
+	refreshTrendingUC := trendingusecase.NewRefreshTrendingUseCase(trendingRepo, trendingConfig)
+	go refreshTrendingUC.Run(context.Background())
 
-	getTrendingBooksUC := bookusecase.NewGetTrendingBooks()
+	getOPDSFeedUC := opdsusecase.NewGetFeedUseCase(bookRepo, accessUC)
 
-	userHandler := handler.NewUserHandler(createUserUC, updateUserUC, deleteUserUC, getAllUsersUC, getUserByIDUC, loginUC)
-	bookHandler := handler.NewBookHandler(*createBookUC, *getAllBooksUC, *deleteBookUC, *getBookByIDUC, *updateBookUC, *getTrendingBooksUC)
-	chatHandler := handler.NewChatHandler(*getMultipleChoiceUC, *getTrueFalseUC, *getShortAnswerUC, *getChatResponsesUC, getChatResponseStreamUC)
-	noteHandler := handler.NewNoteHandler(createNoteUC, getNotesUC, deleteNoteUC, generateAINoteUC)
+	// Order UseCases
+	paymentGateway := newPaymentGateway()
+	getCartUC := orderusecase.NewGetCartUseCase(orderRepo)
+	addToCartUC := orderusecase.NewAddToCartUseCase(orderRepo, bookRepo)
+	removeFromCartUC := orderusecase.NewRemoveFromCartUseCase(orderRepo)
+	checkoutUC := orderusecase.NewCheckoutUseCase(orderRepo, bookRepo, paymentGateway)
+	payOrderUC := orderusecase.NewPayOrderUseCase(orderRepo, paymentGateway)
+	listOrdersUC := orderusecase.NewListOrdersUseCase(orderRepo)
+	getOrderUC := orderusecase.NewGetOrderUseCase(orderRepo)
+	refundOrderUC := orderusecase.NewRefundOrderUseCase(orderRepo, paymentGateway)
 
-	router.SetupRoutes(r, bookHandler, userHandler, chatHandler, noteHandler)
+	// License UseCases
+	createLicenseUC := entitlementusecase.NewCreateLicenseUseCase(entitlementRepo)
+	listLicensesUC := entitlementusecase.NewListLicensesUseCase(entitlementRepo)
+	deleteLicenseUC := entitlementusecase.NewDeleteLicenseUseCase(entitlementRepo)
+	addLicenseMemberUC := entitlementusecase.NewAddLicenseMemberUseCase(entitlementRepo, userRepo)
+	removeLicenseMemberUC := entitlementusecase.NewRemoveLicenseMemberUseCase(entitlementRepo)
+	listLicenseMembersUC := entitlementusecase.NewListLicenseMembersUseCase(entitlementRepo)
+
+	userHandler := handler.NewUserHandler(createUserUC, updateUserUC, deleteUserUC, getAllUsersUC, getUserByIDUC, loginUC, setUserRoleUC)
+	bookHandler := handler.NewBookHandler(*createBookUC, *getAllBooksUC, *deleteBookUC, *getBookByIDUC, *updateBookUC, *getTrendingBooksUC, *downloadBookUC, *importBookUC)
+	chatHandler := handler.NewChatHandler(*getMultipleChoiceUC, *getTrueFalseUC, *getShortAnswerUC, *getChatResponsesUC, getChatResponseStreamUC, gradeShortAnswerUC, aiCache, recordQuizActivityUC, accessUC)
+	noteHandler := handler.NewNoteHandler(createNoteUC, getNotesUC, deleteNoteUC, generateAINoteUC, updateNoteUC, searchNotesUC, exportNotesUC, syncNotesUC)
+	reviewHandler := handler.NewReviewHandler(createCardsFromQuizUC, createCardsFromNotesUC, getDueCardsUC, reviewCardUC, getDecksUC)
+	readingHandler := handler.NewReadingHandler(updateProgressUC, getReadingUC)
+	activityHandler := handler.NewActivityHandler(getStatsUC)
+	achievementHandler := handler.NewAchievementHandler(getAchievementsUC)
+	leaderboardHandler := handler.NewLeaderboardHandler(getLeaderboardUC, addFriendUC, removeFriendUC, listFriendsUC)
+	goalHandler := handler.NewGoalHandler(createGoalUC, getGoalsUC, deleteGoalUC)
+	shelfHandler := handler.NewShelfHandler(listShelvesUC, createShelfUC, renameShelfUC, deleteShelfUC, reorderShelvesUC,
+		getShelfUC, addToShelfUC, removeFromShelfUC, reorderShelfUC, exportShelvesUC)
+	importHandler := handler.NewImportHandler(startImportUC, getImportUC, listImportsUC)
+	ratingHandler := handler.NewRatingHandler(submitReviewUC, deleteReviewUC, listReviewsUC, reportReviewUC,
+		listReportedReviewsUC, moderateReviewUC)
+	recommendationHandler := handler.NewRecommendationHandler(getRecommendationsUC)
+	opdsHandler := handler.NewOPDSHandler(getOPDSFeedUC, loginUC)
+	orderHandler := handler.NewOrderHandler(getCartUC, addToCartUC, removeFromCartUC, checkoutUC, payOrderUC,
+		listOrdersUC, getOrderUC, refundOrderUC)
+	licenseHandler := handler.NewLicenseHandler(createLicenseUC, listLicensesUC, deleteLicenseUC, addLicenseMemberUC, removeLicenseMemberUC, listLicenseMembersUC)
+
+	router.SetupRoutes(r, bookHandler, userHandler, chatHandler, noteHandler, reviewHandler, readingHandler, activityHandler, achievementHandler, leaderboardHandler, goalHandler, shelfHandler, importHandler, ratingHandler, recommendationHandler, opdsHandler, orderHandler, licenseHandler)
 
 	srv := &http.Server{
 		Handler:      r,
@@ -100,3 +362,192 @@ func main() {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
+
+// newAICache wraps chatRepo with a response cache configured from the
+// environment. AI_CACHE_BACKEND selects "memory" (default), "postgres" or
+// "off"; AI_CACHE_TTL, AI_CACHE_SIZE and AI_CACHE_DISABLE tune it.
+func newAICache(db *sql.DB, chatRepo chat.ChatRepository) *cache.CachedChatRepository {
+	opts := cache.Options{
+		Model:    Gemini.GeminiModelName,
+		TTL:      cache.DefaultTTL,
+		Disabled: cache.ParseDisabled(os.Getenv("AI_CACHE_DISABLE")),
+	}
+	if ttl := os.Getenv("AI_CACHE_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			log.Println("⚠️ Warning: invalid AI_CACHE_TTL, using default:", err)
+		} else {
+			opts.TTL = d
+		}
+	}
+
+	var store cache.Store
+	switch backend := os.Getenv("AI_CACHE_BACKEND"); backend {
+	case "off":
+		log.Println("AI response cache disabled")
+		return nil
+	case "postgres":
+		pgStore := postgres.NewAICacheRepositoryPostgres(db)
+		if err := pgStore.CreateAICacheTable(); err != nil {
+			log.Println("⚠️ Warning: Could not create AI cache table, falling back to memory:", err)
+			break
+		}
+		if n, err := pgStore.DeleteExpired(); err == nil && n > 0 {
+			log.Printf("Pruned %d expired AI cache entries", n)
+		}
+		store = pgStore
+	case "", "memory":
+	default:
+		log.Printf("⚠️ Warning: unknown AI_CACHE_BACKEND %q, using memory", backend)
+	}
+
+	if store == nil {
+		size := 500
+		if v, err := strconv.Atoi(os.Getenv("AI_CACHE_SIZE")); err == nil && v > 0 {
+			size = v
+		}
+		store = cache.NewMemoryStore(size)
+	}
+
+	log.Printf("✅ AI response cache ready (%T, ttl %s)", store, opts.TTL)
+	return cache.NewCachedChatRepository(chatRepo, store, opts)
+}
+
+// grantAdmins gives the admin role to the users listed by ID in
+// ADMIN_USER_IDS, so the first admins can be set up before any admin can
+// promote others. IDs are used rather than emails because users choose
+// their own emails.
+func grantAdmins(repo *postgres.UserRepositoryPostgres) {
+	for _, v := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		id, err := strconv.Atoi(v)
+		if err == nil {
+			err = repo.SetRole(id, identity.RoleAdmin)
+		}
+		if err != nil {
+			log.Printf("⚠️ Warning: Could not make user %s an admin: %v", v, err)
+			continue
+		}
+		log.Printf("✅ User %d is an admin", id)
+	}
+}
+
+// loadBadges reads the achievement definitions from the JSON file named by
+// ACHIEVEMENTS_CONFIG, falling back to the built-in badges.
+func loadBadges() []achievement.Badge {
+	if path := os.Getenv("ACHIEVEMENTS_CONFIG"); path != "" {
+		badges, err := achievement.LoadBadgesFile(path)
+		if err == nil {
+			log.Printf("✅ Loaded %d badges from %s", len(badges), path)
+			return badges
+		}
+		log.Println("⚠️ Warning: Could not load ACHIEVEMENTS_CONFIG, using built-in badges:", err)
+	}
+	badges, err := achievement.DefaultBadges()
+	if err != nil {
+		log.Fatal("❌ Invalid built-in badges:", err)
+	}
+	return badges
+}
+
+// newExternalProvider builds the external book provider named by
+// EXTERNAL_PROVIDER, "google_books" (default) or "open_library", behind an
+// in-memory cache whose lifetime EXTERNAL_PROVIDER_CACHE_TTL sets.
+func newExternalProvider() catalog.ExternalProvider {
+	var provider catalog.ExternalProvider
+	name := os.Getenv("EXTERNAL_PROVIDER")
+	switch name {
+	case "open_library":
+		provider = Gemini.NewOpenLibraryProvider()
+	case "", "google_books":
+		name = "google_books"
+		provider = Gemini.NewGoogleBooksProvider(os.Getenv("GOOGLE_BOOKS_API_KEY"))
+	default:
+		log.Printf("⚠️ Warning: unknown EXTERNAL_PROVIDER %q, using google_books", name)
+		name = "google_books"
+		provider = Gemini.NewGoogleBooksProvider(os.Getenv("GOOGLE_BOOKS_API_KEY"))
+	}
+
+	ttl := cache.DefaultProviderTTL
+	if v := os.Getenv("EXTERNAL_PROVIDER_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Println("⚠️ Warning: invalid EXTERNAL_PROVIDER_CACHE_TTL, using default:", err)
+		} else {
+			ttl = d
+		}
+	}
+	log.Printf("✅ External book provider ready (%s, cache ttl %s)", name, ttl)
+	return cache.NewCachedProvider(provider, name, cache.NewMemoryStore(1000), ttl)
+}
+
+// loadTrendingConfig reads the trending settings from the environment,
+// falling back to the defaults for anything unset or invalid.
+func loadTrendingConfig() trending.Config {
+	cfg := trending.DefaultConfig()
+	durations := map[string]*time.Duration{
+		"TRENDING_HALF_LIFE":        &cfg.HalfLife,
+		"TRENDING_WINDOW":           &cfg.Window,
+		"TRENDING_REFRESH_INTERVAL": &cfg.RefreshInterval,
+	}
+	for name, d := range durations {
+		if v := os.Getenv(name); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				log.Printf("⚠️ Warning: invalid %s, using default: %v", name, err)
+				continue
+			}
+			*d = parsed
+		}
+	}
+	weights := map[string]*float64{
+		"TRENDING_LOCAL_WEIGHT":    &cfg.LocalWeight,
+		"TRENDING_EXTERNAL_WEIGHT": &cfg.ExternalWeight,
+	}
+	for name, w := range weights {
+		if v := os.Getenv(name); v != "" {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				log.Printf("⚠️ Warning: invalid %s, using default: %v", name, err)
+				continue
+			}
+			*w = parsed
+		}
+	}
+	if v := os.Getenv("TRENDING_SUBJECT"); v != "" {
+		cfg.ExternalSubject = v
+	}
+	if v := os.Getenv("TRENDING_SIGNAL_WEIGHTS"); v != "" {
+		signals, err := trending.ParseSignalWeights(v)
+		if err != nil {
+			log.Println("⚠️ Warning: invalid TRENDING_SIGNAL_WEIGHTS, using defaults:", err)
+		} else {
+			cfg.Signals = signals
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Println("⚠️ Warning: invalid trending config, using defaults:", err)
+		return trending.DefaultConfig()
+	}
+	return cfg
+}
+
+// newPaymentGateway returns the payment gateway named by PAYMENT_GATEWAY.
+// Only "fake", which approves every charge without moving money, exists so
+// far; without it checkout leaves orders pending.
+func newPaymentGateway() order.PaymentGateway {
+	switch name := os.Getenv("PAYMENT_GATEWAY"); name {
+	case "fake":
+		log.Println("⚠️ Warning: using the fake payment gateway, no money is charged")
+		return payment.NewFakeGateway()
+	case "":
+		log.Println("⚠️ Warning: no PAYMENT_GATEWAY set, paid books cannot be bought")
+		return nil
+	default:
+		log.Printf("⚠️ Warning: unknown PAYMENT_GATEWAY %q, paid books cannot be bought", name)
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package achievement
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+//go:embed default_badges.json
+var defaultBadges []byte
+
+// DefaultBadges returns the badges shipped with the server.
+func DefaultBadges() ([]Badge, error) {
+	return LoadBadges(bytes.NewReader(defaultBadges))
+}
+
+// LoadBadgesFile reads badge definitions from a JSON file.
+func LoadBadgesFile(path string) ([]Badge, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadBadges(f)
+}
+
+// LoadBadges decodes and validates a JSON array of badges. The returned
+// error wraps ErrInvalidBadgeConfig.
+func LoadBadges(r io.Reader) ([]Badge, error) {
+	var badges []Badge
+	if err := json.NewDecoder(r).Decode(&badges); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBadgeConfig, err)
+	}
+
+	seen := make(map[string]bool, len(badges))
+	for _, b := range badges {
+		switch {
+		case b.ID == "" || b.Name == "":
+			return nil, fmt.Errorf("%w: every badge needs an id and a name", ErrInvalidBadgeConfig)
+		case seen[b.ID]:
+			return nil, fmt.Errorf("%w: duplicate badge id %q", ErrInvalidBadgeConfig, b.ID)
+		case !slices.Contains(KnownMetrics, b.Metric):
+			return nil, fmt.Errorf("%w: badge %q has unknown metric %q", ErrInvalidBadgeConfig, b.ID, b.Metric)
+		case b.Threshold < 1:
+			return nil, fmt.Errorf("%w: badge %q needs a positive threshold", ErrInvalidBadgeConfig, b.ID)
+		}
+		seen[b.ID] = true
+	}
+	return badges, nil
+}
@@ -0,0 +1,56 @@
+package achievement
+
+import "time"
+
+// Metrics a badge rule can test. Each is a running total for one user.
+const (
+	MetricReadingStreak   = "reading_streak"
+	MetricPoints          = "points"
+	MetricBooksFinished   = "books_finished"
+	MetricReadingMinutes  = "reading_minutes"
+	MetricNotesCreated    = "notes_created"
+	MetricAINotesCreated  = "ai_notes_created"
+	MetricQuizzesAnswered = "quizzes_answered"
+	MetricQuizzesAced     = "quizzes_aced"
+	MetricBooksShared     = "books_shared"
+)
+
+// KnownMetrics lists every metric a badge may use.
+var KnownMetrics = []string{
+	MetricReadingStreak, MetricPoints, MetricBooksFinished, MetricReadingMinutes, MetricNotesCreated,
+	MetricAINotesCreated, MetricQuizzesAnswered, MetricQuizzesAced, MetricBooksShared,
+}
+
+// Metrics maps metric names to a user's current values.
+type Metrics map[string]int
+
+// Badge is an achievement definition: it is earned once Metric reaches
+// Threshold.
+type Badge struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon,omitempty"`
+	Metric      string `json:"metric"`
+	Threshold   int    `json:"threshold"`
+}
+
+// Earned reports whether m satisfies the badge's rule.
+func (b Badge) Earned(m Metrics) bool {
+	return m[b.Metric] >= b.Threshold
+}
+
+// Award records when a user earned a badge.
+type Award struct {
+	UserID    int       `json:"user_id"`
+	BadgeID   string    `json:"badge_id"`
+	AwardedAt time.Time `json:"awarded_at"`
+}
+
+// Achievement is a badge as seen by one user, with their progress towards it.
+type Achievement struct {
+	Badge
+	Earned    bool       `json:"earned"`
+	AwardedAt *time.Time `json:"awarded_at,omitempty"`
+	Progress  int        `json:"progress"`
+}
@@ -0,0 +1,7 @@
+package achievement
+
+import "errors"
+
+var (
+	ErrInvalidBadgeConfig = errors.New("invalid badge config")
+)
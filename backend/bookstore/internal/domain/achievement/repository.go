@@ -0,0 +1,13 @@
+package achievement
+
+import "time"
+
+// Repository defines methods for achievement persistence.
+type Repository interface {
+	// GetMetrics computes the user's current value of every known metric.
+	GetMetrics(userID int) (Metrics, error)
+	// Award stores that the user earned a badge. It reports false if the
+	// badge had already been awarded.
+	Award(userID int, badgeID string, at time.Time) (bool, error)
+	ListAwards(userID int) ([]*Award, error)
+}
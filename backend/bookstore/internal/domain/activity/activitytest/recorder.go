@@ -0,0 +1,102 @@
+// Package activitytest provides an in-memory activity.Repository for tests.
+// It follows the Postgres store's rules: one event per user and source key,
+// and points capped per type and day by activity.DailyPointCap.
+package activitytest
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+)
+
+// Recorder is an in-memory activity.Repository. Days are UTC.
+type Recorder struct {
+	mu     sync.Mutex
+	events []*activity.Event
+}
+
+var _ activity.Repository = (*Recorder)(nil)
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Record(e *activity.Event) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+	e.LocalDate = activity.LocalDate(e.OccurredAt, time.UTC)
+	earned := 0
+	for _, prev := range r.events {
+		if prev.UserID != e.UserID {
+			continue
+		}
+		if prev.SourceKey == e.SourceKey {
+			return false, nil
+		}
+		if prev.Type == e.Type && prev.LocalDate.Equal(e.LocalDate) {
+			earned += prev.Points
+		}
+	}
+	if limit, ok := activity.DailyPointCap[e.Type]; ok && e.Points > 0 {
+		e.Points = max(0, min(e.Points, limit-earned))
+	}
+	recorded := *e
+	r.events = append(r.events, &recorded)
+	return true, nil
+}
+
+func (r *Recorder) GetStats(userID int) (*activity.Stats, error) {
+	stats := &activity.Stats{Points: r.Points(userID), Timezone: "UTC"}
+	stats.BooksReadCount = len(r.Events(userID, activity.TypeBookFinished))
+	return stats, nil
+}
+
+func (r *Recorder) GetStreakDays(userID int, limit int) ([]time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := map[time.Time]bool{}
+	var days []time.Time
+	for i := len(r.events) - 1; i >= 0 && len(days) < limit; i-- {
+		e := r.events[i]
+		if e.UserID == userID && e.StreakDay && !seen[e.LocalDate] {
+			seen[e.LocalDate] = true
+			days = append(days, e.LocalDate)
+		}
+	}
+	return days, nil
+}
+
+// Events returns the user's recorded events of the given types, or all of
+// them if no type is given, oldest first.
+func (r *Recorder) Events(userID int, types ...string) []*activity.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []*activity.Event
+	for _, e := range r.events {
+		if e.UserID != userID {
+			continue
+		}
+		if len(types) == 0 || slices.Contains(types, e.Type) {
+			recorded := *e
+			events = append(events, &recorded)
+		}
+	}
+	return events
+}
+
+// Points returns the user's total points.
+func (r *Recorder) Points(userID int) int {
+	total := 0
+	for _, e := range r.Events(userID) {
+		total += e.Points
+	}
+	return total
+}
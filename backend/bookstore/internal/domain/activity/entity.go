@@ -0,0 +1,163 @@
+package activity
+
+import (
+	"math"
+	"time"
+)
+
+// Event types that earn points.
+const (
+	TypeReadingSession = "reading_session"
+	TypeBookFinished   = "book_finished"
+	TypeQuizAnswer     = "quiz_answer"
+	TypeNoteCreated    = "note_created"
+	TypeAINoteCreated  = "ai_note_created"
+	TypeBookShared     = "book_shared"
+	TypeGoalCompleted  = "goal_completed"
+	TypeDailyGoalMet   = "daily_goal_met"
+)
+
+const (
+	// SecondsPerReadingPoint is the reading time worth one point.
+	SecondsPerReadingPoint = 10 * 60
+	BookFinishedPoints     = 50
+	// QuizAnswerMaxPoints is awarded for a fully correct answer.
+	QuizAnswerMaxPoints = 5
+	NotePoints          = 2
+	AINotePoints        = 1
+	BookSharedPoints    = 10
+	GoalCompletedPoints = 100
+	DailyGoalPoints     = 5
+	// MinStreakSeconds is the shortest reading session that keeps a streak going.
+	MinStreakSeconds = 60
+)
+
+// DailyPointCap limits the points one event type can earn per local day, so
+// points cannot be farmed by repeating cheap actions. Types without an entry
+// are uncapped.
+var DailyPointCap = map[string]int{
+	TypeReadingSession: 30,
+	TypeBookFinished:   3 * BookFinishedPoints,
+	TypeQuizAnswer:     50,
+	TypeNoteCreated:    20,
+	TypeAINoteCreated:  10,
+	TypeBookShared:     30,
+	TypeGoalCompleted:  GoalCompletedPoints,
+	TypeDailyGoalMet:   10,
+}
+
+// Event is something a user did that earns points or counts towards their
+// reading streak. SourceKey identifies what the event is about, so the same
+// book, note or question is only ever rewarded once.
+type Event struct {
+	ID        string `json:"id"`
+	UserID    int    `json:"user_id"`
+	Type      string `json:"type"`
+	SourceKey string `json:"source_key"`
+	Points    int    `json:"points"`
+	// Value is the measured amount: seconds for reading sessions and the
+	// score in percent for quiz answers.
+	Value      int       `json:"value"`
+	StreakDay  bool      `json:"streak_day"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// LocalDate is the day, in the user's timezone, the event happened on.
+	LocalDate time.Time `json:"local_date"`
+}
+
+// Stats are a user's server-computed counters.
+type Stats struct {
+	Points         int        `json:"points"`
+	BooksReadCount int        `json:"books_read_count"`
+	ReadingStreak  int        `json:"reading_streak"`
+	LastReadDate   *time.Time `json:"last_read_date,omitempty"`
+	Timezone       string     `json:"timezone"`
+}
+
+// ReadingSession is seconds of reading in a book.
+func ReadingSession(userID int, sourceKey string, seconds int64, at time.Time) *Event {
+	return &Event{
+		UserID:     userID,
+		Type:       TypeReadingSession,
+		SourceKey:  sourceKey,
+		Points:     int(seconds / SecondsPerReadingPoint),
+		Value:      int(seconds),
+		StreakDay:  seconds >= MinStreakSeconds,
+		OccurredAt: at,
+	}
+}
+
+// BookFinished is the first time a user finishes a book.
+func BookFinished(userID int, bookID string, at time.Time) *Event {
+	return &Event{
+		UserID:     userID,
+		Type:       TypeBookFinished,
+		SourceKey:  "book_finished:" + bookID,
+		Points:     BookFinishedPoints,
+		StreakDay:  true,
+		OccurredAt: at,
+	}
+}
+
+// QuizAnswer is a server-graded answer with a score between 0 and 1.
+func QuizAnswer(userID int, sourceKey string, score float64, at time.Time) *Event {
+	score = math.Max(0, math.Min(1, score))
+	return &Event{
+		UserID:     userID,
+		Type:       TypeQuizAnswer,
+		SourceKey:  sourceKey,
+		Points:     int(math.Round(score * QuizAnswerMaxPoints)),
+		Value:      int(math.Round(score * 100)),
+		OccurredAt: at,
+	}
+}
+
+// NoteCreated is a new note; AI-generated notes earn less.
+func NoteCreated(userID int, noteID string, aiGenerated bool, at time.Time) *Event {
+	eventType, points := TypeNoteCreated, NotePoints
+	if aiGenerated {
+		eventType, points = TypeAINoteCreated, AINotePoints
+	}
+	return &Event{
+		UserID:     userID,
+		Type:       eventType,
+		SourceKey:  "note:" + noteID,
+		Points:     points,
+		OccurredAt: at,
+	}
+}
+
+// BookShared is a book the user uploaded to the catalog.
+func BookShared(userID int, bookID string, at time.Time) *Event {
+	return &Event{
+		UserID:     userID,
+		Type:       TypeBookShared,
+		SourceKey:  "book_shared:" + bookID,
+		Points:     BookSharedPoints,
+		OccurredAt: at,
+	}
+}
+
+// GoalCompleted is a books goal reached for the first time. It is keyed on
+// the goal's period rather than the goal, so deleting a goal and setting
+// the same one again cannot be rewarded twice.
+func GoalCompleted(userID int, from, to time.Time, at time.Time) *Event {
+	return &Event{
+		UserID:     userID,
+		Type:       TypeGoalCompleted,
+		SourceKey:  "goal:books:" + from.Format(time.DateOnly) + ":" + to.Format(time.DateOnly),
+		Points:     GoalCompletedPoints,
+		OccurredAt: at,
+	}
+}
+
+// DailyGoalMet is a daily reading goal met on the local date day. However
+// many daily goals the user has, meeting them earns points once a day.
+func DailyGoalMet(userID int, day time.Time, at time.Time) *Event {
+	return &Event{
+		UserID:     userID,
+		Type:       TypeDailyGoalMet,
+		SourceKey:  "daily_goal:" + day.Format(time.DateOnly),
+		Points:     DailyGoalPoints,
+		OccurredAt: at,
+	}
+}
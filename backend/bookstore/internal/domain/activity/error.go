@@ -0,0 +1,7 @@
+package activity
+
+import "errors"
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+)
@@ -0,0 +1,15 @@
+package activity
+
+import "time"
+
+// Repository records activity and keeps the user's counters in step with it.
+type Repository interface {
+	// Record stores e, adds its points to the user, capped by DailyPointCap,
+	// and updates the reading streak for streak days. It reports false if an
+	// event with the same source key was already recorded.
+	Record(e *Event) (bool, error)
+	GetStats(userID int) (*Stats, error)
+	// GetStreakDays returns the user's most recent distinct streak days,
+	// most recent first.
+	GetStreakDays(userID int, limit int) ([]time.Time, error)
+}
@@ -0,0 +1,48 @@
+package activity
+
+import "time"
+
+// GraceDays is how many days in a row a reader may miss without losing
+// their streak. Missed days do not count towards the streak.
+const GraceDays = 1
+
+// Location loads an IANA timezone, falling back to UTC when it is empty or
+// unknown.
+func Location(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LocalDate returns the calendar day of t in loc, as midnight UTC.
+func LocalDate(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Streak counts the active days in the run that ends on or shortly before
+// today. days are distinct local dates, most recent first. The run is
+// broken by a gap of more than graceDays missed days, including the gap
+// between the last active day and today.
+func Streak(days []time.Time, today time.Time, graceDays int) int {
+	if len(days) == 0 || daysBetween(days[0], today)-1 > graceDays {
+		return 0
+	}
+	streak := 1
+	for i := 1; i < len(days); i++ {
+		if daysBetween(days[i], days[i-1])-1 > graceDays {
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
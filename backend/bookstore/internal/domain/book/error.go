@@ -10,4 +10,5 @@ var (
 	ErrBookAlreadyExists  = errors.New("book already exists")
 	ErrInvalidBookInput   = errors.New("invalid book input")
 	ErrBookInternal       = errors.New("internal server error")
+	ErrInvalidISBN        = errors.New("invalid ISBN")
 )
\ No newline at end of file
@@ -0,0 +1,96 @@
+package book
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseISBN validates an ISBN-10 or ISBN-13, ignoring hyphens and spaces,
+// and returns both forms. Books with a 979 prefix have no ISBN-10, so
+// isbn10 is empty for them. The returned error wraps ErrInvalidISBN.
+func ParseISBN(s string) (isbn10, isbn13 string, err error) {
+	s = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(s)))
+	switch len(s) {
+	case 10:
+		if !validISBN10(s) {
+			return "", "", fmt.Errorf("%w: %q has a bad check digit", ErrInvalidISBN, s)
+		}
+		return s, isbn10To13(s), nil
+	case 13:
+		if !validISBN13(s) {
+			return "", "", fmt.Errorf("%w: %q has a bad check digit", ErrInvalidISBN, s)
+		}
+		return isbn13To10(s), s, nil
+	default:
+		return "", "", fmt.Errorf("%w: %q must have 10 or 13 digits", ErrInvalidISBN, s)
+	}
+}
+
+// validISBN10 checks the mod-11 checksum. The last character may be X,
+// standing for 10.
+func validISBN10(s string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var d int
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			d = int(c - '0')
+		case c == 'X' && i == 9:
+			d = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * d
+	}
+	return sum%11 == 0
+}
+
+// validISBN13 checks the EAN-13 checksum: digits weighted 1 and 3 in turn
+// must sum to a multiple of 10.
+func validISBN13(s string) bool {
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 13; i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func isbn10To13(s string) string {
+	body := "978" + s[:9]
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return fmt.Sprintf("%s%d", body, (10-sum%10)%10)
+}
+
+func isbn13To10(s string) string {
+	if !strings.HasPrefix(s, "978") {
+		return ""
+	}
+	body := s[3:12]
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += (10 - i) * int(body[i]-'0')
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return body + "X"
+	}
+	return fmt.Sprintf("%s%d", body, check)
+}
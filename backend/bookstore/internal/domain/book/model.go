@@ -1,18 +1,84 @@
 package book
 
+import (
+	"strings"
+	"time"
+)
+
+// Book is a catalog entry. Rating and RatingCount are the average and number
+// of visible user reviews; they are maintained by the review store. Price is
+// nil when it is unknown, as it is for most external books.
+//
+// Source attributes the book: SourceUpload for books users uploaded, or the
+// external provider it was imported from, with SourceID the provider's ID.
+// UploadedBy is the uploader's user ID, 0 if unknown.
+//
+// Owned is set on listings for the authenticated caller: true if they may
+// read and download the book, because it is free or they are entitled to it.
 type Book struct {
-	ID         string  `json:"id"`
-	Title      string  `json:"title"`
-	Author     string  `json:"author"`
-	Price      float32 `json:"price"`
-	Rating     float32 `json:"rating"`
-	Category   string  `json:"category"`
-	IsFeatured bool    `json:"is_featured"`
-	SharedBy   string  `json:"shared_by"`
-	Tag        string  `json:"tag"`
-	CoverUrl   string  `json:"cover_url"`
-	BookURL    string  `json:"book_url"`
-	IsExternal bool    `json:"is_external"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Author      string    `json:"author"`
+	Price       *float32  `json:"price"`
+	Rating      float32   `json:"rating"`
+	RatingCount int       `json:"rating_count"`
+	Category    string    `json:"category"`
+	IsFeatured  bool      `json:"is_featured"`
+	SharedBy    string    `json:"shared_by"`
+	Tag         string    `json:"tag"`
+	CoverUrl    string    `json:"cover_url"`
+	BookURL     string    `json:"book_url"`
+	IsExternal  bool      `json:"is_external"`
+	ISBN10      string    `json:"isbn10,omitempty"`
+	ISBN13      string    `json:"isbn13,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	SourceID    string    `json:"source_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+	UploadedBy  int       `json:"uploaded_by,omitempty"`
+	Owned       *bool     `json:"owned,omitempty"`
+}
+
+// SourceUpload marks books uploaded by users.
+const SourceUpload = "upload"
+
+// Listing sort orders. SortRating ranks by a damped average, so a book with
+// a single five-star review does not outrank one with hundreds of fours.
+// SortNewest lists the most recently added books first.
+const (
+	SortDefault = ""
+	SortTitle   = "title"
+	SortRating  = "rating"
+	SortNewest  = "newest"
+)
+
+// ValidSort reports whether sort is a supported listing order.
+func ValidSort(sort string) bool {
+	return sort == SortDefault || sort == SortTitle || sort == SortRating || sort == SortNewest
+}
+
+// Filter narrows a book listing. Zero fields do not filter and a zero
+// Limit means no limit. Query matches title, author and ISBN, and Title
+// matches the whole title as compared by NormalizeTitle.
+type Filter struct {
+	Query    string
+	Title    string
+	Category string
+	Featured bool
+	Sort     string
+	Limit    int
+	Offset   int
+}
+
+// NormalizeTitle lower-cases a title and collapses its whitespace, so
+// titles typed by users match the catalog's.
+func NormalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// Category is a catalog category with the number of books in it.
+type Category struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
 }
 
 func NewBook(id, title, author string, price float32, coverURL, bookURL string, rating float32, category string, isFeatured bool, sharedBy string, tag string) *Book {
@@ -20,7 +86,7 @@ func NewBook(id, title, author string, price float32, coverURL, bookURL string,
 		ID:         id,
 		Title:      title,
 		Author:     author,
-		Price:      price,
+		Price:      &price,
 		CoverUrl:   coverURL,
 		BookURL:    bookURL,
 		Rating:     rating,
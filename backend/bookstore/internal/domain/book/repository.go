@@ -7,4 +7,12 @@ type BookRepository interface{
 	UpdateBook(book *Book) (*Book, error)
 	DeleteBook(id string) error
 	GetAllBooks() ([]*Book, error)
+	ListBooks(sort string) ([]*Book, error)
+	FindBooks(filter Filter) ([]*Book, error)
+	ListCategories() ([]Category, error)
+	// GetBookByISBN, GetBookBySource and GetBookByTitle return nil if no
+	// book matches. Titles are compared by NormalizeTitle.
+	GetBookByISBN(isbn13 string) (*Book, error)
+	GetBookByTitle(title string) (*Book, error)
+	GetBookBySource(source, sourceID string) (*Book, error)
 }
@@ -0,0 +1,49 @@
+package catalog
+
+import "strings"
+
+// External sources. Shelf items and imported books record which provider
+// they came from with these.
+const (
+	SourceGoogleBooks = "google_books"
+	SourceOpenLibrary = "open_library"
+)
+
+// ExternalBook is a book as described by an external provider such as
+// Google Books.
+type ExternalBook struct {
+	Source        string   `json:"source"`
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Authors       []string `json:"authors"`
+	Description   string   `json:"description,omitempty"`
+	Categories    []string `json:"categories,omitempty"`
+	PublishedDate string   `json:"published_date,omitempty"`
+	ISBN10        string   `json:"isbn10,omitempty"`
+	ISBN13        string   `json:"isbn13,omitempty"`
+	CoverUrl      string   `json:"cover_url,omitempty"`
+	PreviewURL    string   `json:"preview_url,omitempty"`
+	Rating        float32  `json:"rating,omitempty"`
+	// Price is nil when the provider does not sell the book or does not
+	// say what it costs.
+	Price *Price `json:"price,omitempty"`
+}
+
+// Price is a list price in a currency's major unit.
+type Price struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// Author returns the book's authors as one display string.
+func (b *ExternalBook) Author() string {
+	return strings.Join(b.Authors, ", ")
+}
+
+// ISBN returns the ISBN-13 if known, else the ISBN-10.
+func (b *ExternalBook) ISBN() string {
+	if b.ISBN13 != "" {
+		return b.ISBN13
+	}
+	return b.ISBN10
+}
@@ -0,0 +1,10 @@
+package catalog
+
+import "errors"
+
+var (
+	ErrExternalNotFound = errors.New("book not found at the external provider")
+	// ErrExternalUnavailable means the provider could not be reached or kept
+	// failing after retries.
+	ErrExternalUnavailable = errors.New("external book provider unavailable")
+)
@@ -0,0 +1,18 @@
+package catalog
+
+import "context"
+
+// ExternalProvider looks books up in an external catalog.
+type ExternalProvider interface {
+	// LookupISBN returns ErrExternalNotFound if the provider has no book
+	// with that ISBN.
+	LookupISBN(ctx context.Context, isbn string) (*ExternalBook, error)
+	// Lookup returns the book with the provider's own ID, or
+	// ErrExternalNotFound.
+	Lookup(ctx context.Context, id string) (*ExternalBook, error)
+	// Search returns up to limit books matching a free-text query.
+	Search(ctx context.Context, query string, limit int) ([]*ExternalBook, error)
+	// Trending returns up to limit notable recent books on a subject such
+	// as "fiction".
+	Trending(ctx context.Context, subject string, limit int) ([]*ExternalBook, error)
+}
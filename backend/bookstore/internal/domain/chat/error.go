@@ -3,9 +3,17 @@ package chat
 import "errors"
 
 var (
-	ErrChatNotFound      = errors.New("chat not found")
-	ErrInvalidChatID     = errors.New("invalid chat ID")
-	ErrChatAlreadyExists = errors.New("chat already exists")
-	ErrInvalidChatInput  = errors.New("invalid chat input")
-	ErrChatInternal      = errors.New("internal server error")
-)
\ No newline at end of file
+	ErrChatNotFound       = errors.New("chat not found")
+	ErrInvalidChatID      = errors.New("invalid chat ID")
+	ErrChatAlreadyExists  = errors.New("chat already exists")
+	ErrInvalidChatInput   = errors.New("invalid chat input")
+	ErrChatInternal       = errors.New("internal server error")
+	ErrInvalidQuizOptions = errors.New("invalid quiz options")
+	ErrInvalidQuizItem    = errors.New("invalid quiz item")
+	ErrQuestionNotFound   = errors.New("question not found")
+
+	// Errors returned by ChatRepository implementations backed by an AI model.
+	ErrAIRateLimited       = errors.New("free tier limit reached. Please wait 1 minute before trying again")
+	ErrAIUnavailable       = errors.New("AI service unavailable")
+	ErrAIMalformedResponse = errors.New("AI returned a malformed response")
+)
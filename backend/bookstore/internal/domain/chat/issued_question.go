@@ -0,0 +1,17 @@
+package chat
+
+import "time"
+
+// IssuedQuestion is a short-answer question the server gave a user, kept so
+// their answer is graded against the server's reference answer. The user
+// only sees the reference answer and explanation once they have answered.
+// Only answers to issued questions earn points.
+type IssuedQuestion struct {
+	ID              string    `json:"id"`
+	UserID          int       `json:"user_id"`
+	BookName        string    `json:"book_name"`
+	Question        string    `json:"question"`
+	ReferenceAnswer string    `json:"reference_answer"`
+	Explanation     string    `json:"explanation"`
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -1,9 +1,62 @@
 package chat
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 type MultipleQuiz struct {
-	ID            int   `json:"id"`
-	Question      string   `json:"question"`
-	Options       []string `json:"options"`
-	CorrectIndex int   `json:"correct_index"`
-	Explanation  string `json:"explanation"`
+	ID           int      `json:"id"`
+	Question     string   `json:"question"`
+	Options      []string `json:"options"`
+	CorrectIndex int      `json:"correct_index"`
+	Explanation  string   `json:"explanation"`
+}
+
+// UnmarshalJSON accepts both "correct_index" and the "correct_answer_index"
+// key the quiz prompt asks the model for.
+func (q *MultipleQuiz) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID                 int      `json:"id"`
+		Question           string   `json:"question"`
+		Options            []string `json:"options"`
+		CorrectIndex       *int     `json:"correct_index"`
+		CorrectAnswerIndex *int     `json:"correct_answer_index"`
+		Explanation        string   `json:"explanation"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	q.ID = raw.ID
+	q.Question = raw.Question
+	q.Options = raw.Options
+	q.Explanation = raw.Explanation
+	q.CorrectIndex = -1
+	if raw.CorrectAnswerIndex != nil {
+		q.CorrectIndex = *raw.CorrectAnswerIndex
+	} else if raw.CorrectIndex != nil {
+		q.CorrectIndex = *raw.CorrectIndex
+	}
+	return nil
+}
+
+// Validate reports whether the quiz is complete enough to show to a reader.
+func (q *MultipleQuiz) Validate() error {
+	if strings.TrimSpace(q.Question) == "" {
+		return fmt.Errorf("%w: empty question", ErrInvalidQuizItem)
+	}
+	if len(q.Options) < 2 {
+		return fmt.Errorf("%w: need at least 2 options, got %d", ErrInvalidQuizItem, len(q.Options))
+	}
+	for _, o := range q.Options {
+		if strings.TrimSpace(o) == "" {
+			return fmt.Errorf("%w: empty option", ErrInvalidQuizItem)
+		}
+	}
+	if q.CorrectIndex < 0 || q.CorrectIndex >= len(q.Options) {
+		return fmt.Errorf("%w: correct index %d out of range", ErrInvalidQuizItem, q.CorrectIndex)
+	}
+	return nil
 }
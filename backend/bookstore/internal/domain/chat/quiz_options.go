@@ -0,0 +1,96 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultQuizCount    = 10
+	MinQuizCount        = 1
+	MaxQuizCount        = 20
+	DefaultQuizLanguage = "English"
+	maxQuizTopicLength  = 200
+	maxQuizLanguageLen  = 40
+)
+
+// Supported quiz difficulties. Adaptive quizzes ramp from easy to hard.
+const (
+	DifficultyEasy     = "easy"
+	DifficultyMedium   = "medium"
+	DifficultyHard     = "hard"
+	DifficultyAdaptive = "adaptive"
+)
+
+// QuizOptions controls how a quiz is generated. It is shared by the
+// multiple-choice, true/false and short-answer generators.
+type QuizOptions struct {
+	Count        int    `json:"count"`
+	Difficulty   string `json:"difficulty"`
+	ChapterStart int    `json:"chapter_start"`
+	ChapterEnd   int    `json:"chapter_end"`
+	PageStart    int    `json:"page_start"`
+	PageEnd      int    `json:"page_end"`
+	Topic        string `json:"topic"`
+	Language     string `json:"language"`
+}
+
+// Normalize fills in defaults and validates the options in place.
+// The returned error wraps ErrInvalidQuizOptions.
+func (o *QuizOptions) Normalize() error {
+	if o.Count == 0 {
+		o.Count = DefaultQuizCount
+	}
+	if o.Count < MinQuizCount || o.Count > MaxQuizCount {
+		return fmt.Errorf("%w: count must be between %d and %d", ErrInvalidQuizOptions, MinQuizCount, MaxQuizCount)
+	}
+
+	o.Difficulty = strings.ToLower(strings.TrimSpace(o.Difficulty))
+	switch o.Difficulty {
+	case "":
+		o.Difficulty = DifficultyMedium
+	case DifficultyEasy, DifficultyMedium, DifficultyHard, DifficultyAdaptive:
+	default:
+		return fmt.Errorf("%w: difficulty must be one of easy, medium, hard, adaptive", ErrInvalidQuizOptions)
+	}
+
+	if err := validateRange("chapter", o.ChapterStart, o.ChapterEnd); err != nil {
+		return err
+	}
+	if err := validateRange("page", o.PageStart, o.PageEnd); err != nil {
+		return err
+	}
+	if o.ChapterEnd == 0 {
+		o.ChapterEnd = o.ChapterStart
+	}
+	if o.PageEnd == 0 {
+		o.PageEnd = o.PageStart
+	}
+
+	o.Topic = strings.TrimSpace(o.Topic)
+	if len(o.Topic) > maxQuizTopicLength {
+		return fmt.Errorf("%w: topic must be at most %d characters", ErrInvalidQuizOptions, maxQuizTopicLength)
+	}
+
+	o.Language = strings.TrimSpace(o.Language)
+	if o.Language == "" {
+		o.Language = DefaultQuizLanguage
+	}
+	if len(o.Language) > maxQuizLanguageLen {
+		return fmt.Errorf("%w: language must be at most %d characters", ErrInvalidQuizOptions, maxQuizLanguageLen)
+	}
+	return nil
+}
+
+func validateRange(name string, start, end int) error {
+	if start < 0 || end < 0 {
+		return fmt.Errorf("%w: %s range must not be negative", ErrInvalidQuizOptions, name)
+	}
+	if end != 0 && start == 0 {
+		return fmt.Errorf("%w: %s_end requires %s_start", ErrInvalidQuizOptions, name, name)
+	}
+	if end != 0 && end < start {
+		return fmt.Errorf("%w: %s_end must not be before %s_start", ErrInvalidQuizOptions, name, name)
+	}
+	return nil
+}
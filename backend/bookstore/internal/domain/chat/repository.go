@@ -8,6 +8,14 @@ type ChatRepository interface {
 	GetMultipleChoiceQuestion(id string, bookName string) ([]*MultipleQuiz, error)
 	GetTrueFalseQuestion(id string, bookName string) ([]*TrueFalse, error)
 	GetShortAnswerQuestion(id string, bookName string) ([]*ShortAnswer, error)
+	GradeShortAnswer(id string, prompt string) (*ShortAnswerGrade, error)
 	GetChatResponses(chatID int, prompt string) (*ChatResponse, error)
 	GetChatResponseStream(ctx context.Context, prompt string) (<-chan string, error)
 }
+
+// QuestionRepository stores the questions issued to users.
+type QuestionRepository interface {
+	Issue(q *IssuedQuestion) (*IssuedQuestion, error)
+	// GetIssued returns the user's issued question or ErrQuestionNotFound.
+	GetIssued(id string, userID int) (*IssuedQuestion, error)
+}
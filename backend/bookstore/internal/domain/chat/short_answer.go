@@ -1,8 +1,29 @@
 package chat
 
+import (
+	"fmt"
+	"strings"
+)
+
+// ShortAnswer is a generated short-answer question. QuestionID is set once
+// the question has been issued to a user, and is what they answer it by;
+// issued questions carry no answer or explanation, which are only returned
+// with the grade.
 type ShortAnswer struct {
-	ID            int `json:"id"`
+	ID            int    `json:"id"`
+	QuestionID    string `json:"question_id,omitempty"`
 	Question      string `json:"question"`
-	CorrectAnswer string `json:"answer"`
-	Explanation   string `json:"explanation"`
-}
\ No newline at end of file
+	CorrectAnswer string `json:"answer,omitempty"`
+	Explanation   string `json:"explanation,omitempty"`
+}
+
+// Validate reports whether the quiz is complete enough to show to a reader.
+func (q *ShortAnswer) Validate() error {
+	if strings.TrimSpace(q.Question) == "" {
+		return fmt.Errorf("%w: empty question", ErrInvalidQuizItem)
+	}
+	if strings.TrimSpace(q.CorrectAnswer) == "" {
+		return fmt.Errorf("%w: empty answer", ErrInvalidQuizItem)
+	}
+	return nil
+}
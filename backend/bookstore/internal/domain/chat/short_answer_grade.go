@@ -0,0 +1,20 @@
+package chat
+
+// Verdicts returned when grading a learner's short answer.
+const (
+	VerdictCorrect   = "correct"
+	VerdictPartial   = "partial"
+	VerdictIncorrect = "incorrect"
+)
+
+// ShortAnswerGrade is the result of comparing a learner's answer to the
+// reference answer of a ShortAnswer question. Grades of issued questions
+// reveal the reference answer and its explanation.
+type ShortAnswerGrade struct {
+	Score       float64 `json:"score"`
+	Verdict     string  `json:"verdict"`
+	Feedback    string  `json:"feedback"`
+	GradedBy    string  `json:"graded_by"`
+	Answer      string  `json:"answer,omitempty"`
+	Explanation string  `json:"explanation,omitempty"`
+}
@@ -1,9 +1,52 @@
 package chat
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 type TrueFalse struct {
-	ID       int `json:"id"`
-	Question string `json:"question"`
-	Answer   string   `json:"answer"`
+	ID          int    `json:"id"`
+	Question    string `json:"question"`
+	Answer      string `json:"answer"`
 	Explanation string `json:"explanation"`
+}
+
+// UnmarshalJSON accepts the answer either as a JSON boolean or as a string
+// and normalizes it to "true" or "false".
+func (q *TrueFalse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID          int    `json:"id"`
+		Question    string `json:"question"`
+		Answer      any    `json:"answer"`
+		Explanation string `json:"explanation"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	q.ID = raw.ID
+	q.Question = raw.Question
+	q.Explanation = raw.Explanation
+	switch v := raw.Answer.(type) {
+	case bool:
+		q.Answer = fmt.Sprint(v)
+	case string:
+		q.Answer = strings.ToLower(strings.TrimSpace(v))
+	default:
+		q.Answer = ""
+	}
+	return nil
+}
 
-}
\ No newline at end of file
+// Validate reports whether the quiz is complete enough to show to a reader.
+func (q *TrueFalse) Validate() error {
+	if strings.TrimSpace(q.Question) == "" {
+		return fmt.Errorf("%w: empty question", ErrInvalidQuizItem)
+	}
+	if q.Answer != "true" && q.Answer != "false" {
+		return fmt.Errorf("%w: answer must be true or false, got %q", ErrInvalidQuizItem, q.Answer)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package entitlement
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reasons a user may read a book, in the order they are checked.
+const (
+	ReasonAdmin     = "admin"
+	ReasonFree      = "free"
+	ReasonUploader  = "uploader"
+	ReasonPurchased = "purchased"
+	ReasonLicense   = "license"
+)
+
+// Decision says whether a user may read and download a book, and why.
+// Reason is empty when access is denied.
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func Allow(reason string) Decision {
+	return Decision{Allowed: true, Reason: reason}
+}
+
+// License is an institutional license: its members may read the books in
+// Category, or every book if Category is empty, until ExpiresAt. Members are
+// added by admins. EmailDomain names the institution's domain for admins
+// vetting members; it grants nothing by itself, as users choose their own
+// emails and nothing verifies them.
+type License struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	EmailDomain string     `json:"email_domain"`
+	Category    string     `json:"category"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Normalize trims and lower-cases the license's fields and checks them. The
+// returned error wraps ErrInvalidLicense.
+func (l *License) Normalize(now time.Time) error {
+	l.Name = strings.TrimSpace(l.Name)
+	l.EmailDomain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(l.EmailDomain), "@"))
+	l.Category = strings.TrimSpace(l.Category)
+	if l.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidLicense)
+	}
+	if l.EmailDomain == "" || strings.ContainsAny(l.EmailDomain, "@ ") || !strings.Contains(l.EmailDomain, ".") {
+		return fmt.Errorf("%w: email_domain must be a domain such as example.edu", ErrInvalidLicense)
+	}
+	if l.ExpiresAt != nil && !l.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidLicense)
+	}
+	return nil
+}
+
+// LicenseMember is a user an admin added to an institutional license.
+type LicenseMember struct {
+	LicenseID string    `json:"license_id"`
+	UserID    int       `json:"user_id"`
+	AddedAt   time.Time `json:"added_at"`
+}
@@ -0,0 +1,10 @@
+package entitlement
+
+import "errors"
+
+var (
+	ErrNotEntitled     = errors.New("buy this book to read it")
+	ErrLicenseNotFound = errors.New("license not found")
+	ErrInvalidLicense  = errors.New("invalid license")
+	ErrMemberNotFound  = errors.New("user is not a member of this license")
+)
@@ -0,0 +1,26 @@
+package entitlement
+
+// Repository answers which books a user holds rights to and stores
+// institutional licenses.
+type Repository interface {
+	// Purchased returns the subset of bookIDs the user has bought in a paid
+	// order.
+	Purchased(userID int, bookIDs []string) (map[string]bool, error)
+	// Licensed returns the subset of bookIDs the user may read under an
+	// unexpired institutional license they are a member of.
+	Licensed(userID int, bookIDs []string) (map[string]bool, error)
+
+	CreateLicense(l *License) (*License, error)
+	ListLicenses() ([]*License, error)
+	// DeleteLicense returns ErrLicenseNotFound if there is no such license.
+	DeleteLicense(id string) error
+
+	// AddLicenseMember is a no-op if the user is already a member. It and
+	// ListLicenseMembers return ErrLicenseNotFound if there is no such
+	// license.
+	AddLicenseMember(licenseID string, userID int) (*LicenseMember, error)
+	// RemoveLicenseMember returns ErrMemberNotFound if the user is not a
+	// member.
+	RemoveLicenseMember(licenseID string, userID int) error
+	ListLicenseMembers(licenseID string) ([]*LicenseMember, error)
+}
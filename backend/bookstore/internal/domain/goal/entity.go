@@ -0,0 +1,149 @@
+package goal
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Goal types.
+const (
+	// TypeBooks is finishing Target books within the goal's period.
+	TypeBooks = "books"
+	// TypeDailyMinutes is reading Target minutes on every day of the period.
+	TypeDailyMinutes = "daily_minutes"
+)
+
+// Goal statuses, derived from the period and progress.
+const (
+	StatusUpcoming  = "upcoming"
+	StatusActive    = "active"
+	StatusCompleted = "completed"
+	StatusEnded     = "ended"
+)
+
+const (
+	MaxBooksTarget   = 1000
+	MaxMinutesTarget = 24 * 60
+	// MaxPeriodDays bounds a goal's period to a little over a leap year.
+	MaxPeriodDays = 366
+)
+
+// Goal is a reading target over a period of days. StartDate and EndDate are
+// local dates in the user's timezone, stored as midnight UTC like
+// activity.Event.LocalDate; both are inclusive.
+type Goal struct {
+	ID          string     `json:"id"`
+	UserID      int        `json:"user_id"`
+	Type        string     `json:"type"`
+	Target      int        `json:"target"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     time.Time  `json:"end_date"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Progress is how far a goal is along and whether the user is keeping pace.
+// For TypeBooks, Current counts finished books and Required is Target. For
+// TypeDailyMinutes, Current counts the days the target was met and Required
+// is the number of days in the period.
+type Progress struct {
+	Goal     *Goal   `json:"goal"`
+	Status   string  `json:"status"`
+	Current  int     `json:"current"`
+	Required int     `json:"required"`
+	Percent  float64 `json:"percent"`
+	// Expected is where an even pace would have the user by today.
+	Expected int  `json:"expected"`
+	OnPace   bool `json:"on_pace"`
+	// Projected is the total reached by the end of the period at the
+	// current pace.
+	Projected int `json:"projected"`
+	DaysLeft  int `json:"days_left"`
+	// TodayMinutes is only set for TypeDailyMinutes.
+	TodayMinutes *int `json:"today_minutes,omitempty"`
+}
+
+// YearPeriod returns the first and last day of year.
+func YearPeriod(year int) (time.Time, time.Time) {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+// Normalize validates g, defaulting the period to the calendar year that
+// contains today. The returned error wraps ErrInvalidGoal.
+func (g *Goal) Normalize(today time.Time) error {
+	switch g.Type {
+	case TypeBooks:
+		if g.Target < 1 || g.Target > MaxBooksTarget {
+			return fmt.Errorf("%w: target must be between 1 and %d books", ErrInvalidGoal, MaxBooksTarget)
+		}
+	case TypeDailyMinutes:
+		if g.Target < 1 || g.Target > MaxMinutesTarget {
+			return fmt.Errorf("%w: target must be between 1 and %d minutes", ErrInvalidGoal, MaxMinutesTarget)
+		}
+	default:
+		return fmt.Errorf("%w: type must be books or daily_minutes", ErrInvalidGoal)
+	}
+
+	if g.StartDate.IsZero() && g.EndDate.IsZero() {
+		g.StartDate, g.EndDate = YearPeriod(today.Year())
+	}
+	if g.StartDate.IsZero() || g.EndDate.IsZero() {
+		return fmt.Errorf("%w: start_date and end_date must be given together", ErrInvalidGoal)
+	}
+	if g.EndDate.Before(g.StartDate) {
+		return fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidGoal)
+	}
+	if g.Days() > MaxPeriodDays {
+		return fmt.Errorf("%w: a goal may span at most %d days", ErrInvalidGoal, MaxPeriodDays)
+	}
+	if g.EndDate.Before(today) {
+		return fmt.Errorf("%w: end_date is in the past", ErrInvalidGoal)
+	}
+	return nil
+}
+
+// Days returns the number of days in the goal's period.
+func (g *Goal) Days() int {
+	return daysBetween(g.StartDate, g.EndDate) + 1
+}
+
+// Contains reports whether the local date day is within the period.
+func (g *Goal) Contains(day time.Time) bool {
+	return !day.Before(g.StartDate) && !day.After(g.EndDate)
+}
+
+// Compute measures current against the goal on the local date today.
+func (g *Goal) Compute(current int, today time.Time) *Progress {
+	p := &Progress{Goal: g, Current: current, Required: g.Target}
+	if g.Type == TypeDailyMinutes {
+		p.Required = g.Days()
+	}
+	p.Percent = math.Min(100, math.Round(float64(current)/float64(p.Required)*1000)/10)
+
+	total := g.Days()
+	elapsed := min(max(daysBetween(g.StartDate, today)+1, 0), total)
+	p.DaysLeft = total - elapsed
+	p.Expected = p.Required * elapsed / total
+	if elapsed > 0 {
+		p.Projected = int(math.Round(float64(current) * float64(total) / float64(elapsed)))
+	}
+	p.OnPace = current >= p.Expected
+
+	switch {
+	case g.CompletedAt != nil || (g.Type == TypeBooks && current >= g.Target):
+		p.Status = StatusCompleted
+	case today.Before(g.StartDate):
+		p.Status = StatusUpcoming
+	case today.After(g.EndDate):
+		p.Status = StatusEnded
+	default:
+		p.Status = StatusActive
+	}
+	return p
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
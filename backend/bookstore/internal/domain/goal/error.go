@@ -0,0 +1,8 @@
+package goal
+
+import "errors"
+
+var (
+	ErrGoalNotFound = errors.New("goal not found")
+	ErrInvalidGoal  = errors.New("invalid goal")
+)
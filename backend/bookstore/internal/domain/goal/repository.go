@@ -0,0 +1,20 @@
+package goal
+
+import "time"
+
+// Repository defines methods for goal persistence and for reading the
+// progress data goals are measured against. Dates are local dates as in
+// Goal.
+type Repository interface {
+	Create(g *Goal) (*Goal, error)
+	GetByUserID(userID int) ([]*Goal, error)
+	// Delete returns ErrGoalNotFound unless the user owns the goal.
+	Delete(userID int, id string) error
+	MarkCompleted(id string, at time.Time) error
+	// CountFinishedBooks counts the books the user finished between from
+	// and to, inclusive.
+	CountFinishedBooks(userID int, from, to time.Time) (int, error)
+	// ReadingSecondsByDay sums the user's reading time per day between
+	// from and to, inclusive. Days without reading are omitted.
+	ReadingSecondsByDay(userID int, from, to time.Time) (map[time.Time]int, error)
+}
@@ -0,0 +1,67 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Roles carried in access tokens.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+var (
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserID  int
+	Roles   []string
+	TokenID string
+}
+
+// HasRole reports whether the principal was granted role.
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// IsAdmin reports whether the principal has the admin role.
+func (p Principal) IsAdmin() bool {
+	return p.HasRole(RoleAdmin)
+}
+
+// CanActAs reports whether the principal may act on resources owned by userID.
+func (p Principal) CanActAs(userID int) bool {
+	return p.UserID == userID || p.IsAdmin()
+}
+
+// principalKey is unexported so only this package can store or read the
+// principal, which keeps other code from spoofing it with a plain string key.
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	if !ok || p.UserID == 0 {
+		return Principal{}, false
+	}
+	return p, true
+}
+
+// Require returns the principal stored in ctx or ErrUnauthenticated.
+func Require(ctx context.Context) (Principal, error) {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return Principal{}, ErrUnauthenticated
+	}
+	return p, nil
+}
@@ -0,0 +1,92 @@
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+)
+
+// Scopes a leaderboard can cover.
+const (
+	ScopeGlobal = "global"
+	// ScopeWeekly ranks activity since midnight on Monday of the current
+	// week in the caller's timezone, or UTC if they have not set one.
+	ScopeWeekly = "weekly"
+	// ScopeFriends ranks the caller among the users on their friends list.
+	ScopeFriends = "friends"
+)
+
+// Metrics a leaderboard can rank by.
+const (
+	MetricPoints    = "points"
+	MetricStreak    = "streak"
+	MetricBooksRead = "books_read"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// Query selects a leaderboard for the caller UserID.
+type Query struct {
+	Scope  string
+	Metric string
+	Limit  int
+	UserID int
+}
+
+// Entry is one ranked user. Only public profile fields are exposed.
+type Entry struct {
+	Rank         int     `json:"rank"`
+	UserID       int     `json:"user_id"`
+	Username     string  `json:"username"`
+	ProfileImage *string `json:"profile_image,omitempty"`
+	Value        int     `json:"value"`
+}
+
+// Board is the top of a leaderboard plus the caller's own entry, which is
+// filled in even when the caller is outside the top.
+type Board struct {
+	Scope   string     `json:"scope"`
+	Metric  string     `json:"metric"`
+	Since   *time.Time `json:"since,omitempty"`
+	Entries []*Entry   `json:"entries"`
+	Me      *Entry     `json:"me"`
+}
+
+// Normalize applies defaults and validates q. The returned error wraps
+// ErrInvalidQuery.
+func (q *Query) Normalize() error {
+	if q.Scope == "" {
+		q.Scope = ScopeGlobal
+	}
+	if q.Metric == "" {
+		q.Metric = MetricPoints
+	}
+	switch q.Scope {
+	case ScopeGlobal, ScopeWeekly, ScopeFriends:
+	default:
+		return fmt.Errorf("%w: scope must be global, weekly or friends", ErrInvalidQuery)
+	}
+	switch q.Metric {
+	case MetricPoints, MetricStreak, MetricBooksRead:
+	default:
+		return fmt.Errorf("%w: metric must be points, streak or books_read", ErrInvalidQuery)
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+	return nil
+}
+
+// WeekStart returns the Monday 00:00 in loc that starts the week containing
+// t in loc.
+func WeekStart(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	offset := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.AddDate(0, 0, -offset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,26 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekStartUsesTimezone(t *testing.T) {
+	addis, err := time.LoadLocation("Africa/Addis_Ababa")
+	if err != nil {
+		t.Skip(err)
+	}
+	// Monday 01:00 in Addis Ababa is still Sunday in UTC.
+	monday := time.Date(2026, 10, 19, 1, 0, 0, 0, addis)
+
+	if got, want := WeekStart(monday, addis), time.Date(2026, 10, 19, 0, 0, 0, 0, addis); !got.Equal(want) {
+		t.Errorf("in Addis Ababa: got %v, want %v", got, want)
+	}
+	if got, want := WeekStart(monday, time.UTC), time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("in UTC: got %v, want %v", got, want)
+	}
+	sunday := time.Date(2026, 10, 25, 23, 59, 0, 0, addis)
+	if got, want := WeekStart(sunday, addis), time.Date(2026, 10, 19, 0, 0, 0, 0, addis); !got.Equal(want) {
+		t.Errorf("on Sunday night: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,8 @@
+package leaderboard
+
+import "errors"
+
+var (
+	ErrInvalidQuery = errors.New("invalid leaderboard query")
+	ErrUserNotFound = errors.New("user not found")
+)
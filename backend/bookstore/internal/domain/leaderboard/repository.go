@@ -0,0 +1,13 @@
+package leaderboard
+
+import "time"
+
+// Repository ranks users. since is only used by the weekly scope, which
+// counts activity from the local date of since onwards, each event on the
+// day it happened in its user's timezone.
+type Repository interface {
+	// Top returns the first q.Limit entries, highest value first.
+	Top(q Query, since time.Time) ([]*Entry, error)
+	// RankOf returns the entry of q.UserID.
+	RankOf(q Query, since time.Time) (*Entry, error)
+}
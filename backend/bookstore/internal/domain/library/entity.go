@@ -0,0 +1,115 @@
+package library
+
+import (
+	"strings"
+	"time"
+)
+
+// Import formats.
+const (
+	FormatGoodreadsCSV = "goodreads_csv"
+	// FormatCalibreOPF is a single metadata.opf file or a zip of a Calibre
+	// library folder, which holds one metadata.opf per book.
+	FormatCalibreOPF = "calibre_opf"
+	// FormatCalibreDB is a Calibre library's metadata.db.
+	FormatCalibreDB = "calibre_db"
+)
+
+// Job statuses.
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+const (
+	// MaxJobDuration bounds a whole import job. A job still pending or
+	// running after that has lost its worker.
+	MaxJobDuration = 30 * time.Minute
+	MaxImportBytes = 20 << 20
+	MaxImportRows  = 5000
+	// CalibreShelf is the custom shelf Calibre books are put on, since a
+	// Calibre library records what the user owns rather than what they read.
+	CalibreShelf = "Calibre"
+)
+
+// Record is one book read from an imported library. Shelf is the built-in
+// shelf kind the book belongs on, if any; Shelves are custom shelf names.
+type Record struct {
+	Row     int      `json:"row"`
+	Title   string   `json:"title"`
+	Author  string   `json:"author"`
+	ISBN    string   `json:"isbn,omitempty"`
+	Rating  int      `json:"rating,omitempty"`
+	Shelf   string   `json:"shelf,omitempty"`
+	Shelves []string `json:"shelves,omitempty"`
+}
+
+// Unmatched is a record that could not be found in the catalog or at the
+// external provider.
+type Unmatched struct {
+	Record
+	Reason string `json:"reason"`
+}
+
+// Job is a library import running in the background. Clients poll it until
+// Status is completed or failed.
+type Job struct {
+	ID        string       `json:"id"`
+	UserID    int          `json:"user_id"`
+	Format    string       `json:"format"`
+	Filename  string       `json:"filename"`
+	Status    string       `json:"status"`
+	Total     int          `json:"total"`
+	Processed int          `json:"processed"`
+	Matched   int          `json:"matched"`
+	Unmatched []*Unmatched `json:"unmatched"`
+	Error     string       `json:"error,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	// FinishedAt is set once the job has completed or failed.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Done reports whether the job has stopped running.
+func (j *Job) Done() bool {
+	return j.Status == StatusCompleted || j.Status == StatusFailed
+}
+
+// NormalizeISBN strips separators from an ISBN and returns it if it has the
+// length of an ISBN-10 or ISBN-13, or "" otherwise.
+func NormalizeISBN(s string) string {
+	s = strings.ToUpper(strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == 'X' || r == 'x' {
+			return r
+		}
+		return -1
+	}, s))
+	if len(s) != 10 && len(s) != 13 {
+		return ""
+	}
+	return s
+}
+
+// MatchKey normalizes a title and author for comparing books from
+// different sources: lower case, letters and digits only, subtitle and
+// co-authors dropped.
+func MatchKey(title, author string) string {
+	if i := strings.IndexAny(title, ":("); i > 0 {
+		title = title[:i]
+	}
+	if i := strings.IndexAny(author, ",&;"); i > 0 {
+		author = author[:i]
+	}
+	return compact(title) + "|" + compact(author)
+}
+
+func compact(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r > 127 {
+			return r
+		}
+		return -1
+	}, strings.ToLower(s))
+}
@@ -0,0 +1,10 @@
+package library
+
+import "errors"
+
+var (
+	ErrImportNotFound   = errors.New("import not found")
+	ErrImportInProgress = errors.New("an import is already running")
+	ErrInvalidImport    = errors.New("invalid import file")
+	ErrUnsupportedFile  = errors.New("unsupported import file")
+)
@@ -0,0 +1,17 @@
+package library
+
+// ImportRepository defines methods for import job persistence.
+type ImportRepository interface {
+	// CreateJob returns ErrImportInProgress if the user already has a job
+	// that is not done. Jobs older than MaxJobDuration are failed first, so
+	// a job whose worker died cannot block the user.
+	CreateJob(job *Job) (*Job, error)
+	// UpdateJob saves the job's status and progress.
+	UpdateJob(job *Job) error
+	GetJob(userID int, id string) (*Job, error)
+	// ListJobs returns the user's most recent jobs, newest first.
+	ListJobs(userID int, limit int) ([]*Job, error)
+	// FailInterrupted fails jobs left running by a previous process, and
+	// reports how many there were.
+	FailInterrupted() (int, error)
+}
@@ -1,15 +1,66 @@
 package note
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
 
-// Note represents a user's note on a specific book.
+// Highlight colors offered by the reader. Custom "#rrggbb" colors are also accepted.
+var Colors = []string{"yellow", "green", "blue", "pink", "purple", "orange"}
+
+const (
+	MaxTags      = 20
+	MaxTagLength = 40
+)
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Anchor ties a note to a location in the book: a page for PDFs or an
+// EPUB CFI for reflowable books.
+type Anchor struct {
+	PageNumber *int   `json:"page_number,omitempty"`
+	CFI        string `json:"cfi,omitempty"`
+}
+
+// Note represents a user's note on a specific book. A note with a Quote is
+// a highlight; Content holds the note text or, for AI notes, the explanation.
+// Version is bumped on every write, and a deleted note is kept as a
+// tombstone with DeletedAt set so offline clients can sync the deletion.
 type Note struct {
-	ID            string    `json:"id"`
-	UserID        int       `json:"user_id"`
-	BookID        string    `json:"book_id"`
-	Content       string    `json:"content"`
-	IsAIGenerated bool      `json:"is_ai_generated"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID     string `json:"id"`
+	UserID int    `json:"user_id"`
+	BookID string `json:"book_id"`
+	Anchor
+	Quote         string     `json:"quote,omitempty"`
+	Content       string     `json:"content"`
+	Comment       string     `json:"comment,omitempty"`
+	Color         string     `json:"color,omitempty"`
+	Tags          []string   `json:"tags"`
+	IsAIGenerated bool       `json:"is_ai_generated"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Version       int64      `json:"version"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Filter narrows a note listing. Zero values match everything.
+type Filter struct {
+	Tag   string
+	Color string
+	Page  *int
+}
+
+// Patch holds the fields of a partial note update; nil fields are left unchanged.
+type Patch struct {
+	Content    *string   `json:"content"`
+	Quote      *string   `json:"quote"`
+	Comment    *string   `json:"comment"`
+	Color      *string   `json:"color"`
+	Tags       *[]string `json:"tags"`
+	PageNumber *int      `json:"page_number"`
+	CFI        *string   `json:"cfi"`
 }
 
 // NewNote creates a new Note instance.
@@ -22,7 +73,94 @@ func NewNote(id string, userID int, bookID, content string, isAIGenerated bool,
 		UserID:        userID,
 		BookID:        bookID,
 		Content:       content,
+		Tags:          []string{},
 		IsAIGenerated: isAIGenerated,
 		CreatedAt:     createdAt,
+		UpdatedAt:     createdAt,
+	}
+}
+
+// Apply copies the non-nil fields of p onto n.
+func (n *Note) Apply(p Patch) {
+	if p.Content != nil {
+		n.Content = *p.Content
+	}
+	if p.Quote != nil {
+		n.Quote = *p.Quote
+	}
+	if p.Comment != nil {
+		n.Comment = *p.Comment
+	}
+	if p.Color != nil {
+		n.Color = *p.Color
+	}
+	if p.Tags != nil {
+		n.Tags = *p.Tags
+	}
+	if p.PageNumber != nil {
+		if *p.PageNumber == 0 {
+			n.PageNumber = nil
+		} else {
+			page := *p.PageNumber
+			n.PageNumber = &page
+		}
+	}
+	if p.CFI != nil {
+		n.CFI = *p.CFI
+	}
+}
+
+// Normalize trims and de-duplicates tags and lowercases the color, then
+// validates the note. The returned error wraps ErrInvalidNote.
+func (n *Note) Normalize() error {
+	n.Content = strings.TrimSpace(n.Content)
+	n.Quote = strings.TrimSpace(n.Quote)
+	n.Comment = strings.TrimSpace(n.Comment)
+	n.CFI = strings.TrimSpace(n.CFI)
+	n.Color = strings.ToLower(strings.TrimSpace(n.Color))
+
+	if n.BookID == "" {
+		return fmt.Errorf("%w: book_id is required", ErrInvalidNote)
+	}
+	if n.Content == "" && n.Quote == "" {
+		return fmt.Errorf("%w: content or quote is required", ErrInvalidNote)
+	}
+	if n.PageNumber != nil && *n.PageNumber < 1 {
+		return fmt.Errorf("%w: page_number must be positive", ErrInvalidNote)
+	}
+	if n.CFI != "" && !strings.HasPrefix(n.CFI, "epubcfi(") {
+		return fmt.Errorf("%w: cfi must look like epubcfi(...)", ErrInvalidNote)
+	}
+	if n.Color != "" && !IsValidColor(n.Color) {
+		return fmt.Errorf("%w: color must be one of %s or #rrggbb", ErrInvalidNote, strings.Join(Colors, ", "))
+	}
+
+	seen := make(map[string]bool, len(n.Tags))
+	tags := make([]string, 0, len(n.Tags))
+	for _, tag := range n.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > MaxTagLength {
+			return fmt.Errorf("%w: tags must be at most %d characters", ErrInvalidNote, MaxTagLength)
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > MaxTags {
+		return fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidNote, MaxTags)
+	}
+	n.Tags = tags
+	return nil
+}
+
+// IsValidColor reports whether color is a palette name or a #rrggbb value.
+func IsValidColor(color string) bool {
+	for _, c := range Colors {
+		if color == c {
+			return true
+		}
 	}
+	return hexColor.MatchString(color)
 }
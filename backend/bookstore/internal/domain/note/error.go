@@ -0,0 +1,11 @@
+package note
+
+import "errors"
+
+var (
+	ErrNoteNotFound = errors.New("note not found")
+	ErrInvalidNote  = errors.New("invalid note")
+	ErrNoteExists   = errors.New("note already exists")
+	// ErrNoteVersionConflict means the note changed since it was read.
+	ErrNoteVersionConflict = errors.New("note was modified concurrently")
+)
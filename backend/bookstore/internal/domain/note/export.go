@@ -0,0 +1,37 @@
+package note
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExportFormat selects how notes are rendered by an export.
+type ExportFormat string
+
+const (
+	ExportMarkdown ExportFormat = "markdown"
+	ExportJSON     ExportFormat = "json"
+	// ExportAnki is a CSV file with Anki import headers, one card per note.
+	ExportAnki ExportFormat = "anki"
+)
+
+// ParseExportFormat accepts a format name or a common alias such as "md"
+// or "csv". The returned error wraps ErrInvalidNote.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "markdown", "md":
+		return ExportMarkdown, nil
+	case "json":
+		return ExportJSON, nil
+	case "anki", "csv":
+		return ExportAnki, nil
+	}
+	return "", fmt.Errorf("%w: unsupported export format %q", ErrInvalidNote, s)
+}
+
+// Export is a rendered export ready to be downloaded.
+type Export struct {
+	Filename    string
+	ContentType string
+	Body        []byte
+}
@@ -1,8 +1,19 @@
 package note
 
-// NoteRepository defines methods for note persistence.
+// NoteRepository defines methods for note persistence. Deleted notes are
+// tombstones that only GetByIDIncludingDeleted and GetChanges return.
 type NoteRepository interface {
 	Create(note *Note) (*Note, error)
-	GetByBookID(bookID string, userID int) ([]*Note, error)
+	GetByID(noteID string, userID int) (*Note, error)
+	GetByIDIncludingDeleted(noteID string, userID int) (*Note, error)
+	GetByBookID(bookID string, userID int, filter Filter) ([]*Note, error)
+	GetByUserID(userID int) ([]*Note, error)
+	// Update saves note if it is still at note.Version and bumps the version.
+	Update(note *Note) (*Note, error)
 	Delete(noteID string, userID int) error
+	Search(userID int, query SearchQuery) ([]*SearchResult, error)
+	// GetChanges returns the changes after since. A change must not be
+	// returned until every change that could sort before it is visible,
+	// so that a cursor never moves past a change still being written.
+	GetChanges(userID int, since Cursor, limit int) (*ChangeSet, error)
 }
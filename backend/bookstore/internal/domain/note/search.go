@@ -0,0 +1,60 @@
+package note
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	maxSearchTextLen   = 200
+)
+
+// SearchQuery is a full-text search over one user's notes in every book.
+type SearchQuery struct {
+	Text string
+	// IsAIGenerated restricts results to AI (true) or manual (false) notes when set.
+	IsAIGenerated *bool
+	BookID        string
+	Limit         int
+	Offset        int
+}
+
+// BookInfo is the book metadata shown next to a search hit.
+type BookInfo struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Author   string `json:"author"`
+	CoverUrl string `json:"cover_url"`
+}
+
+// SearchResult is a note that matched a search, with a highlighted snippet
+// in which matches are wrapped in <mark></mark>.
+type SearchResult struct {
+	Note    *Note    `json:"note"`
+	Snippet string   `json:"snippet"`
+	Rank    float64  `json:"rank"`
+	Book    BookInfo `json:"book"`
+}
+
+// Normalize applies defaults and limits. The returned error wraps ErrInvalidNote.
+func (q *SearchQuery) Normalize() error {
+	q.Text = strings.TrimSpace(q.Text)
+	if q.Text == "" {
+		return fmt.Errorf("%w: search query is required", ErrInvalidNote)
+	}
+	if len(q.Text) > maxSearchTextLen {
+		return fmt.Errorf("%w: search query must be at most %d characters", ErrInvalidNote, maxSearchTextLen)
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultSearchLimit
+	}
+	if q.Limit > MaxSearchLimit {
+		q.Limit = MaxSearchLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package note
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxSyncChanges caps how many client changes one sync request may push.
+	MaxSyncChanges = 500
+	// SyncPageSize caps how many server changes one sync response returns.
+	SyncPageSize = 500
+	// ConflictTag marks a note copied aside because it lost a sync conflict.
+	ConflictTag = "conflict"
+
+	syncTokenPrefix = "notes:"
+)
+
+// Sides of a sync conflict.
+const (
+	WinnerClient = "client"
+	WinnerServer = "server"
+)
+
+// Cursor is a position in a user's note changes, which are ordered by the
+// transaction that wrote them and then by Seq within it. The zero Cursor
+// is the beginning.
+type Cursor struct {
+	TxID int64
+	Seq  int64
+}
+
+// ChangeSet is a page of a user's note changes, tombstones included, in
+// cursor order.
+type ChangeSet struct {
+	Notes []*Note
+	// Cursor is the position of the last change in the page, or the
+	// requested position when the page is empty.
+	Cursor  Cursor
+	HasMore bool
+}
+
+// SyncConflict reports a client change that was made against an outdated
+// version of a note. The losing side is kept as a new note, CopyID, unless
+// it was a deletion.
+type SyncConflict struct {
+	NoteID string `json:"note_id"`
+	CopyID string `json:"copy_id,omitempty"`
+	Winner string `json:"winner"`
+}
+
+// SyncRejection reports a client change that could not be applied.
+type SyncRejection struct {
+	NoteID string `json:"note_id"`
+	Error  string `json:"error"`
+}
+
+// SyncResult is the server's answer to a sync request: every change since
+// the client's token, including the client's own accepted changes with
+// their new versions, and the token to send next time.
+type SyncResult struct {
+	SyncToken string          `json:"sync_token"`
+	Changes   []*Note         `json:"changes"`
+	Conflicts []SyncConflict  `json:"conflicts"`
+	Rejected  []SyncRejection `json:"rejected"`
+	HasMore   bool            `json:"has_more"`
+}
+
+// EncodeSyncToken turns a change cursor into an opaque sync token.
+func EncodeSyncToken(cursor Cursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(syncTokenPrefix +
+		strconv.FormatInt(cursor.TxID, 10) + ":" + strconv.FormatInt(cursor.Seq, 10)))
+}
+
+// ParseSyncToken returns the change cursor in token. An empty token starts
+// from the beginning, and so does a token from before changes were ordered
+// by transaction, which held a single number; the client then gets every
+// note again and keeps the newest version of each. The returned error
+// wraps ErrInvalidNote.
+func ParseSyncToken(token string) (Cursor, error) {
+	if token == "" {
+		return Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil && strings.HasPrefix(string(raw), syncTokenPrefix) {
+		tx, seq, ok := strings.Cut(strings.TrimPrefix(string(raw), syncTokenPrefix), ":")
+		if !ok {
+			if legacy, err := strconv.ParseInt(tx, 10, 64); err == nil && legacy >= 0 {
+				return Cursor{}, nil
+			}
+		} else {
+			txID, txErr := strconv.ParseInt(tx, 10, 64)
+			s, seqErr := strconv.ParseInt(seq, 10, 64)
+			if txErr == nil && seqErr == nil && txID >= 0 && s >= 0 {
+				return Cursor{TxID: txID, Seq: s}, nil
+			}
+		}
+	}
+	return Cursor{}, fmt.Errorf("%w: invalid sync_token", ErrInvalidNote)
+}
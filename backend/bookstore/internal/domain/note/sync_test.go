@@ -0,0 +1,36 @@
+package note
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestSyncTokenRoundTrip(t *testing.T) {
+	for _, c := range []Cursor{{}, {TxID: 7}, {TxID: 1 << 40, Seq: 12}} {
+		got, err := ParseSyncToken(EncodeSyncToken(c))
+		if err != nil || got != c {
+			t.Errorf("%+v: got %+v, %v", c, got, err)
+		}
+	}
+}
+
+func TestLegacySyncTokenStartsOver(t *testing.T) {
+	legacy := base64.RawURLEncoding.EncodeToString([]byte("notes:42"))
+	if got, err := ParseSyncToken(legacy); err != nil || got != (Cursor{}) {
+		t.Errorf("got %+v, %v; want the beginning", got, err)
+	}
+}
+
+func TestInvalidSyncToken(t *testing.T) {
+	for _, token := range []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("other:1:2")),
+		base64.RawURLEncoding.EncodeToString([]byte("notes:-1:2")),
+		base64.RawURLEncoding.EncodeToString([]byte("notes:1:x")),
+	} {
+		if _, err := ParseSyncToken(token); !errors.Is(err, ErrInvalidNote) {
+			t.Errorf("%q: got %v, want ErrInvalidNote", token, err)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package opds
+
+import "errors"
+
+var (
+	ErrFeedNotFound   = errors.New("feed not found")
+	ErrInvalidRequest = errors.New("invalid feed request")
+)
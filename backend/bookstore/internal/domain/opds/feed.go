@@ -0,0 +1,79 @@
+package opds
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+)
+
+// Feed paths, relative to the catalog root. A category's books are under
+// PathCategories + "/" + its name.
+const (
+	PathRoot       = ""
+	PathNew        = "new"
+	PathFeatured   = "featured"
+	PathAll        = "all"
+	PathCategories = "categories"
+	PathSearch     = "search"
+)
+
+// Feed kinds. Navigation feeds link to other feeds; acquisition feeds list
+// books.
+const (
+	KindNavigation  = "navigation"
+	KindAcquisition = "acquisition"
+)
+
+// Page sizes of acquisition feeds.
+const (
+	DefaultPageSize = 25
+	MaxPageSize     = 100
+)
+
+// Request asks for one page of a feed. Page is 1-based; Query is only used
+// by the search feed.
+type Request struct {
+	Path     string
+	Query    string
+	Page     int
+	PageSize int
+}
+
+// Feed is a catalog feed independent of its wire format, which may be
+// OPDS 1.2 Atom or OPDS 2.0 JSON.
+type Feed struct {
+	Path    string
+	Title   string
+	Kind    string
+	Updated time.Time
+	// Up is the path of the parent feed; the root has none.
+	Up         string
+	Navigation []Navigation
+	Books      []*book.Book
+	Query      string
+	Page       int
+	PageSize   int
+	HasNext    bool
+}
+
+// Navigation links a navigation feed to another feed. Count is the number
+// of books behind the link, or 0 if not known.
+type Navigation struct {
+	Path    string
+	Title   string
+	Summary string
+	Kind    string
+	Count   int
+}
+
+// CategoryPath returns the path of a category's feed.
+func CategoryPath(name string) string {
+	return PathCategories + "/" + name
+}
+
+// CategoryFromPath returns the category a category feed path is for.
+func CategoryFromPath(path string) (string, bool) {
+	name, ok := strings.CutPrefix(path, PathCategories+"/")
+	return name, ok && name != ""
+}
@@ -0,0 +1,117 @@
+package order
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+)
+
+// Order statuses. An order is created pending, becomes paid once the
+// payment gateway charges it, and may then be refunded.
+const (
+	StatusPending  = "pending"
+	StatusPaid     = "paid"
+	StatusRefunded = "refunded"
+)
+
+// DefaultCurrency is the currency book prices are in.
+const DefaultCurrency = "USD"
+
+// MaxCartItems bounds a cart.
+const MaxCartItems = 100
+
+// transitions lists the statuses each status may move to.
+var transitions = map[string][]string{
+	StatusPending: {StatusPaid},
+	StatusPaid:    {StatusRefunded},
+}
+
+// CanTransition reports whether an order may move from one status to
+// another.
+func CanTransition(from, to string) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition returns an error wrapping ErrInvalidTransition if an
+// order may not move from one status to another.
+func CheckTransition(from, to string) error {
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: a %s order cannot become %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// Cart is the books a user means to buy. Prices are the books' current
+// prices; they are only fixed when the cart is checked out. Amounts here
+// and in orders are in the currency's minor unit, cents for USD, so totals
+// add up exactly.
+type Cart struct {
+	Items      []*CartItem `json:"items"`
+	TotalCents int64       `json:"total_cents"`
+	Currency   string      `json:"currency"`
+}
+
+type CartItem struct {
+	BookID     string    `json:"book_id"`
+	Title      string    `json:"title"`
+	Author     string    `json:"author"`
+	CoverUrl   string    `json:"cover_url"`
+	PriceCents int64     `json:"price_cents"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// Order is a checked-out cart.
+type Order struct {
+	ID         string      `json:"id"`
+	UserID     int         `json:"user_id"`
+	Status     string      `json:"status"`
+	Items      []*LineItem `json:"items"`
+	TotalCents int64       `json:"total_cents"`
+	Currency   string      `json:"currency"`
+	// PaymentRef and RefundRef are the gateway's references for the
+	// charge and its refund.
+	PaymentRef string     `json:"payment_ref,omitempty"`
+	RefundRef  string     `json:"refund_ref,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	PaidAt     *time.Time `json:"paid_at,omitempty"`
+	RefundedAt *time.Time `json:"refunded_at,omitempty"`
+}
+
+// LineItem is one book in an order, with its price when the order was
+// placed.
+type LineItem struct {
+	BookID     string `json:"book_id"`
+	Title      string `json:"title"`
+	Author     string `json:"author"`
+	PriceCents int64  `json:"price_cents"`
+}
+
+// Entitlement records that a user may download a paid book.
+type Entitlement struct {
+	UserID    int       `json:"user_id"`
+	BookID    string    `json:"book_id"`
+	OrderID   string    `json:"order_id"`
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+// PriceCents returns a book's price in cents, or 0 if it is free or its
+// price is unknown.
+func PriceCents(b *book.Book) int64 {
+	if b.Price == nil || *b.Price <= 0 {
+		return 0
+	}
+	return int64(math.Round(float64(*b.Price) * 100))
+}
+
+// ForSale reports whether a book must be bought before it is downloaded.
+// External books are not sold here.
+func ForSale(b *book.Book) bool {
+	return !b.IsExternal && PriceCents(b) > 0
+}
@@ -0,0 +1,19 @@
+package order
+
+import "errors"
+
+var (
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrItemNotFound      = errors.New("book is not in the cart")
+	ErrCartEmpty         = errors.New("the cart is empty")
+	ErrCartFull          = errors.New("the cart is full")
+	ErrNotForSale        = errors.New("book is not for sale")
+	ErrAlreadyOwned      = errors.New("you already own this book")
+	ErrInvalidTransition = errors.New("invalid order status change")
+	// ErrPaymentDeclined means the gateway refused the charge; the order
+	// stays pending and may be paid again.
+	ErrPaymentDeclined = errors.New("payment declined")
+	// ErrPaymentUnavailable means no gateway is configured or it could not
+	// be reached.
+	ErrPaymentUnavailable = errors.New("payment gateway unavailable")
+)
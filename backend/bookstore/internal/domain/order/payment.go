@@ -0,0 +1,21 @@
+package order
+
+import "context"
+
+// Charge asks the gateway to take payment for an order.
+type Charge struct {
+	OrderID     string
+	UserID      int
+	AmountCents int64
+	Currency    string
+}
+
+// PaymentGateway takes and refunds payments. Implementations return errors
+// wrapping ErrPaymentDeclined or ErrPaymentUnavailable.
+type PaymentGateway interface {
+	// Charge returns the gateway's reference for the payment. Charging the
+	// same order twice must not take payment twice.
+	Charge(ctx context.Context, c Charge) (string, error)
+	// Refund returns the gateway's reference for the refund of a payment.
+	Refund(ctx context.Context, paymentRef string, amountCents int64, currency string) (string, error)
+}
@@ -0,0 +1,29 @@
+package order
+
+import "time"
+
+// Repository defines methods for cart, order and entitlement persistence.
+type Repository interface {
+	// AddToCart is a no-op if the book is already in the cart.
+	AddToCart(userID int, bookID string) error
+	// RemoveFromCart returns ErrItemNotFound if the book is not in the cart.
+	RemoveFromCart(userID int, bookID string) error
+	// ListCart returns the cart's books with their current prices, oldest
+	// first.
+	ListCart(userID int) ([]*CartItem, error)
+
+	// CreateOrder stores a pending order and removes its books from the
+	// user's cart.
+	CreateOrder(o *Order) (*Order, error)
+	// GetOrder returns ErrOrderNotFound if there is no such order.
+	GetOrder(id string) (*Order, error)
+	// ListOrders returns the user's orders, newest first.
+	ListOrders(userID int) ([]*Order, error)
+	// MarkPaid moves a pending order to paid and grants its entitlements.
+	MarkPaid(orderID, paymentRef string, at time.Time) (*Order, error)
+	// MarkRefunded moves a paid order to refunded and revokes its
+	// entitlements.
+	MarkRefunded(orderID, refundRef string, at time.Time) (*Order, error)
+
+	HasEntitlement(userID int, bookID string) (bool, error)
+}
@@ -0,0 +1,103 @@
+package rating
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Review statuses. Hidden reviews are kept for the author and admins but
+// are not listed and do not count towards the book's rating.
+const (
+	StatusVisible = "visible"
+	StatusHidden  = "hidden"
+)
+
+// Moderation actions.
+const (
+	ActionHide    = "hide"
+	ActionRestore = "restore"
+	ActionDelete  = "delete"
+)
+
+const (
+	MinRating       = 1
+	MaxRating       = 5
+	MaxTextLength   = 5000
+	MaxReasonLength = 500
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Review is one user's star rating of a book, with optional text. A user has
+// at most one review per book.
+type Review struct {
+	ID           string    `json:"id"`
+	BookID       string    `json:"book_id"`
+	UserID       int       `json:"user_id"`
+	Username     string    `json:"username"`
+	ProfileImage *string   `json:"profile_image,omitempty"`
+	Rating       int       `json:"rating"`
+	Text         string    `json:"text"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	// Moderation details are only shown to admins and the review's author.
+	ReportCount      int        `json:"report_count,omitempty"`
+	ModerationReason string     `json:"moderation_reason,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`
+}
+
+// Summary is the aggregate of a book's visible reviews. Distribution[i]
+// counts the reviews rated i+1.
+type Summary struct {
+	Average      float64        `json:"average"`
+	Count        int            `json:"count"`
+	Distribution [MaxRating]int `json:"distribution"`
+}
+
+// Page is a page of a book's reviews with the book's summary.
+type Page struct {
+	Summary *Summary  `json:"summary"`
+	Reviews []*Review `json:"reviews"`
+	// Mine is the caller's own review, whatever its status.
+	Mine *Review `json:"mine,omitempty"`
+}
+
+// Draft is a review as submitted by its author.
+type Draft struct {
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+// Validate trims the text and checks the draft. The returned error wraps
+// ErrInvalidReview.
+func (d *Draft) Validate() error {
+	d.Text = strings.TrimSpace(d.Text)
+	if d.Rating < MinRating || d.Rating > MaxRating {
+		return fmt.Errorf("%w: rating must be between %d and %d", ErrInvalidReview, MinRating, MaxRating)
+	}
+	if utf8.RuneCountInString(d.Text) > MaxTextLength {
+		return fmt.Errorf("%w: text must be at most %d characters", ErrInvalidReview, MaxTextLength)
+	}
+	return nil
+}
+
+// Redact clears the moderation details for callers who are neither the
+// author nor an admin.
+func (r *Review) Redact() {
+	r.ReportCount = 0
+	r.ModerationReason = ""
+	r.ModeratedAt = nil
+}
+
+// NormalizeReason trims a report or moderation reason and checks its
+// length. The returned error wraps ErrInvalidReview.
+func NormalizeReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > MaxReasonLength {
+		return "", fmt.Errorf("%w: reason must be at most %d characters", ErrInvalidReview, MaxReasonLength)
+	}
+	return reason, nil
+}
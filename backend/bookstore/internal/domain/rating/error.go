@@ -0,0 +1,9 @@
+package rating
+
+import "errors"
+
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	ErrInvalidReview  = errors.New("invalid review")
+	ErrOwnReview      = errors.New("you cannot report your own review")
+)
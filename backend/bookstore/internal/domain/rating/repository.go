@@ -0,0 +1,26 @@
+package rating
+
+// Repository defines methods for review persistence. Every write keeps the
+// book's aggregate rating and rating count in step with its visible
+// reviews.
+type Repository interface {
+	// Upsert creates the user's review of the book or edits it, and
+	// reports whether it was created. Editing keeps a hidden review hidden.
+	Upsert(r *Review) (*Review, bool, error)
+	// Delete removes the user's review of the book.
+	Delete(bookID string, userID int) error
+	// GetByUser returns ErrReviewNotFound if the user has not reviewed the book.
+	GetByUser(bookID string, userID int) (*Review, error)
+	// ListByBook returns a page of the book's visible reviews, newest first.
+	ListByBook(bookID string, limit, offset int) ([]*Review, error)
+	Summary(bookID string) (*Summary, error)
+
+	// Report flags a review for moderation. Reporting twice counts once.
+	Report(reviewID string, userID int, reason string) error
+	// ListReported returns visible reviews with reports, most reported first.
+	ListReported(limit, offset int) ([]*Review, error)
+	// Moderate hides or restores a review, recording the reason.
+	Moderate(reviewID, status, reason string) (*Review, error)
+	// DeleteByID removes a review regardless of its author.
+	DeleteByID(reviewID string) error
+}
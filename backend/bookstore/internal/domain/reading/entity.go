@@ -0,0 +1,119 @@
+package reading
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reading statuses.
+const (
+	StatusReading  = "reading"
+	StatusFinished = "finished"
+)
+
+// MaxSessionSeconds caps the reading time one progress update may add, so a
+// reader left open overnight does not count as hours of reading.
+const MaxSessionSeconds = 4 * 60 * 60
+
+// MinFinishedSeconds is the reading time a finished book needs before it
+// earns points, so marking unread books finished earns nothing.
+const MinFinishedSeconds = 30 * 60
+
+// Progress is where a user is in a book and how long they have spent in it.
+type Progress struct {
+	UserID           int        `json:"user_id"`
+	BookID           string     `json:"book_id"`
+	PageNumber       *int       `json:"page_number,omitempty"`
+	CFI              string     `json:"cfi,omitempty"`
+	Percent          float64    `json:"percent"`
+	TimeSpentSeconds int64      `json:"time_spent_seconds"`
+	Status           string     `json:"status"`
+	StartedAt        time.Time  `json:"started_at"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Update is a progress report sent by the reader.
+type Update struct {
+	PageNumber *int    `json:"page_number"`
+	CFI        string  `json:"cfi"`
+	Percent    float64 `json:"percent"`
+	// SessionSeconds is the reading time since the previous report. Only
+	// as much as has passed since then is counted.
+	SessionSeconds int64 `json:"time_spent_seconds"`
+	// Finished marks the book as read even if Percent is below 100.
+	Finished bool `json:"finished"`
+}
+
+// BookInfo is the book metadata shown in a reading list.
+type BookInfo struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Author   string `json:"author"`
+	CoverUrl string `json:"cover_url"`
+}
+
+// Entry is a book on the user's reading list.
+type Entry struct {
+	Progress
+	Book BookInfo `json:"book"`
+}
+
+// List splits the user's books into those in progress and those finished.
+type List struct {
+	InProgress []*Entry `json:"in_progress"`
+	Finished   []*Entry `json:"finished"`
+}
+
+// Validate checks an update. The returned error wraps ErrInvalidProgress.
+func (u *Update) Validate() error {
+	u.CFI = strings.TrimSpace(u.CFI)
+	if u.PageNumber != nil && *u.PageNumber < 1 {
+		return fmt.Errorf("%w: page_number must be positive", ErrInvalidProgress)
+	}
+	if u.CFI != "" && !strings.HasPrefix(u.CFI, "epubcfi(") {
+		return fmt.Errorf("%w: cfi must look like epubcfi(...)", ErrInvalidProgress)
+	}
+	if u.Percent < 0 || u.Percent > 100 {
+		return fmt.Errorf("%w: percent must be between 0 and 100", ErrInvalidProgress)
+	}
+	if u.SessionSeconds < 0 {
+		return fmt.Errorf("%w: time_spent_seconds must not be negative", ErrInvalidProgress)
+	}
+	return nil
+}
+
+// ReadEnough reports whether the reader has recorded MinFinishedSeconds of
+// reading in the book, over at least as long since they started it, so the
+// time cannot be claimed in a single report.
+func (p *Progress) ReadEnough(now time.Time) bool {
+	return p.TimeSpentSeconds >= MinFinishedSeconds && now.Sub(p.StartedAt) >= MinFinishedSeconds*time.Second
+}
+
+// Apply records u on p at now. A finished book stays finished if the
+// reader goes back to an earlier page. The reading time added is capped at
+// MaxSessionSeconds and at the time since the previous update, so a reader
+// cannot claim time faster than the clock runs; the first update only
+// starts the clock.
+func (p *Progress) Apply(u Update, now time.Time) {
+	if p.StartedAt.IsZero() {
+		p.StartedAt = now
+	}
+	p.PageNumber = u.PageNumber
+	p.CFI = u.CFI
+	p.Percent = u.Percent
+	if !p.UpdatedAt.IsZero() {
+		elapsed := int64(now.Sub(p.UpdatedAt) / time.Second)
+		p.TimeSpentSeconds += max(min(u.SessionSeconds, MaxSessionSeconds, elapsed), 0)
+	}
+	p.UpdatedAt = now
+
+	if p.Status == "" {
+		p.Status = StatusReading
+	}
+	if (u.Finished || u.Percent >= 100) && p.Status != StatusFinished {
+		p.Status = StatusFinished
+		p.FinishedAt = &now
+	}
+}
@@ -0,0 +1,8 @@
+package reading
+
+import "errors"
+
+var (
+	ErrProgressNotFound = errors.New("reading progress not found")
+	ErrInvalidProgress  = errors.New("invalid reading progress")
+)
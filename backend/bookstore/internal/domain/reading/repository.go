@@ -0,0 +1,10 @@
+package reading
+
+// ProgressRepository defines methods for reading progress persistence.
+type ProgressRepository interface {
+	Get(userID int, bookID string) (*Progress, error)
+	// Save stores p. The first time a book is saved as finished, the
+	// user's books read count is incremented along with it.
+	Save(p *Progress) (*Progress, error)
+	ListByUser(userID int) ([]*Entry, error)
+}
@@ -0,0 +1,66 @@
+package recommendation
+
+import "github.com/bereke1t2/bookstore/internal/domain/book"
+
+// Signal kinds, strongest first. A signal's kind is the strongest thing the
+// user did with the book and is what explanations refer to.
+const (
+	SignalRated    = "rated"
+	SignalFinished = "finished"
+	SignalReading  = "reading"
+	SignalNoted    = "noted"
+	SignalSaved    = "saved"
+)
+
+// Reason kinds.
+const (
+	ReasonSimilarReaders = "similar_readers"
+	ReasonSameAuthor     = "same_author"
+	ReasonSameCategory   = "same_category"
+	ReasonPopular        = "popular"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 50
+	// MaxSeeds caps how many of the user's books seed the neighbour search.
+	MaxSeeds = 25
+)
+
+// Signal is how strongly a user engaged with a book, from shelves, reading
+// progress, ratings and notes. A negative weight means the user disliked it.
+type Signal struct {
+	BookID string
+	Weight float64
+	Kind   string
+}
+
+// Neighbor is a book that readers of a seed book also engaged with.
+// Similarity is the cosine of their reader vectors, damped for small overlaps.
+type Neighbor struct {
+	SeedID     string
+	BookID     string
+	Similarity float64
+}
+
+// Popularity is how many readers engaged positively with a book.
+type Popularity struct {
+	BookID  string
+	Readers int
+	Score   float64
+}
+
+// Reason explains a recommendation. BookID and BookTitle name the user's
+// book it is based on, if any.
+type Reason struct {
+	Kind      string `json:"kind"`
+	Text      string `json:"text"`
+	BookID    string `json:"book_id,omitempty"`
+	BookTitle string `json:"book_title,omitempty"`
+}
+
+type Recommendation struct {
+	Book    *book.Book `json:"book"`
+	Score   float64    `json:"score"`
+	Reasons []Reason   `json:"reasons"`
+}
@@ -0,0 +1,12 @@
+package recommendation
+
+// Repository reads the engagement data recommendations are built from.
+type Repository interface {
+	// Signals returns the user's engagement with each local book.
+	Signals(userID int) ([]Signal, error)
+	// Neighbors returns the books most similar to the seeds, judged by
+	// other readers' engagement. The user's own engagement is left out.
+	Neighbors(seedIDs []string, userID int, limit int) ([]Neighbor, error)
+	// Popular returns the books most readers engaged with positively.
+	Popular(limit int) ([]Popularity, error)
+}
@@ -0,0 +1,96 @@
+package review
+
+import (
+	"math"
+	"time"
+)
+
+// Sources a review card can be built from.
+const (
+	SourceMultipleChoice = "multiple_choice"
+	SourceTrueFalse      = "true_false"
+	SourceShortAnswer    = "short_answer"
+	SourceNote           = "note"
+)
+
+const (
+	DefaultEaseFactor = 2.5
+	MinEaseFactor     = 1.3
+	MinGrade          = 0
+	MaxGrade          = 5
+	// PassingGrade is the lowest grade that counts as a successful recall.
+	PassingGrade = 3
+)
+
+// Card is a flashcard scheduled with the SM-2 algorithm. Cards are grouped
+// into one deck per book.
+type Card struct {
+	ID             string     `json:"id"`
+	UserID         int        `json:"user_id"`
+	BookID         string     `json:"book_id"`
+	Front          string     `json:"front"`
+	Back           string     `json:"back"`
+	SourceType     string     `json:"source_type"`
+	SourceKey      string     `json:"-"`
+	EaseFactor     float64    `json:"ease_factor"`
+	IntervalDays   int        `json:"interval_days"`
+	Repetitions    int        `json:"repetitions"`
+	DueAt          time.Time  `json:"due_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Deck summarizes the cards a user has for one book.
+type Deck struct {
+	BookID     string `json:"book_id"`
+	TotalCards int    `json:"total_cards"`
+	DueCards   int    `json:"due_cards"`
+}
+
+// NewCard creates a card that is due immediately.
+func NewCard(userID int, bookID, front, back, sourceType, sourceKey string, now time.Time) *Card {
+	return &Card{
+		UserID:     userID,
+		BookID:     bookID,
+		Front:      front,
+		Back:       back,
+		SourceType: sourceType,
+		SourceKey:  sourceKey,
+		EaseFactor: DefaultEaseFactor,
+		DueAt:      now,
+		CreatedAt:  now,
+	}
+}
+
+// Schedule applies an SM-2 review with a recall grade from 0 (blackout) to
+// 5 (perfect) and moves the due date accordingly.
+func (c *Card) Schedule(grade int, now time.Time) error {
+	if grade < MinGrade || grade > MaxGrade {
+		return ErrInvalidGrade
+	}
+
+	if grade < PassingGrade {
+		c.Repetitions = 0
+		c.IntervalDays = 1
+	} else {
+		c.Repetitions++
+		switch c.Repetitions {
+		case 1:
+			c.IntervalDays = 1
+		case 2:
+			c.IntervalDays = 6
+		default:
+			c.IntervalDays = int(math.Round(float64(c.IntervalDays) * c.EaseFactor))
+		}
+	}
+
+	miss := float64(MaxGrade - grade)
+	c.EaseFactor += 0.1 - miss*(0.08+miss*0.02)
+	if c.EaseFactor < MinEaseFactor {
+		c.EaseFactor = MinEaseFactor
+	}
+
+	c.DueAt = now.AddDate(0, 0, c.IntervalDays)
+	c.LastReviewedAt = &now
+	return nil
+}
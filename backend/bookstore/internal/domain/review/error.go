@@ -0,0 +1,9 @@
+package review
+
+import "errors"
+
+var (
+	ErrCardNotFound = errors.New("review card not found")
+	ErrInvalidGrade = errors.New("grade must be between 0 and 5")
+	ErrInvalidCard  = errors.New("invalid review card")
+)
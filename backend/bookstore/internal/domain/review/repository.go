@@ -0,0 +1,14 @@
+package review
+
+import "time"
+
+// CardRepository defines methods for review card persistence.
+type CardRepository interface {
+	// CreateMany stores cards, skipping any whose SourceKey the user already
+	// has a card for, and returns the cards that were inserted.
+	CreateMany(cards []*Card) ([]*Card, error)
+	GetByID(cardID string, userID int) (*Card, error)
+	GetDue(userID int, bookID string, now time.Time, limit int) ([]*Card, error)
+	Update(card *Card) (*Card, error)
+	GetDecks(userID int, now time.Time) ([]*Deck, error)
+}
@@ -0,0 +1,133 @@
+package shelf
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Shelf kinds. Every user has one shelf of each built-in kind; a book is on
+// at most one of them at a time. Custom shelves hold any books.
+const (
+	KindWantToRead = "want_to_read"
+	KindReading    = "reading"
+	KindFinished   = "finished"
+	KindCustom     = "custom"
+)
+
+// Builtins lists the built-in shelves in their default order.
+var Builtins = []struct{ Kind, Name string }{
+	{KindWantToRead, "Want to read"},
+	{KindReading, "Reading"},
+	{KindFinished, "Finished"},
+}
+
+// IsBuiltin reports whether kind is a built-in shelf kind.
+func IsBuiltin(kind string) bool {
+	return kind == KindWantToRead || kind == KindReading || kind == KindFinished
+}
+
+// Item sources. Local items reference a book in the catalog; the others are
+// keyed by the provider's own ID.
+const (
+	SourceLocal       = "local"
+	SourceGoogleBooks = "google_books"
+	SourceOpenLibrary = "open_library"
+)
+
+const (
+	MaxNameLength    = 64
+	MaxCustomShelves = 50
+	MaxRating        = 5
+)
+
+type Shelf struct {
+	ID        string    `json:"id"`
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	Position  int       `json:"position"`
+	ItemCount int       `json:"item_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Item is a book on a shelf. The book's details are copied when it is
+// shelved, so external books and books later removed from the catalog
+// still display. Rating is the user's own 1-5 star rating, 0 if unrated.
+type Item struct {
+	ID         string    `json:"id"`
+	ShelfID    string    `json:"shelf_id"`
+	Source     string    `json:"source"`
+	ExternalID string    `json:"external_id"`
+	Title      string    `json:"title"`
+	Author     string    `json:"author"`
+	CoverUrl   string    `json:"cover_url,omitempty"`
+	ISBN       string    `json:"isbn,omitempty"`
+	Rating     int       `json:"rating,omitempty"`
+	Position   int       `json:"position"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// Contents is a shelf with its items in order.
+type Contents struct {
+	Shelf *Shelf  `json:"shelf"`
+	Items []*Item `json:"items"`
+}
+
+// ExportRow is one shelved book with every shelf it is on.
+type ExportRow struct {
+	Item
+	// Shelves holds the kinds of built-in shelves and the names of custom
+	// shelves the book is on, in shelf order.
+	Shelves  []string
+	DateRead *time.Time
+}
+
+// Export is a rendered export ready to be downloaded.
+type Export struct {
+	Filename    string
+	ContentType string
+	Body        []byte
+}
+
+// NormalizeName trims a custom shelf name and validates it. The returned
+// error wraps ErrInvalidShelf.
+func NormalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidShelf)
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidShelf, MaxNameLength)
+	}
+	return name, nil
+}
+
+// Normalize trims the item's fields and validates them. The returned error
+// wraps ErrInvalidItem.
+func (i *Item) Normalize() error {
+	i.Source = strings.TrimSpace(i.Source)
+	i.ExternalID = strings.TrimSpace(i.ExternalID)
+	i.Title = strings.TrimSpace(i.Title)
+	i.Author = strings.TrimSpace(i.Author)
+	i.CoverUrl = strings.TrimSpace(i.CoverUrl)
+	i.ISBN = strings.ReplaceAll(strings.TrimSpace(i.ISBN), "-", "")
+
+	if i.Source == "" {
+		i.Source = SourceGoogleBooks
+	}
+	if i.Source != SourceLocal && i.Source != SourceGoogleBooks && i.Source != SourceOpenLibrary {
+		return fmt.Errorf("%w: source must be local, google_books or open_library", ErrInvalidItem)
+	}
+	if i.ExternalID == "" {
+		return fmt.Errorf("%w: a book_id or external_id is required", ErrInvalidItem)
+	}
+	if i.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidItem)
+	}
+	if i.Rating < 0 || i.Rating > MaxRating {
+		return fmt.Errorf("%w: rating must be between 0 and %d", ErrInvalidItem, MaxRating)
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package shelf
+
+import "errors"
+
+var (
+	ErrShelfNotFound  = errors.New("shelf not found")
+	ErrItemNotFound   = errors.New("shelf item not found")
+	ErrShelfExists    = errors.New("a shelf with this name already exists")
+	ErrBuiltinShelf   = errors.New("built-in shelves cannot be renamed or deleted")
+	ErrTooManyShelves = errors.New("too many shelves")
+	ErrInvalidShelf   = errors.New("invalid shelf")
+	ErrInvalidItem    = errors.New("invalid shelf item")
+	ErrInvalidOrder   = errors.New("order must list every id on the shelf exactly once")
+)
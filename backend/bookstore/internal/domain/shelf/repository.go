@@ -0,0 +1,31 @@
+package shelf
+
+// Repository defines methods for shelf persistence. ref arguments accept a
+// shelf ID or a built-in kind.
+type Repository interface {
+	// EnsureBuiltins creates the user's built-in shelves if they are missing.
+	EnsureBuiltins(userID int) error
+	// ListShelves returns the user's shelves in order, with item counts.
+	ListShelves(userID int) ([]*Shelf, error)
+	GetShelf(userID int, ref string) (*Shelf, error)
+	// CreateShelf appends a custom shelf. It returns ErrShelfExists if the
+	// user already has a shelf with that name.
+	CreateShelf(s *Shelf) (*Shelf, error)
+	RenameShelf(userID int, id, name string) (*Shelf, error)
+	DeleteShelf(userID int, id string) error
+	// ReorderShelves sets the order of all of the user's shelves.
+	ReorderShelves(userID int, ids []string) error
+
+	ListItems(shelfID string) ([]*Item, error)
+	// AddItem appends an item to a shelf, or returns the existing item if
+	// the book is already on it, updating its rating if one is given.
+	// Adding to a built-in shelf removes the book from the user's other
+	// built-in shelves.
+	AddItem(userID int, item *Item) (*Item, error)
+	RemoveItem(shelfID, itemID string) error
+	// ReorderItems sets the order of all items on a shelf.
+	ReorderItems(shelfID string, ids []string) error
+	// ListExportRows returns every book on the user's shelves, or on one
+	// shelf when shelfID is not empty.
+	ListExportRows(userID int, shelfID string) ([]*ExportRow, error)
+}
@@ -0,0 +1,12 @@
+package social
+
+import "time"
+
+// Friend is a user on someone's friends list. Only public profile fields
+// are exposed.
+type Friend struct {
+	UserID       int       `json:"user_id"`
+	Username     string    `json:"username"`
+	ProfileImage *string   `json:"profile_image,omitempty"`
+	Since        time.Time `json:"since"`
+}
@@ -0,0 +1,9 @@
+package social
+
+import "errors"
+
+var (
+	ErrUserNotFound   = errors.New("user not found")
+	ErrFriendNotFound = errors.New("friend not found")
+	ErrInvalidFriend  = errors.New("you cannot add yourself as a friend")
+)
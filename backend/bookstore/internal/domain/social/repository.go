@@ -0,0 +1,10 @@
+package social
+
+// FriendRepository defines methods for friends list persistence. Friends
+// lists are one-directional: adding someone does not add you to theirs.
+type FriendRepository interface {
+	// AddFriend returns ErrUserNotFound if friendID is not a user.
+	AddFriend(userID, friendID int) error
+	RemoveFriend(userID, friendID int) error
+	ListFriends(userID int) ([]*Friend, error)
+}
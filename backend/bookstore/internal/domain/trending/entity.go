@@ -0,0 +1,129 @@
+package trending
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signal kinds. Downloads and quizzes are recorded as events; reads and
+// notes are read from reading progress and the notes table.
+const (
+	KindDownload = "download"
+	KindRead     = "read"
+	KindNote     = "note"
+	KindQuiz     = "quiz"
+)
+
+// Kinds lists every signal kind.
+var Kinds = []string{KindDownload, KindRead, KindNote, KindQuiz}
+
+// Config tunes how trending scores are computed and blended with external
+// suggestions.
+type Config struct {
+	// HalfLife is how long it takes a signal to lose half its weight.
+	HalfLife time.Duration
+	// Window is how far back signals are counted.
+	Window time.Duration
+	// RefreshInterval is how often scores are recomputed.
+	RefreshInterval time.Duration
+	// Signals weighs each signal kind.
+	Signals map[string]float64
+	// LocalWeight and ExternalWeight weigh our own trending books against
+	// the external provider's suggestions when the two lists are blended.
+	LocalWeight    float64
+	ExternalWeight float64
+	// ExternalSubject is the subject external suggestions are drawn from.
+	ExternalSubject string
+	// Limit is how many books /books/trending returns.
+	Limit int
+}
+
+// DefaultConfig favours our own catalog and counts a download as worth more
+// than a note or a quiz.
+func DefaultConfig() Config {
+	return Config{
+		HalfLife:        72 * time.Hour,
+		Window:          30 * 24 * time.Hour,
+		RefreshInterval: 15 * time.Minute,
+		Signals: map[string]float64{
+			KindDownload: 3,
+			KindRead:     2,
+			KindNote:     1,
+			KindQuiz:     1,
+		},
+		LocalWeight:     0.7,
+		ExternalWeight:  0.3,
+		ExternalSubject: "fiction",
+		Limit:           20,
+	}
+}
+
+// Validate checks the config. The returned error wraps ErrInvalidConfig.
+func (c Config) Validate() error {
+	if c.HalfLife <= 0 || c.Window <= 0 || c.RefreshInterval <= 0 {
+		return fmt.Errorf("%w: durations must be positive", ErrInvalidConfig)
+	}
+	if c.LocalWeight < 0 || c.ExternalWeight < 0 || c.LocalWeight+c.ExternalWeight == 0 {
+		return fmt.Errorf("%w: blend weights must be non-negative and not both zero", ErrInvalidConfig)
+	}
+	for kind, w := range c.Signals {
+		if !validKind(kind) {
+			return fmt.Errorf("%w: unknown signal %q", ErrInvalidConfig, kind)
+		}
+		if w < 0 {
+			return fmt.Errorf("%w: signal %q has a negative weight", ErrInvalidConfig, kind)
+		}
+	}
+	if c.Limit <= 0 {
+		return fmt.Errorf("%w: limit must be positive", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// ParseSignalWeights parses a comma-separated list such as
+// "download=3,read=2". Kinds not listed keep their default weight.
+func ParseSignalWeights(list string) (map[string]float64, error) {
+	weights := DefaultConfig().Signals
+	for _, pair := range strings.Split(list, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kind, value, ok := strings.Cut(pair, "=")
+		kind = strings.TrimSpace(kind)
+		if !ok || !validKind(kind) {
+			return nil, fmt.Errorf("%w: bad signal weight %q", ErrInvalidConfig, pair)
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("%w: bad signal weight %q", ErrInvalidConfig, pair)
+		}
+		weights[kind] = w
+	}
+	return weights, nil
+}
+
+func validKind(kind string) bool {
+	return slices.Contains(Kinds, kind)
+}
+
+// Event is a recorded signal. BookTitle identifies the book when the caller
+// only knows its title, as quizzes do; events for titles not in the catalog
+// are dropped.
+type Event struct {
+	BookID     string
+	BookTitle  string
+	UserID     int
+	Kind       string
+	OccurredAt time.Time
+}
+
+// Score is a book's decayed engagement as of the last refresh.
+type Score struct {
+	BookID      string
+	Score       float64
+	RefreshedAt time.Time
+}
@@ -0,0 +1,7 @@
+package trending
+
+import "errors"
+
+var (
+	ErrInvalidConfig = errors.New("invalid trending config")
+)
@@ -0,0 +1,13 @@
+package trending
+
+import "time"
+
+// Repository stores trending signals and the scores computed from them.
+type Repository interface {
+	Record(e *Event) error
+	// Refresh recomputes every book's score as of now and returns how many
+	// books have one.
+	Refresh(cfg Config, now time.Time) (int, error)
+	// Top returns the highest scores from the last refresh.
+	Top(limit int) ([]*Score, error)
+}
@@ -19,8 +19,16 @@ type User struct {
 	ReadingStreak  int        `json:"readingStreak"`
 	LastReadDate   *time.Time `json:"lastReadDate,omitempty"`
 	Points         int        `json:"points"`
+	// Timezone is the IANA zone reading streaks are counted in.
+	Timezone string `json:"timezone"`
+	// Role is identity.RoleUser or identity.RoleAdmin. Only an admin can
+	// change it, so it is never read from request bodies.
+	Role string `json:"role"`
 }
 
+// DefaultTimezone is used until the user sets a timezone.
+const DefaultTimezone = "UTC"
+
 func NewUser(
 	id int,
 	username, email, passwordHash string,
@@ -145,6 +153,8 @@ func (u *User) UnmarshalJSON(data []byte) error {
 	// points
 	u.Points = toIntDefault(raw["points"], 0)
 
+	u.Timezone = toString(raw["timezone"])
+
 	return nil
 }
 
@@ -161,6 +171,8 @@ func (u User) MarshalJSON() ([]byte, error) {
 		ReadingStreak  int        `json:"reading_streak"`
 		LastReadDate   *string    `json:"last_read_date,omitempty"`
 		Points         int        `json:"points"`
+		Timezone       string     `json:"timezone"`
+		Role           string     `json:"role"`
 	}
 	var last *string
 	if u.LastReadDate != nil {
@@ -179,6 +191,8 @@ func (u User) MarshalJSON() ([]byte, error) {
 		ReadingStreak:  u.ReadingStreak,
 		LastReadDate:   last,
 		Points:         u.Points,
+		Timezone:       u.Timezone,
+		Role:           u.Role,
 	})
 }
 
@@ -6,5 +6,8 @@ type UserRepository interface{
 	UpdateUser(user User) (User, error)
 	DeleteUser(id string) error
 	GetAllUsers() ([]User, error)
+	// GetUserByEmail matches email regardless of case.
 	GetUserByEmail(email string) (User, error)
+	// SetRole returns ErrNotFound if there is no user with id.
+	SetRole(id int, role string) error
 }
\ No newline at end of file
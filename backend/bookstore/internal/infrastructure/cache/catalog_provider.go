@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+)
+
+// DefaultProviderTTL is how long external book metadata is cached. Book
+// details rarely change and trending lists are fine an hour stale.
+const DefaultProviderTTL = time.Hour
+
+// CachedProvider is a catalog.ExternalProvider that serves repeated lookups
+// from a Store. Books the provider does not have are cached too, so
+// unmatched imports do not hit the provider again.
+type CachedProvider struct {
+	next  catalog.ExternalProvider
+	store Store
+	ttl   time.Duration
+	// name keeps the entries of different providers apart in a shared store.
+	name string
+}
+
+var _ catalog.ExternalProvider = (*CachedProvider)(nil)
+
+func NewCachedProvider(next catalog.ExternalProvider, name string, store Store, ttl time.Duration) *CachedProvider {
+	if ttl <= 0 {
+		ttl = DefaultProviderTTL
+	}
+	return &CachedProvider{next: next, store: store, ttl: ttl, name: name}
+}
+
+func (p *CachedProvider) LookupISBN(ctx context.Context, isbn string) (*catalog.ExternalBook, error) {
+	return cachedBook(p, "isbn", isbn, func() (*catalog.ExternalBook, error) {
+		return p.next.LookupISBN(ctx, isbn)
+	})
+}
+
+func (p *CachedProvider) Lookup(ctx context.Context, id string) (*catalog.ExternalBook, error) {
+	return cachedBook(p, "id", id, func() (*catalog.ExternalBook, error) {
+		return p.next.Lookup(ctx, id)
+	})
+}
+
+func (p *CachedProvider) Search(ctx context.Context, query string, limit int) ([]*catalog.ExternalBook, error) {
+	return cachedProvider(p, "search", normalizePrompt(query)+"\x00"+strconv.Itoa(limit), func() ([]*catalog.ExternalBook, error) {
+		return p.next.Search(ctx, query, limit)
+	})
+}
+
+func (p *CachedProvider) Trending(ctx context.Context, subject string, limit int) ([]*catalog.ExternalBook, error) {
+	return cachedProvider(p, "trending", strings.ToLower(subject)+"\x00"+strconv.Itoa(limit), func() ([]*catalog.ExternalBook, error) {
+		return p.next.Trending(ctx, subject, limit)
+	})
+}
+
+// cachedBook caches a single-book lookup, storing a miss as null.
+func cachedBook(p *CachedProvider, kind, key string, load func() (*catalog.ExternalBook, error)) (*catalog.ExternalBook, error) {
+	b, err := cachedProvider(p, kind, key, func() (*catalog.ExternalBook, error) {
+		b, err := load()
+		if errors.Is(err, catalog.ErrExternalNotFound) {
+			return nil, nil
+		}
+		return b, err
+	})
+	if err == nil && b == nil {
+		return nil, catalog.ErrExternalNotFound
+	}
+	return b, err
+}
+
+// cachedProvider looks the request up in the store and falls back to load
+// on a miss. Errors are never cached, and store failures are logged rather
+// than failing the lookup.
+func cachedProvider[T any](p *CachedProvider, kind, key string, load func() (T, error)) (T, error) {
+	storeKey := "catalog:" + p.name + ":" + kind + ":" + key
+	if data, err := p.store.Get(storeKey); err == nil {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+		log.Printf("catalog cache: discarding undecodable %s entry", kind)
+	} else if !errors.Is(err, ErrMiss) {
+		log.Printf("catalog cache: get %s: %v", kind, err)
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = p.store.Set(storeKey, data, p.ttl)
+	}
+	if err != nil {
+		log.Printf("catalog cache: set %s: %v", kind, err)
+	}
+	return value, nil
+}
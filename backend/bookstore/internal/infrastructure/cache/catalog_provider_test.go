@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/externalapis"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/externalapis/externalapistest"
+)
+
+func newCachedOpenLibrary(t *testing.T, store Store, ttl time.Duration) (*CachedProvider, *externalapistest.Server) {
+	t.Helper()
+	s := externalapistest.NewServer(&catalog.ExternalBook{
+		ID:         "OL66554W",
+		Title:      "Emma",
+		Authors:    []string{"Jane Austen"},
+		ISBN13:     "9780141439587",
+		Categories: []string{"Classics"},
+	})
+	t.Cleanup(s.Close)
+	p := externalapis.NewOpenLibraryProviderWithOptions(externalapis.ProviderOptions{
+		BaseURL:      s.OpenLibraryURL(),
+		RetryBackoff: time.Millisecond,
+	})
+	return NewCachedProvider(p, "open_library", store, ttl), s
+}
+
+func TestCachedProviderServesRepeatsFromTheStore(t *testing.T) {
+	p, s := newCachedOpenLibrary(t, NewMemoryStore(100), time.Hour)
+	ctx := context.Background()
+
+	for range 3 {
+		b, err := p.LookupISBN(ctx, "9780141439587")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Title != "Emma" {
+			t.Fatalf("got %q, want Emma", b.Title)
+		}
+		if _, err := p.Search(ctx, "Austen", 5); err != nil {
+			t.Fatal(err)
+		}
+		// queries differing only in case and spacing share an entry
+		if _, err := p.Search(ctx, "  austen ", 5); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := s.Requests(); got != 2 {
+		t.Errorf("provider got %d requests, want 2", got)
+	}
+
+	if _, err := p.Search(ctx, "Austen", 10); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Requests(); got != 3 {
+		t.Errorf("a different limit was served from the cache: %d requests, want 3", got)
+	}
+}
+
+func TestCachedProviderCachesMissesButNotErrors(t *testing.T) {
+	p, s := newCachedOpenLibrary(t, NewMemoryStore(100), time.Hour)
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := p.LookupISBN(ctx, "0000000000"); !errors.Is(err, catalog.ErrExternalNotFound) {
+			t.Fatalf("got %v, want ErrExternalNotFound", err)
+		}
+	}
+	if got := s.Requests(); got != 1 {
+		t.Errorf("repeated miss made %d requests, want 1", got)
+	}
+
+	s.FailNext(10)
+	if _, err := p.Lookup(ctx, "OL66554W"); !errors.Is(err, catalog.ErrExternalUnavailable) {
+		t.Fatalf("got %v, want ErrExternalUnavailable", err)
+	}
+	s.FailNext(0)
+	b, err := p.Lookup(ctx, "OL66554W")
+	if err != nil {
+		t.Fatalf("failure was cached: %v", err)
+	}
+	if b.Title != "Emma" {
+		t.Errorf("got %q, want Emma", b.Title)
+	}
+}
+
+func TestCachedProviderExpiresEntries(t *testing.T) {
+	store, now := NewMemoryStore(100), time.Now()
+	store.now = func() time.Time { return now }
+	p, s := newCachedOpenLibrary(t, store, time.Hour)
+	ctx := context.Background()
+
+	if _, err := p.Trending(ctx, "Classics", 5); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour + time.Second)
+	if _, err := p.Trending(ctx, "classics", 5); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Requests(); got != 2 {
+		t.Errorf("expired entry: provider got %d requests, want 2", got)
+	}
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/chat"
+)
+
+// Endpoint names a cacheable ChatRepository operation.
+type Endpoint string
+
+const (
+	EndpointMultipleChoice   Endpoint = "multiple_choice"
+	EndpointTrueFalse        Endpoint = "true_false"
+	EndpointShortAnswer      Endpoint = "short_answer"
+	EndpointGradeShortAnswer Endpoint = "grade_short_answer"
+	EndpointChatResponse     Endpoint = "chat_response"
+)
+
+const DefaultTTL = 24 * time.Hour
+
+// Options configures a CachedChatRepository.
+type Options struct {
+	// Model identifies the model behind the wrapped repository. It is part
+	// of every key so switching models does not serve stale answers.
+	Model string
+	TTL   time.Duration
+	// Disabled lists endpoints that always bypass the cache.
+	Disabled map[Endpoint]bool
+}
+
+// EndpointStats counts cache lookups for one endpoint.
+type EndpointStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Errors int64 `json:"errors"`
+}
+
+// CachedChatRepository is a chat.ChatRepository that serves repeated
+// requests from a Store instead of calling the wrapped repository.
+// Streaming responses are never cached.
+type CachedChatRepository struct {
+	next  chat.ChatRepository
+	store Store
+	opts  Options
+
+	mu    sync.Mutex
+	stats map[Endpoint]*EndpointStats
+}
+
+var _ chat.ChatRepository = (*CachedChatRepository)(nil)
+
+func NewCachedChatRepository(next chat.ChatRepository, store Store, opts Options) *CachedChatRepository {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	return &CachedChatRepository{
+		next:  next,
+		store: store,
+		opts:  opts,
+		stats: make(map[Endpoint]*EndpointStats),
+	}
+}
+
+func (r *CachedChatRepository) GetMultipleChoiceQuestion(id string, prompt string) ([]*chat.MultipleQuiz, error) {
+	return cached(r, EndpointMultipleChoice, prompt, func() ([]*chat.MultipleQuiz, error) {
+		return r.next.GetMultipleChoiceQuestion(id, prompt)
+	})
+}
+
+func (r *CachedChatRepository) GetTrueFalseQuestion(id string, prompt string) ([]*chat.TrueFalse, error) {
+	return cached(r, EndpointTrueFalse, prompt, func() ([]*chat.TrueFalse, error) {
+		return r.next.GetTrueFalseQuestion(id, prompt)
+	})
+}
+
+func (r *CachedChatRepository) GetShortAnswerQuestion(id string, prompt string) ([]*chat.ShortAnswer, error) {
+	return cached(r, EndpointShortAnswer, prompt, func() ([]*chat.ShortAnswer, error) {
+		return r.next.GetShortAnswerQuestion(id, prompt)
+	})
+}
+
+func (r *CachedChatRepository) GradeShortAnswer(id string, prompt string) (*chat.ShortAnswerGrade, error) {
+	return cached(r, EndpointGradeShortAnswer, prompt, func() (*chat.ShortAnswerGrade, error) {
+		return r.next.GradeShortAnswer(id, prompt)
+	})
+}
+
+func (r *CachedChatRepository) GetChatResponses(chatID int, prompt string) (*chat.ChatResponse, error) {
+	resp, err := cached(r, EndpointChatResponse, prompt, func() (*chat.ChatResponse, error) {
+		return r.next.GetChatResponses(chatID, prompt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The cached response may have been produced for another chat.
+	resp.ID = chatID
+	resp.ChatID = chatID
+	return resp, nil
+}
+
+func (r *CachedChatRepository) GetChatResponseStream(ctx context.Context, prompt string) (<-chan string, error) {
+	return r.next.GetChatResponseStream(ctx, prompt)
+}
+
+// Stats returns a snapshot of the hit/miss counters per endpoint.
+func (r *CachedChatRepository) Stats() map[Endpoint]EndpointStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make(map[Endpoint]EndpointStats, len(r.stats))
+	for endpoint, s := range r.stats {
+		out[endpoint] = *s
+	}
+	return out
+}
+
+func (r *CachedChatRepository) record(endpoint Endpoint, update func(*EndpointStats)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.stats[endpoint]
+	if !ok {
+		s = &EndpointStats{}
+		r.stats[endpoint] = s
+	}
+	update(s)
+}
+
+// cached looks the request up in the store and falls back to load on a miss.
+// Store failures are logged and never fail the request.
+func cached[T any](r *CachedChatRepository, endpoint Endpoint, prompt string, load func() (T, error)) (T, error) {
+	if r.opts.Disabled[endpoint] {
+		return load()
+	}
+
+	key := r.key(endpoint, prompt)
+	if data, err := r.store.Get(key); err == nil {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			r.record(endpoint, func(s *EndpointStats) { s.Hits++ })
+			return value, nil
+		}
+		log.Printf("ai cache: discarding undecodable %s entry", endpoint)
+	} else if !errors.Is(err, ErrMiss) {
+		r.record(endpoint, func(s *EndpointStats) { s.Errors++ })
+		log.Printf("ai cache: get %s: %v", endpoint, err)
+	}
+	r.record(endpoint, func(s *EndpointStats) { s.Misses++ })
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = r.store.Set(key, data, r.opts.TTL)
+	}
+	if err != nil {
+		r.record(endpoint, func(s *EndpointStats) { s.Errors++ })
+		log.Printf("ai cache: set %s: %v", endpoint, err)
+	}
+	return value, nil
+}
+
+// key derives the cache key from the endpoint, the model and the normalized
+// prompt. Generation parameters are fixed per endpoint, so the endpoint name
+// stands in for them.
+func (r *CachedChatRepository) key(endpoint Endpoint, prompt string) string {
+	sum := sha256.Sum256([]byte(string(endpoint) + "\x00" + r.opts.Model + "\x00" + normalizePrompt(prompt)))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizePrompt makes prompts that differ only in case or whitespace share
+// a cache entry.
+func normalizePrompt(prompt string) string {
+	return strings.ToLower(strings.Join(strings.Fields(prompt), " "))
+}
+
+// ParseDisabled parses a comma-separated list of endpoint names such as
+// "chat_response,grade_short_answer".
+func ParseDisabled(list string) map[Endpoint]bool {
+	disabled := make(map[Endpoint]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			disabled[Endpoint(name)] = true
+		}
+	}
+	return disabled
+}
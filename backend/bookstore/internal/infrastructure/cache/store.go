@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Store.Get when the key is absent or expired.
+var ErrMiss = errors.New("cache miss")
+
+// Store is a key/value store with per-entry expiry used to cache AI
+// responses. Implementations must be safe for concurrent use.
+type Store interface {
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte, ttl time.Duration) error
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/achievement"
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+)
+
+var _ achievement.Repository = (*AchievementRepositoryPostgres)(nil)
+
+type AchievementRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewAchievementRepositoryPostgres(db *sql.DB) *AchievementRepositoryPostgres {
+	return &AchievementRepositoryPostgres{db: db}
+}
+
+// CreateAchievementTable creates the user_achievements table if it doesn't exist.
+func (r *AchievementRepositoryPostgres) CreateAchievementTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS user_achievements (
+			user_id INTEGER NOT NULL,
+			badge_id VARCHAR(64) NOT NULL,
+			awarded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (user_id, badge_id)
+		);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// GetMetrics derives the metrics from the user's counters and activity events.
+func (r *AchievementRepositoryPostgres) GetMetrics(userID int) (achievement.Metrics, error) {
+	query := `
+		SELECT
+			u.reading_streak,
+			u.points,
+			COUNT(e.id) FILTER (WHERE e.type = $2),
+			COALESCE(SUM(e.value) FILTER (WHERE e.type = $3), 0) / 60,
+			COUNT(e.id) FILTER (WHERE e.type IN ($4, $5)),
+			COUNT(e.id) FILTER (WHERE e.type = $5),
+			COUNT(e.id) FILTER (WHERE e.type = $6),
+			COUNT(e.id) FILTER (WHERE e.type = $6 AND e.value >= 100),
+			COUNT(e.id) FILTER (WHERE e.type = $7)
+		FROM users u
+		LEFT JOIN activity_events e ON e.user_id = u.id
+		WHERE u.id = $1
+		GROUP BY u.id
+	`
+	var streak, points, finished, minutes, notes, aiNotes, answered, aced, shared int
+	err := r.db.QueryRow(query, userID, activity.TypeBookFinished, activity.TypeReadingSession,
+		activity.TypeNoteCreated, activity.TypeAINoteCreated, activity.TypeQuizAnswer, activity.TypeBookShared,
+	).Scan(&streak, &points, &finished, &minutes, &notes, &aiNotes, &answered, &aced, &shared)
+	if err == sql.ErrNoRows {
+		return nil, activity.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return achievement.Metrics{
+		achievement.MetricReadingStreak:   streak,
+		achievement.MetricPoints:          points,
+		achievement.MetricBooksFinished:   finished,
+		achievement.MetricReadingMinutes:  minutes,
+		achievement.MetricNotesCreated:    notes,
+		achievement.MetricAINotesCreated:  aiNotes,
+		achievement.MetricQuizzesAnswered: answered,
+		achievement.MetricQuizzesAced:     aced,
+		achievement.MetricBooksShared:     shared,
+	}, nil
+}
+
+// Award stores the badge unless the user already has it.
+func (r *AchievementRepositoryPostgres) Award(userID int, badgeID string, at time.Time) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO user_achievements (user_id, badge_id, awarded_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, badge_id) DO NOTHING
+	`, userID, badgeID, at)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListAwards returns the user's badges, oldest first.
+func (r *AchievementRepositoryPostgres) ListAwards(userID int) ([]*achievement.Award, error) {
+	rows, err := r.db.Query(`
+		SELECT user_id, badge_id, awarded_at FROM user_achievements
+		WHERE user_id = $1
+		ORDER BY awarded_at ASC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var awards []*achievement.Award
+	for rows.Next() {
+		var a achievement.Award
+		if err := rows.Scan(&a.UserID, &a.BadgeID, &a.AwardedAt); err != nil {
+			return nil, err
+		}
+		awards = append(awards, &a)
+	}
+	return awards, rows.Err()
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/google/uuid"
+)
+
+var _ activity.Repository = (*ActivityRepositoryPostgres)(nil)
+
+// streakLookback is how many streak days are read to recompute a streak.
+const streakLookback = 400
+
+type ActivityRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewActivityRepositoryPostgres(db *sql.DB) *ActivityRepositoryPostgres {
+	return &ActivityRepositoryPostgres{db: db}
+}
+
+// CreateActivityTable creates the activity_events table if it doesn't exist
+// and adds the timezone column streaks are counted in to users.
+func (r *ActivityRepositoryPostgres) CreateActivityTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS activity_events (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			type VARCHAR(32) NOT NULL,
+			source_key VARCHAR(128) NOT NULL,
+			points INTEGER NOT NULL DEFAULT 0,
+			streak_day BOOLEAN NOT NULL DEFAULT FALSE,
+			occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			local_date DATE NOT NULL,
+			UNIQUE (user_id, source_key)
+		);
+		CREATE INDEX IF NOT EXISTS idx_activity_events_user_day ON activity_events(user_id, local_date);
+		ALTER TABLE activity_events ADD COLUMN IF NOT EXISTS value INTEGER NOT NULL DEFAULT 0;
+
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// Record stores the event and updates the user's points, streak and last
+// read date in one transaction. The user row is locked so concurrent events
+// of the same user are applied one at a time.
+func (r *ActivityRepositoryPostgres) Record(e *activity.Event) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var timezone string
+	err = tx.QueryRow(`SELECT timezone FROM users WHERE id = $1 FOR UPDATE`, e.UserID).Scan(&timezone)
+	if err == sql.ErrNoRows {
+		return false, activity.ErrUserNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+	e.LocalDate = activity.LocalDate(e.OccurredAt, activity.Location(timezone))
+
+	if limit, ok := activity.DailyPointCap[e.Type]; ok && e.Points > 0 {
+		var earned int
+		err := tx.QueryRow(`
+			SELECT COALESCE(SUM(points), 0) FROM activity_events
+			WHERE user_id = $1 AND type = $2 AND local_date = $3
+		`, e.UserID, e.Type, e.LocalDate).Scan(&earned)
+		if err != nil {
+			return false, err
+		}
+		e.Points = max(0, min(e.Points, limit-earned))
+	}
+
+	res, err := tx.Exec(`
+		INSERT INTO activity_events (id, user_id, type, source_key, points, value, streak_day, occurred_at, local_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id, source_key) DO NOTHING
+	`, e.ID, e.UserID, e.Type, e.SourceKey, e.Points, e.Value, e.StreakDay, e.OccurredAt, e.LocalDate)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	if e.Points > 0 {
+		if _, err := tx.Exec(`UPDATE users SET points = points + $1 WHERE id = $2`, e.Points, e.UserID); err != nil {
+			return false, err
+		}
+	}
+
+	if e.StreakDay {
+		days, err := queryStreakDays(tx, e.UserID, streakLookback)
+		if err != nil {
+			return false, err
+		}
+		streak := activity.Streak(days, e.LocalDate, activity.GraceDays)
+		if _, err := tx.Exec(`
+			UPDATE users SET reading_streak = $1, last_read_date = GREATEST(last_read_date, $2)
+			WHERE id = $3
+		`, streak, e.OccurredAt, e.UserID); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// GetStats returns the counters stored on the user.
+func (r *ActivityRepositoryPostgres) GetStats(userID int) (*activity.Stats, error) {
+	var s activity.Stats
+	err := r.db.QueryRow(`
+		SELECT points, books_read_count, reading_streak, last_read_date, timezone
+		FROM users WHERE id = $1
+	`, userID).Scan(&s.Points, &s.BooksReadCount, &s.ReadingStreak, &s.LastReadDate, &s.Timezone)
+	if err == sql.ErrNoRows {
+		return nil, activity.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *ActivityRepositoryPostgres) GetStreakDays(userID int, limit int) ([]time.Time, error) {
+	return queryStreakDays(r.db, userID, limit)
+}
+
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func queryStreakDays(q queryer, userID int, limit int) ([]time.Time, error) {
+	rows, err := q.Query(`
+		SELECT DISTINCT local_date FROM activity_events
+		WHERE user_id = $1 AND streak_day
+		ORDER BY local_date DESC
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
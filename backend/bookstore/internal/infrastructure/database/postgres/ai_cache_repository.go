@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/infrastructure/cache"
+)
+
+// AICacheRepositoryPostgres is a cache.Store backed by the ai_response_cache
+// table, so cached AI responses survive restarts and are shared between
+// instances.
+type AICacheRepositoryPostgres struct {
+	db *sql.DB
+}
+
+var _ cache.Store = (*AICacheRepositoryPostgres)(nil)
+
+func NewAICacheRepositoryPostgres(db *sql.DB) *AICacheRepositoryPostgres {
+	return &AICacheRepositoryPostgres{db: db}
+}
+
+// CreateAICacheTable creates the ai_response_cache table if it doesn't exist.
+func (r *AICacheRepositoryPostgres) CreateAICacheTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS ai_response_cache (
+			cache_key VARCHAR(64) PRIMARY KEY,
+			value TEXT NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_ai_response_cache_expires ON ai_response_cache(expires_at);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// Get returns the cached value for key, or cache.ErrMiss if it is absent or expired.
+func (r *AICacheRepositoryPostgres) Get(key string) ([]byte, error) {
+	query := `SELECT value FROM ai_response_cache WHERE cache_key = $1 AND expires_at > NOW()`
+	var value string
+	if err := r.db.QueryRow(query, key).Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, cache.ErrMiss
+		}
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+// Set stores value under key, replacing any previous entry.
+func (r *AICacheRepositoryPostgres) Set(key string, value []byte, ttl time.Duration) error {
+	query := `
+		INSERT INTO ai_response_cache (cache_key, value, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (cache_key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+	`
+	_, err := r.db.Exec(query, key, string(value), time.Now().Add(ttl))
+	return err
+}
+
+// DeleteExpired removes expired entries and returns how many were deleted.
+func (r *AICacheRepositoryPostgres) DeleteExpired() (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM ai_response_cache WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
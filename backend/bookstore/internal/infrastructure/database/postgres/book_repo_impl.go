@@ -2,6 +2,9 @@ package postgres
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
+
 	"github.com/bereke1t2/bookstore/internal/domain/book"
 )
 
@@ -24,60 +27,172 @@ func (r *BookRepositoryImpl) DeleteBook(id string) error {
 	return nil
 }
 
-func (r *BookRepositoryImpl) CreateBook(book *book.Book) (*book.Book, error) {
-	print("creating book in repo")
-	print("with book url: ", book.BookURL)
-	print("with image url: ", book.CoverUrl)
-	query := "INSERT INTO books (title, author, price, rating , category, is_featured, shared_by, tag, cover_url , book_url) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9 , $10)"
-	_, err := r.db.Exec(query, book.Title, book.Author, book.Price, book.Rating, book.Category, book.IsFeatured, book.SharedBy, book.Tag, book.CoverUrl, book.BookURL)
-	if err != nil {
+// CreateBookColumns adds the ISBN, source attribution, uploader and
+// creation time columns to books. ISBN-13 is unique so an imported book is stored only
+// once. Books added before created_at existed are dated to the migration.
+func (r *BookRepositoryImpl) CreateBookColumns() error {
+	query := `
+		ALTER TABLE books ADD COLUMN IF NOT EXISTS isbn10 VARCHAR(10);
+		ALTER TABLE books ADD COLUMN IF NOT EXISTS isbn13 VARCHAR(13);
+		ALTER TABLE books ADD COLUMN IF NOT EXISTS source VARCHAR(32) NOT NULL DEFAULT 'upload';
+		ALTER TABLE books ADD COLUMN IF NOT EXISTS source_id VARCHAR(128);
+		ALTER TABLE books ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+		ALTER TABLE books ADD COLUMN IF NOT EXISTS uploaded_by INTEGER;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_books_isbn13 ON books (isbn13) WHERE isbn13 IS NOT NULL;
+		CREATE INDEX IF NOT EXISTS idx_books_source ON books (source, source_id) WHERE source_id IS NOT NULL;
+		CREATE INDEX IF NOT EXISTS idx_books_created_at ON books (created_at DESC);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// bookColumns is the column list scanned by scanBook.
+const bookColumns = "id, title, author, price, rating , rating_count , category , is_featured , shared_by , tag , cover_url , book_url , COALESCE(isbn10, '') , COALESCE(isbn13, '') , source , COALESCE(source_id, '') , created_at , COALESCE(uploaded_by, 0)"
+
+func scanBook(row rowScanner) (*book.Book, error) {
+	var b book.Book
+	if err := row.Scan(&b.ID, &b.Title, &b.Author, &b.Price, &b.Rating, &b.RatingCount, &b.Category, &b.IsFeatured, &b.SharedBy, &b.Tag, &b.CoverUrl, &b.BookURL, &b.ISBN10, &b.ISBN13, &b.Source, &b.SourceID, &b.CreatedAt, &b.UploadedBy); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *BookRepositoryImpl) CreateBook(bk *book.Book) (*book.Book, error) {
+	if bk.Source == "" {
+		bk.Source = book.SourceUpload
+	}
+	query := `INSERT INTO books (title, author, price, rating , category, is_featured, shared_by, tag, cover_url , book_url, isbn10, isbn13, source, source_id, uploaded_by)
+		VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9 , $10, NULLIF($11, ''), NULLIF($12, ''), $13, NULLIF($14, ''), NULLIF($15, 0))
+		RETURNING id::text, created_at`
+	if err := r.db.QueryRow(query, bk.Title, bk.Author, bk.Price, bk.Rating, bk.Category, bk.IsFeatured, bk.SharedBy, bk.Tag, bk.CoverUrl, bk.BookURL,
+		bk.ISBN10, bk.ISBN13, bk.Source, bk.SourceID, bk.UploadedBy).Scan(&bk.ID, &bk.CreatedAt); err != nil {
 		print("error creating book in repo: ", err.Error())
 		return nil, err
 	}
-	print("book created in repo")
-	print("with book url: ", book.BookURL)
-	print("with image url: ", book.CoverUrl)
-	return book, nil
+	return bk, nil
 }
 func (r *BookRepositoryImpl) GetBookByID(id string) (*book.Book, error) {
-	query := "SELECT id, title, author, price, rating , category , is_featured , shared_by , tag ,cover_url, book_url FROM books WHERE id = $1"
-	row := r.db.QueryRow(query, id)
-	print("fetching book by id in repo: ", id)
-	var b book.Book
-	if err := row.Scan(&b.ID, &b.Title, &b.Author, &b.Price, &b.Rating, &b.Category, &b.IsFeatured, &b.SharedBy, &b.Tag, &b.CoverUrl, &b.BookURL); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
+	return r.getBook("id = $1", id)
+}
+
+func (r *BookRepositoryImpl) GetBookByISBN(isbn13 string) (*book.Book, error) {
+	return r.getBook("isbn13 = $1", isbn13)
+}
+
+func (r *BookRepositoryImpl) GetBookByTitle(title string) (*book.Book, error) {
+	return r.getBook(normalizedTitle+" = $1", book.NormalizeTitle(title))
+}
+
+func (r *BookRepositoryImpl) GetBookBySource(source, sourceID string) (*book.Book, error) {
+	return r.getBook("source = $1 AND source_id = $2", source, sourceID)
+}
+
+// normalizedTitle is book.NormalizeTitle in SQL.
+const normalizedTitle = `LOWER(BTRIM(REGEXP_REPLACE(title, '\s+', ' ', 'g')))`
+
+// getBook returns the first book matching where, or nil if there is none.
+func (r *BookRepositoryImpl) getBook(where string, args ...any) (*book.Book, error) {
+	b, err := scanBook(r.db.QueryRow("SELECT "+bookColumns+" FROM books WHERE "+where+" LIMIT 1", args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-	return &b, nil
+	return b, err
 }
 func (r *BookRepositoryImpl) GetAllBooks() ([]*book.Book, error) {
-	query := "SELECT id, title, author, price, rating , category , is_featured , shared_by , tag , cover_url , book_url FROM books"
-	rows, err := r.db.Query(query)
+	return r.ListBooks(book.SortDefault)
+}
+
+// bookOrderBy maps listing sorts to ORDER BY clauses. The rating order is a
+// Bayesian average that pulls books with few reviews towards a prior of 3
+// stars weighted as 5 reviews.
+var bookOrderBy = map[string]string{
+	book.SortDefault: "",
+	book.SortTitle:   " ORDER BY title, id",
+	book.SortRating:  " ORDER BY (rating * rating_count + 3 * 5) / (rating_count + 5) DESC, rating_count DESC, id",
+	book.SortNewest:  " ORDER BY created_at DESC, id",
+}
+
+func (r *BookRepositoryImpl) ListBooks(sort string) ([]*book.Book, error) {
+	return r.FindBooks(book.Filter{Sort: sort})
+}
+
+func (r *BookRepositoryImpl) FindBooks(f book.Filter) ([]*book.Book, error) {
+	orderBy, ok := bookOrderBy[f.Sort]
+	if !ok {
+		return nil, book.ErrInvalidBookInput
+	}
+	var where []string
+	var args []any
+	if q := strings.TrimSpace(f.Query); q != "" {
+		args = append(args, "%"+escapeLike(q)+"%", strings.ToUpper(strings.ReplaceAll(q, "-", "")))
+		where = append(where, fmt.Sprintf("(title ILIKE $%d OR author ILIKE $%d OR isbn13 = $%d OR isbn10 = $%d)", len(args)-1, len(args)-1, len(args), len(args)))
+	}
+	if f.Title != "" {
+		args = append(args, book.NormalizeTitle(f.Title))
+		where = append(where, fmt.Sprintf("%s = $%d", normalizedTitle, len(args)))
+	}
+	if f.Category != "" {
+		args = append(args, f.Category)
+		where = append(where, fmt.Sprintf("category = $%d", len(args)))
+	}
+	if f.Featured {
+		where = append(where, "is_featured")
+	}
+
+	query := "SELECT " + bookColumns + " FROM books"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += orderBy
+	if f.Limit > 0 {
+		args = append(args, f.Limit, f.Offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var books []*book.Book
 	for rows.Next() {
-		var b book.Book
-		if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Price, &b.Rating, &b.Category, &b.IsFeatured, &b.SharedBy, &b.Tag, &b.CoverUrl, &b.BookURL); err != nil {
+		b, err := scanBook(rows)
+		if err != nil {
 			return nil, err
 		}
-		books = append(books, &b)
+		books = append(books, b)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return books, nil
 }
-func (r *BookRepositoryImpl) UpdateBook(bk *book.Book) (*book.Book, error) {
-	query := "UPDATE books SET title = $1, author = $2, price = $3, rating=$4 , category=$5 , is_featured=$6 , shared_by=$7 , tag=$8 ,  cover_url = $9 WHERE id = $10 RETURNING id, title, author, price, rating, category, is_featured, shared_by, tag, cover_url"
-	row := r.db.QueryRow(query, bk.Title, bk.Author, bk.Price, bk.Rating, bk.Category, bk.IsFeatured, bk.SharedBy, bk.Tag, bk.CoverUrl, bk.ID)
-	var updated book.Book
-	if err := row.Scan(&updated.ID, &updated.Title, &updated.Author, &updated.Price, &updated.Rating, &updated.Category, &updated.IsFeatured, &updated.SharedBy, &updated.Tag, &updated.CoverUrl); err != nil {
+
+// ListCategories returns the non-empty categories by name.
+func (r *BookRepositoryImpl) ListCategories() ([]book.Category, error) {
+	rows, err := r.db.Query("SELECT category, COUNT(*) FROM books WHERE category <> '' GROUP BY category ORDER BY category")
+	if err != nil {
 		return nil, err
 	}
-	return &updated, nil
-}
\ No newline at end of file
+	defer rows.Close()
+	var categories []book.Category
+	for rows.Next() {
+		var c book.Category
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (r *BookRepositoryImpl) UpdateBook(bk *book.Book) (*book.Book, error) {
+	query := "UPDATE books SET title = $1, author = $2, price = $3, category=$4 , is_featured=$5 , shared_by=$6 , tag=$7 ,  cover_url = $8 WHERE id = $9 RETURNING " + bookColumns
+	row := r.db.QueryRow(query, bk.Title, bk.Author, bk.Price, bk.Category, bk.IsFeatured, bk.SharedBy, bk.Tag, bk.CoverUrl, bk.ID)
+	return scanBook(row)
+}
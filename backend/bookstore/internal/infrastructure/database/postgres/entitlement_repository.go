@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/google/uuid"
+)
+
+var _ entitlement.Repository = (*EntitlementRepositoryPostgres)(nil)
+
+const licenseColumns = `id, name, email_domain, category, expires_at, created_at`
+
+// EntitlementRepositoryPostgres reads purchases from the book_entitlements
+// table kept by the order store, and keeps institutional licenses.
+type EntitlementRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewEntitlementRepositoryPostgres(db *sql.DB) *EntitlementRepositoryPostgres {
+	return &EntitlementRepositoryPostgres{db: db}
+}
+
+// CreateLicenseTable creates the institutional_licenses and
+// institutional_license_members tables if they don't exist.
+func (r *EntitlementRepositoryPostgres) CreateLicenseTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS institutional_licenses (
+			id VARCHAR(36) PRIMARY KEY,
+			name TEXT NOT NULL,
+			email_domain TEXT NOT NULL,
+			category TEXT NOT NULL DEFAULT '',
+			expires_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_institutional_licenses_domain ON institutional_licenses(email_domain);
+
+		CREATE TABLE IF NOT EXISTS institutional_license_members (
+			license_id VARCHAR(36) NOT NULL REFERENCES institutional_licenses(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL,
+			added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (license_id, user_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_institutional_license_members_user ON institutional_license_members(user_id);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *EntitlementRepositoryPostgres) Purchased(userID int, bookIDs []string) (map[string]bool, error) {
+	return r.bookSet(`
+		SELECT DISTINCT book_id FROM book_entitlements
+		WHERE user_id = $1 AND book_id = ANY($2)
+	`, userID, bookIDs)
+}
+
+func (r *EntitlementRepositoryPostgres) Licensed(userID int, bookIDs []string) (map[string]bool, error) {
+	return r.bookSet(`
+		SELECT b.id::text
+		FROM books b
+		WHERE b.id::text = ANY($2) AND EXISTS (
+			SELECT 1
+			FROM institutional_licenses l
+			JOIN institutional_license_members m ON m.license_id = l.id
+			WHERE m.user_id = $1
+				AND (l.category = '' OR l.category = b.category)
+				AND (l.expires_at IS NULL OR l.expires_at > NOW())
+		)
+	`, userID, bookIDs)
+}
+
+func (r *EntitlementRepositoryPostgres) bookSet(query string, userID int, bookIDs []string) (map[string]bool, error) {
+	set := map[string]bool{}
+	if len(bookIDs) == 0 {
+		return set, nil
+	}
+	rows, err := r.db.Query(query, userID, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		set[id] = true
+	}
+	return set, rows.Err()
+}
+
+func (r *EntitlementRepositoryPostgres) CreateLicense(l *entitlement.License) (*entitlement.License, error) {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return scanLicense(r.db.QueryRow(`
+		INSERT INTO institutional_licenses (id, name, email_domain, category, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+licenseColumns,
+		l.ID, l.Name, l.EmailDomain, l.Category, l.ExpiresAt))
+}
+
+func (r *EntitlementRepositoryPostgres) ListLicenses() ([]*entitlement.License, error) {
+	rows, err := r.db.Query(`SELECT ` + licenseColumns + ` FROM institutional_licenses ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	licenses := []*entitlement.License{}
+	for rows.Next() {
+		l, err := scanLicense(rows)
+		if err != nil {
+			return nil, err
+		}
+		licenses = append(licenses, l)
+	}
+	return licenses, rows.Err()
+}
+
+func (r *EntitlementRepositoryPostgres) DeleteLicense(id string) error {
+	res, err := r.db.Exec(`DELETE FROM institutional_licenses WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return entitlement.ErrLicenseNotFound
+	}
+	return err
+}
+
+func (r *EntitlementRepositoryPostgres) AddLicenseMember(licenseID string, userID int) (*entitlement.LicenseMember, error) {
+	var m entitlement.LicenseMember
+	err := r.db.QueryRow(`
+		WITH added AS (
+			INSERT INTO institutional_license_members (license_id, user_id)
+			SELECT id, $2 FROM institutional_licenses WHERE id = $1
+			ON CONFLICT (license_id, user_id) DO NOTHING
+			RETURNING license_id, user_id, added_at
+		)
+		SELECT license_id, user_id, added_at FROM added
+		UNION ALL
+		SELECT license_id, user_id, added_at FROM institutional_license_members
+		WHERE license_id = $1 AND user_id = $2
+	`, licenseID, userID).Scan(&m.LicenseID, &m.UserID, &m.AddedAt)
+	if err == sql.ErrNoRows {
+		return nil, entitlement.ErrLicenseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *EntitlementRepositoryPostgres) RemoveLicenseMember(licenseID string, userID int) error {
+	res, err := r.db.Exec(`DELETE FROM institutional_license_members WHERE license_id = $1 AND user_id = $2`, licenseID, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return entitlement.ErrMemberNotFound
+	}
+	return err
+}
+
+func (r *EntitlementRepositoryPostgres) ListLicenseMembers(licenseID string) ([]*entitlement.LicenseMember, error) {
+	var exists bool
+	if err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM institutional_licenses WHERE id = $1)`, licenseID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, entitlement.ErrLicenseNotFound
+	}
+
+	rows, err := r.db.Query(`
+		SELECT license_id, user_id, added_at FROM institutional_license_members
+		WHERE license_id = $1
+		ORDER BY added_at, user_id
+	`, licenseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*entitlement.LicenseMember{}
+	for rows.Next() {
+		var m entitlement.LicenseMember
+		if err := rows.Scan(&m.LicenseID, &m.UserID, &m.AddedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, &m)
+	}
+	return members, rows.Err()
+}
+
+func scanLicense(row rowScanner) (*entitlement.License, error) {
+	var l entitlement.License
+	if err := row.Scan(&l.ID, &l.Name, &l.EmailDomain, &l.Category, &l.ExpiresAt, &l.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
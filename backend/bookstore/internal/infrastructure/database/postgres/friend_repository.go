@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/bereke1t2/bookstore/internal/domain/social"
+)
+
+var _ social.FriendRepository = (*FriendRepositoryPostgres)(nil)
+
+type FriendRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewFriendRepositoryPostgres(db *sql.DB) *FriendRepositoryPostgres {
+	return &FriendRepositoryPostgres{db: db}
+}
+
+// CreateFriendTable creates the user_friends table if it doesn't exist.
+func (r *FriendRepositoryPostgres) CreateFriendTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS user_friends (
+			user_id INTEGER NOT NULL,
+			friend_id INTEGER NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (user_id, friend_id)
+		);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// AddFriend adds friendID to the user's friends list. Adding an existing
+// friend again is a no-op.
+func (r *FriendRepositoryPostgres) AddFriend(userID, friendID int) error {
+	res, err := r.db.Exec(`
+		INSERT INTO user_friends (user_id, friend_id)
+		SELECT $1, id FROM users WHERE id = $2
+		ON CONFLICT (user_id, friend_id) DO NOTHING
+	`, userID, friendID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	var exists bool
+	if err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, friendID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return social.ErrUserNotFound
+	}
+	return nil
+}
+
+// RemoveFriend removes friendID from the user's friends list.
+func (r *FriendRepositoryPostgres) RemoveFriend(userID, friendID int) error {
+	res, err := r.db.Exec(`DELETE FROM user_friends WHERE user_id = $1 AND friend_id = $2`, userID, friendID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return social.ErrFriendNotFound
+	}
+	return nil
+}
+
+// ListFriends returns the user's friends, most recently added first.
+func (r *FriendRepositoryPostgres) ListFriends(userID int) ([]*social.Friend, error) {
+	rows, err := r.db.Query(`
+		SELECT u.id, u.username, u.profile_image, f.created_at
+		FROM user_friends f
+		JOIN users u ON u.id = f.friend_id
+		WHERE f.user_id = $1
+		ORDER BY f.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	friends := []*social.Friend{}
+	for rows.Next() {
+		var f social.Friend
+		if err := rows.Scan(&f.UserID, &f.Username, &f.ProfileImage, &f.Since); err != nil {
+			return nil, err
+		}
+		friends = append(friends, &f)
+	}
+	return friends, rows.Err()
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/goal"
+	"github.com/bereke1t2/bookstore/internal/domain/reading"
+	"github.com/google/uuid"
+)
+
+var _ goal.Repository = (*GoalRepositoryPostgres)(nil)
+
+const goalColumns = `id, user_id, type, target, start_date, end_date, completed_at, created_at`
+
+type GoalRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewGoalRepositoryPostgres(db *sql.DB) *GoalRepositoryPostgres {
+	return &GoalRepositoryPostgres{db: db}
+}
+
+// CreateGoalTable creates the reading_goals table if it doesn't exist.
+func (r *GoalRepositoryPostgres) CreateGoalTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS reading_goals (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			type VARCHAR(32) NOT NULL,
+			target INTEGER NOT NULL,
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL,
+			completed_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_reading_goals_user ON reading_goals(user_id, end_date);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *GoalRepositoryPostgres) Create(g *goal.Goal) (*goal.Goal, error) {
+	if g.ID == "" {
+		g.ID = uuid.New().String()
+	}
+	query := `
+		INSERT INTO reading_goals (id, user_id, type, target, start_date, end_date)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + goalColumns
+
+	return scanGoal(r.db.QueryRow(query, g.ID, g.UserID, g.Type, g.Target, g.StartDate, g.EndDate))
+}
+
+// GetByUserID returns the user's goals, those ending soonest first.
+func (r *GoalRepositoryPostgres) GetByUserID(userID int) ([]*goal.Goal, error) {
+	rows, err := r.db.Query(`
+		SELECT `+goalColumns+` FROM reading_goals
+		WHERE user_id = $1
+		ORDER BY end_date, created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := []*goal.Goal{}
+	for rows.Next() {
+		g, err := scanGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, g)
+	}
+	return goals, rows.Err()
+}
+
+func (r *GoalRepositoryPostgres) Delete(userID int, id string) error {
+	res, err := r.db.Exec(`DELETE FROM reading_goals WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return goal.ErrGoalNotFound
+	}
+	return nil
+}
+
+func (r *GoalRepositoryPostgres) MarkCompleted(id string, at time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE reading_goals SET completed_at = $2
+		WHERE id = $1 AND completed_at IS NULL
+	`, id, at)
+	return err
+}
+
+// CountFinishedBooks counts finished books by the day they were finished
+// on in the user's timezone.
+func (r *GoalRepositoryPostgres) CountFinishedBooks(userID int, from, to time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM reading_progress p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.user_id = $1 AND p.status = $2
+			AND (p.finished_at AT TIME ZONE u.timezone)::date BETWEEN $3 AND $4
+	`, userID, reading.StatusFinished, from, to).Scan(&count)
+	return count, err
+}
+
+func (r *GoalRepositoryPostgres) ReadingSecondsByDay(userID int, from, to time.Time) (map[time.Time]int, error) {
+	rows, err := r.db.Query(`
+		SELECT local_date, SUM(value)
+		FROM activity_events
+		WHERE user_id = $1 AND type = $2 AND local_date BETWEEN $3 AND $4
+		GROUP BY local_date
+	`, userID, activity.TypeReadingSession, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seconds := map[time.Time]int{}
+	for rows.Next() {
+		var day time.Time
+		var total int
+		if err := rows.Scan(&day, &total); err != nil {
+			return nil, err
+		}
+		seconds[day.UTC()] = total
+	}
+	return seconds, rows.Err()
+}
+
+func scanGoal(row rowScanner) (*goal.Goal, error) {
+	var g goal.Goal
+	err := row.Scan(&g.ID, &g.UserID, &g.Type, &g.Target, &g.StartDate, &g.EndDate, &g.CompletedAt, &g.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	g.StartDate, g.EndDate = g.StartDate.UTC(), g.EndDate.UTC()
+	return &g, nil
+}
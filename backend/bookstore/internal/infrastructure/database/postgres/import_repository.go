@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/library"
+	"github.com/google/uuid"
+)
+
+var _ library.ImportRepository = (*ImportRepositoryPostgres)(nil)
+
+const importColumns = `id, user_id, format, filename, status, total, processed, matched, unmatched, error, created_at, updated_at, finished_at`
+
+type ImportRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewImportRepositoryPostgres(db *sql.DB) *ImportRepositoryPostgres {
+	return &ImportRepositoryPostgres{db: db}
+}
+
+// CreateImportTable creates the library_imports table if it doesn't exist.
+// At most one job per user may be pending or running.
+func (r *ImportRepositoryPostgres) CreateImportTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS library_imports (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			format VARCHAR(32) NOT NULL,
+			filename TEXT NOT NULL DEFAULT '',
+			status VARCHAR(16) NOT NULL,
+			total INTEGER NOT NULL DEFAULT 0,
+			processed INTEGER NOT NULL DEFAULT 0,
+			matched INTEGER NOT NULL DEFAULT 0,
+			unmatched JSONB NOT NULL DEFAULT '[]',
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			finished_at TIMESTAMPTZ
+		);
+		CREATE INDEX IF NOT EXISTS idx_library_imports_user ON library_imports(user_id, created_at DESC);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_library_imports_active ON library_imports(user_id)
+			WHERE status IN ('pending', 'running');
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *ImportRepositoryPostgres) CreateJob(job *library.Job) (*library.Job, error) {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	_, err := r.db.Exec(`
+		UPDATE library_imports
+		SET status = $2, error = 'timed out', finished_at = NOW(), updated_at = NOW()
+		WHERE user_id = $1 AND status IN ($3, $4) AND created_at < $5
+	`, job.UserID, library.StatusFailed, library.StatusPending, library.StatusRunning, time.Now().Add(-library.MaxJobDuration))
+	if err != nil {
+		return nil, err
+	}
+
+	row := r.db.QueryRow(`
+		INSERT INTO library_imports (id, user_id, format, filename, status, total)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+		RETURNING `+importColumns,
+		job.ID, job.UserID, job.Format, job.Filename, job.Status, job.Total)
+
+	created, err := scanImportJob(row)
+	if err == sql.ErrNoRows {
+		return nil, library.ErrImportInProgress
+	}
+	return created, err
+}
+
+func (r *ImportRepositoryPostgres) UpdateJob(job *library.Job) error {
+	if job.Unmatched == nil {
+		job.Unmatched = []*library.Unmatched{}
+	}
+	unmatched, err := json.Marshal(job.Unmatched)
+	if err != nil {
+		return err
+	}
+	err = r.db.QueryRow(`
+		UPDATE library_imports
+		SET status = $2, processed = $3, matched = $4, unmatched = $5, error = $6, finished_at = $7, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, job.ID, job.Status, job.Processed, job.Matched, unmatched, job.Error, job.FinishedAt).Scan(&job.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return library.ErrImportNotFound
+	}
+	return err
+}
+
+func (r *ImportRepositoryPostgres) GetJob(userID int, id string) (*library.Job, error) {
+	job, err := scanImportJob(r.db.QueryRow(`
+		SELECT `+importColumns+` FROM library_imports WHERE id = $1 AND user_id = $2
+	`, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, library.ErrImportNotFound
+	}
+	return job, err
+}
+
+func (r *ImportRepositoryPostgres) ListJobs(userID int, limit int) ([]*library.Job, error) {
+	rows, err := r.db.Query(`
+		SELECT `+importColumns+` FROM library_imports
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*library.Job{}
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *ImportRepositoryPostgres) FailInterrupted() (int, error) {
+	res, err := r.db.Exec(`
+		UPDATE library_imports
+		SET status = $1, error = 'interrupted by a server restart', finished_at = NOW(), updated_at = NOW()
+		WHERE status IN ($2, $3)
+	`, library.StatusFailed, library.StatusPending, library.StatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func scanImportJob(row rowScanner) (*library.Job, error) {
+	var job library.Job
+	var unmatched []byte
+	err := row.Scan(&job.ID, &job.UserID, &job.Format, &job.Filename, &job.Status, &job.Total, &job.Processed,
+		&job.Matched, &unmatched, &job.Error, &job.CreatedAt, &job.UpdatedAt, &job.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(unmatched, &job.Unmatched); err != nil {
+		return nil, err
+	}
+	if job.Unmatched == nil {
+		job.Unmatched = []*library.Unmatched{}
+	}
+	return &job, nil
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/leaderboard"
+)
+
+var _ leaderboard.Repository = (*LeaderboardRepositoryPostgres)(nil)
+
+// LeaderboardRepositoryPostgres ranks users straight from the users and
+// activity_events tables. Every query scores all users on the board, so its
+// cost grows with the number of users; the weekly scope only reads the
+// week's events through the local_date index. Ranks are counted rather than
+// computed with a window over every user.
+type LeaderboardRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewLeaderboardRepositoryPostgres(db *sql.DB) *LeaderboardRepositoryPostgres {
+	return &LeaderboardRepositoryPostgres{db: db}
+}
+
+// CreateLeaderboardIndexes creates the indexes the ranking queries use.
+func (r *LeaderboardRepositoryPostgres) CreateLeaderboardIndexes() error {
+	query := `
+		CREATE INDEX IF NOT EXISTS idx_users_points ON users(points DESC, id);
+		CREATE INDEX IF NOT EXISTS idx_users_books_read ON users(books_read_count DESC, id);
+		CREATE INDEX IF NOT EXISTS idx_users_streak ON users(reading_streak DESC, id);
+		CREATE INDEX IF NOT EXISTS idx_activity_events_occurred ON activity_events(occurred_at);
+		CREATE INDEX IF NOT EXISTS idx_activity_events_local_date ON activity_events(local_date);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// Top returns the highest-ranked users. Ranks within the page are exact:
+// every user with a higher value is on the page too.
+func (r *LeaderboardRepositoryPostgres) Top(q leaderboard.Query, since time.Time) ([]*leaderboard.Entry, error) {
+	scored, args := scoredUsers(q, since)
+	args = append(args, q.Limit)
+	query := fmt.Sprintf(`
+		WITH scored AS (%s),
+		top AS (SELECT user_id, value FROM scored ORDER BY value DESC, user_id LIMIT $%d)
+		SELECT RANK() OVER (ORDER BY t.value DESC), u.id, u.username, u.profile_image, t.value
+		FROM top t
+		JOIN users u ON u.id = t.user_id
+		ORDER BY t.value DESC, u.id
+	`, scored, len(args))
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*leaderboard.Entry{}
+	for rows.Next() {
+		var e leaderboard.Entry
+		if err := rows.Scan(&e.Rank, &e.UserID, &e.Username, &e.ProfileImage, &e.Value); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// RankOf returns the caller's entry, ranked one below the number of users
+// with a strictly higher value.
+func (r *LeaderboardRepositoryPostgres) RankOf(q leaderboard.Query, since time.Time) (*leaderboard.Entry, error) {
+	scored, args := scoredUsers(q, since)
+	query := fmt.Sprintf(`
+		WITH scored AS (%s)
+		SELECT (SELECT COUNT(*) FROM scored s WHERE s.value > me.value) + 1,
+			u.id, u.username, u.profile_image, me.value
+		FROM scored me
+		JOIN users u ON u.id = me.user_id
+		WHERE me.user_id = $1
+	`, scored)
+
+	var e leaderboard.Entry
+	err := r.db.QueryRow(query, args...).Scan(&e.Rank, &e.UserID, &e.Username, &e.ProfileImage, &e.Value)
+	if err == sql.ErrNoRows {
+		return nil, leaderboard.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// scoredUsers builds a query yielding (user_id, value) for every user on
+// the board. $1 is always the caller.
+func scoredUsers(q leaderboard.Query, since time.Time) (string, []any) {
+	args := []any{q.UserID}
+	param := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var query string
+	if q.Scope == leaderboard.ScopeWeekly {
+		var agg string
+		switch q.Metric {
+		case leaderboard.MetricBooksRead:
+			agg = "COUNT(*) FILTER (WHERE type = " + param(activity.TypeBookFinished) + ")"
+		case leaderboard.MetricStreak:
+			// Days read this week
+			agg = "COUNT(DISTINCT local_date) FILTER (WHERE streak_day)"
+		default:
+			agg = "SUM(points)"
+		}
+		query = `
+			SELECT u.id AS user_id, COALESCE(w.value, 0)::integer AS value
+			FROM users u
+			LEFT JOIN (
+				SELECT user_id, ` + agg + ` AS value
+				FROM activity_events
+				WHERE local_date >= ` + param(since.Format(time.DateOnly)) + `::date
+				GROUP BY user_id
+			) w ON w.user_id = u.id`
+	} else {
+		var value string
+		switch q.Metric {
+		case leaderboard.MetricBooksRead:
+			value = "COALESCE(u.books_read_count, 0)"
+		case leaderboard.MetricStreak:
+			// A stored streak is only current if the user read recently enough.
+			value = "CASE WHEN u.last_read_date >= NOW() - make_interval(days => " +
+				param(activity.GraceDays+1) + "::integer) THEN COALESCE(u.reading_streak, 0) ELSE 0 END"
+		default:
+			value = "COALESCE(u.points, 0)"
+		}
+		query = `SELECT u.id AS user_id, ` + value + ` AS value FROM users u`
+	}
+
+	if q.Scope == leaderboard.ScopeFriends {
+		query += `
+			WHERE u.id = $1 OR u.id IN (SELECT friend_id FROM user_friends WHERE user_id = $1)`
+	}
+	return query, args
+}
@@ -2,12 +2,17 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bereke1t2/bookstore/internal/domain/note"
 	"github.com/google/uuid"
 )
 
+var _ note.NoteRepository = (*NoteRepositoryPostgres)(nil)
+
 type NoteRepositoryPostgres struct {
 	db *sql.DB
 }
@@ -16,7 +21,15 @@ func NewNoteRepositoryPostgres(db *sql.DB) *NoteRepositoryPostgres {
 	return &NoteRepositoryPostgres{db: db}
 }
 
-// CreateNoteTable creates the notes table if it doesn't exist.
+const noteColumns = `id, user_id, book_id, content, is_ai_generated, created_at,
+	updated_at, page_number, cfi, quote, comment, color, tags, version, deleted_at`
+
+// CreateNoteTable creates the notes table if it doesn't exist and adds the
+// highlight, search and sync columns to tables created before they existed.
+// Every write records the ID of its transaction in change_xid and draws
+// change_seq from a sequence; together they order the changes returned to
+// syncing clients. Rows written before change_xid existed all get the ID of
+// the transaction that added it.
 func (r *NoteRepositoryPostgres) CreateNoteTable() error {
 	query := `
 		CREATE TABLE IF NOT EXISTS notes (
@@ -28,12 +41,38 @@ func (r *NoteRepositoryPostgres) CreateNoteTable() error {
 			created_at TIMESTAMPTZ DEFAULT NOW()
 		);
 		CREATE INDEX IF NOT EXISTS idx_notes_user_book ON notes(user_id, book_id);
+
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ DEFAULT NOW();
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS page_number INTEGER;
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS cfi TEXT NOT NULL DEFAULT '';
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS quote TEXT NOT NULL DEFAULT '';
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS comment TEXT NOT NULL DEFAULT '';
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS color VARCHAR(16) NOT NULL DEFAULT '';
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]'::jsonb;
+		CREATE INDEX IF NOT EXISTS idx_notes_tags ON notes USING GIN (tags);
+
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (
+				setweight(to_tsvector('english', coalesce(quote, '')), 'A') ||
+				setweight(to_tsvector('english', coalesce(content, '')), 'B') ||
+				setweight(to_tsvector('english', coalesce(comment, '')), 'C')
+			) STORED;
+		CREATE INDEX IF NOT EXISTS idx_notes_search ON notes USING GIN (search_vector);
+
+		CREATE SEQUENCE IF NOT EXISTS notes_change_seq;
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT nextval('notes_change_seq');
+		CREATE INDEX IF NOT EXISTS idx_notes_user_changes ON notes(user_id, change_seq);
+		ALTER TABLE notes ADD COLUMN IF NOT EXISTS change_xid xid8 NOT NULL DEFAULT pg_current_xact_id();
+		CREATE INDEX IF NOT EXISTS idx_notes_user_change_xid ON notes(user_id, change_xid, change_seq);
 	`
 	_, err := r.db.Exec(query)
 	return err
 }
 
-// Create inserts a new note into the database.
+// Create inserts a new note into the database, keeping a client-generated ID
+// if n has one. It returns note.ErrNoteExists if the ID is already taken.
 func (r *NoteRepositoryPostgres) Create(n *note.Note) (*note.Note, error) {
 	if n.ID == "" {
 		n.ID = uuid.New().String()
@@ -41,31 +80,91 @@ func (r *NoteRepositoryPostgres) Create(n *note.Note) (*note.Note, error) {
 	if n.CreatedAt.IsZero() {
 		n.CreatedAt = time.Now()
 	}
+	if n.UpdatedAt.IsZero() {
+		n.UpdatedAt = n.CreatedAt
+	}
+	if n.Version < 1 {
+		n.Version = 1
+	}
+	tags, err := encodeTags(n.Tags)
+	if err != nil {
+		return nil, err
+	}
 
 	query := `
-		INSERT INTO notes (id, user_id, book_id, content, is_ai_generated, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, user_id, book_id, content, is_ai_generated, created_at
-	`
-	row := r.db.QueryRow(query, n.ID, n.UserID, n.BookID, n.Content, n.IsAIGenerated, n.CreatedAt)
+		INSERT INTO notes (` + noteColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13::jsonb, $14, $15)
+		ON CONFLICT (id) DO NOTHING
+		RETURNING ` + noteColumns
+	row := r.db.QueryRow(query, n.ID, n.UserID, n.BookID, n.Content, n.IsAIGenerated, n.CreatedAt,
+		n.UpdatedAt, n.PageNumber, n.CFI, n.Quote, n.Comment, n.Color, tags, n.Version, n.DeletedAt)
+	created, err := scanNote(row)
+	if err == sql.ErrNoRows {
+		return nil, note.ErrNoteExists
+	}
+	return created, err
+}
+
+// GetByID retrieves a note owned by the user.
+func (r *NoteRepositoryPostgres) GetByID(noteID string, userID int) (*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+	n, err := scanNote(r.db.QueryRow(query, noteID, userID))
+	if err == sql.ErrNoRows {
+		return nil, note.ErrNoteNotFound
+	}
+	return n, err
+}
+
+// GetByIDIncludingDeleted retrieves a note owned by the user, even if it
+// has been deleted.
+func (r *NoteRepositoryPostgres) GetByIDIncludingDeleted(noteID string, userID int) (*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE id = $1 AND user_id = $2`
+	n, err := scanNote(r.db.QueryRow(query, noteID, userID))
+	if err == sql.ErrNoRows {
+		return nil, note.ErrNoteNotFound
+	}
+	return n, err
+}
 
-	var created note.Note
-	err := row.Scan(&created.ID, &created.UserID, &created.BookID, &created.Content, &created.IsAIGenerated, &created.CreatedAt)
+// GetByBookID retrieves the user's notes for a book, optionally narrowed by
+// tag, color or page.
+func (r *NoteRepositoryPostgres) GetByBookID(bookID string, userID int, filter note.Filter) ([]*note.Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		WHERE book_id = $1 AND user_id = $2 AND deleted_at IS NULL
+			AND ($3 = '' OR tags @> jsonb_build_array($3::text))
+			AND ($4 = '' OR color = $4)
+			AND ($5::integer IS NULL OR page_number = $5)
+		ORDER BY page_number ASC NULLS LAST, created_at DESC
+	`
+	rows, err := r.db.Query(query, bookID, userID, filter.Tag, filter.Color, filter.Page)
 	if err != nil {
 		return nil, err
 	}
-	return &created, nil
+	defer rows.Close()
+
+	var notes []*note.Note
+	for rows.Next() {
+		n, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
 }
 
-// GetByBookID retrieves all notes for a specific book and user.
-func (r *NoteRepositoryPostgres) GetByBookID(bookID string, userID int) ([]*note.Note, error) {
+// GetByUserID retrieves all of the user's notes, grouped by book and in
+// reading order within each book.
+func (r *NoteRepositoryPostgres) GetByUserID(userID int) ([]*note.Note, error) {
 	query := `
-		SELECT id, user_id, book_id, content, is_ai_generated, created_at
+		SELECT ` + noteColumns + `
 		FROM notes
-		WHERE book_id = $1 AND user_id = $2
-		ORDER BY created_at DESC
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY book_id, page_number ASC NULLS LAST, created_at ASC
 	`
-	rows, err := r.db.Query(query, bookID, userID)
+	rows, err := r.db.Query(query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -73,18 +172,200 @@ func (r *NoteRepositoryPostgres) GetByBookID(bookID string, userID int) ([]*note
 
 	var notes []*note.Note
 	for rows.Next() {
-		var n note.Note
-		if err := rows.Scan(&n.ID, &n.UserID, &n.BookID, &n.Content, &n.IsAIGenerated, &n.CreatedAt); err != nil {
+		n, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
+
+// Update saves the editable fields and the tombstone of a note owned by
+// n.UserID, provided it is still at n.Version. It returns
+// note.ErrNoteVersionConflict if the note has changed since it was read.
+func (r *NoteRepositoryPostgres) Update(n *note.Note) (*note.Note, error) {
+	tags, err := encodeTags(n.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if n.UpdatedAt.IsZero() {
+		n.UpdatedAt = time.Now()
+	}
+
+	query := `
+		UPDATE notes
+		SET content = $1, page_number = $2, cfi = $3, quote = $4, comment = $5, color = $6,
+			tags = $7::jsonb, updated_at = $8, deleted_at = $9,
+			version = version + 1, change_seq = nextval('notes_change_seq'), change_xid = pg_current_xact_id()
+		WHERE id = $10 AND user_id = $11 AND version = $12
+		RETURNING ` + noteColumns
+	updated, err := scanNote(r.db.QueryRow(query, n.Content, n.PageNumber, n.CFI, n.Quote, n.Comment, n.Color,
+		tags, n.UpdatedAt, n.DeletedAt, n.ID, n.UserID, n.Version))
+	if err == sql.ErrNoRows {
+		if _, err := r.GetByIDIncludingDeleted(n.ID, n.UserID); err != nil {
 			return nil, err
 		}
-		notes = append(notes, &n)
+		return nil, note.ErrNoteVersionConflict
 	}
-	return notes, nil
+	return updated, err
 }
 
-// Delete removes a note by ID for a specific user.
+// Delete turns a note into a tombstone. It returns note.ErrNoteNotFound if
+// the user has no live note with that ID.
 func (r *NoteRepositoryPostgres) Delete(noteID string, userID int) error {
-	query := `DELETE FROM notes WHERE id = $1 AND user_id = $2`
-	_, err := r.db.Exec(query, noteID, userID)
-	return err
+	query := `
+		UPDATE notes
+		SET deleted_at = NOW(), updated_at = NOW(),
+			version = version + 1, change_seq = nextval('notes_change_seq'), change_xid = pg_current_xact_id()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+	res, err := r.db.Exec(query, noteID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return note.ErrNoteNotFound
+	}
+	return nil
+}
+
+// Search runs a full-text search over the user's notes in every book, best
+// matches first, and returns a highlighted snippet and book metadata per hit.
+func (r *NoteRepositoryPostgres) Search(userID int, q note.SearchQuery) ([]*note.SearchResult, error) {
+	query := `
+		SELECT ` + prefixColumns("n", noteColumns) + `,
+			ts_rank(n.search_vector, query) AS rank,
+			ts_headline('english', concat_ws(' … ', NULLIF(n.quote, ''), n.content, NULLIF(n.comment, '')), query,
+				'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=8') AS snippet,
+			coalesce(b.title, ''), coalesce(b.author, ''), coalesce(b.cover_url, '')
+		FROM notes n
+		CROSS JOIN websearch_to_tsquery('english', $2) AS query
+		LEFT JOIN books b ON b.id::text = n.book_id
+		WHERE n.user_id = $1 AND n.deleted_at IS NULL
+			AND n.search_vector @@ query
+			AND ($3::boolean IS NULL OR n.is_ai_generated = $3)
+			AND ($4 = '' OR n.book_id = $4)
+		ORDER BY rank DESC, n.created_at DESC
+		LIMIT $5 OFFSET $6
+	`
+	rows, err := r.db.Query(query, userID, q.Text, q.IsAIGenerated, q.BookID, q.Limit, q.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*note.SearchResult{}
+	for rows.Next() {
+		var (
+			n    note.Note
+			tags []byte
+			res  note.SearchResult
+		)
+		dest := append(noteFields(&n, &tags), &res.Rank, &res.Snippet, &res.Book.Title, &res.Book.Author, &res.Book.CoverUrl)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(tags, &n.Tags); err != nil {
+			return nil, err
+		}
+		res.Note = &n
+		res.Book.ID = n.BookID
+		results = append(results, &res)
+	}
+	return results, rows.Err()
+}
+
+// GetChanges returns up to limit of the user's notes, tombstones included,
+// written after the since cursor, oldest change first.
+//
+// Sequence values are taken when a row is written, not when it commits, so
+// ordering by change_seq alone would let a cursor pass a row whose
+// transaction commits late. Changes are instead ordered by the ID of the
+// writing transaction, and only rows written by transactions older than
+// every transaction still in progress (the snapshot's xmin) are returned.
+// All of those have finished, so no row can later appear before the cursor:
+// any transaction that has yet to commit has an ID at or above xmin and its
+// rows sort after everything returned so far. A slow writer only delays
+// the changes behind it until the next sync.
+func (r *NoteRepositoryPostgres) GetChanges(userID int, since note.Cursor, limit int) (*note.ChangeSet, error) {
+	query := `
+		SELECT ` + noteColumns + `, change_xid::text::bigint, change_seq
+		FROM notes
+		WHERE user_id = $1
+			AND (change_xid, change_seq) > ($2::text::xid8, $3)
+			AND change_xid < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY change_xid ASC, change_seq ASC
+		LIMIT $4
+	`
+	rows, err := r.db.Query(query, userID, strconv.FormatInt(since.TxID, 10), since.Seq, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := &note.ChangeSet{Notes: []*note.Note{}, Cursor: since}
+	for rows.Next() {
+		if len(changes.Notes) == limit {
+			changes.HasMore = true
+			break
+		}
+		var (
+			n    note.Note
+			tags []byte
+		)
+		if err := rows.Scan(append(noteFields(&n, &tags), &changes.Cursor.TxID, &changes.Cursor.Seq)...); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(tags, &n.Tags); err != nil {
+			return nil, err
+		}
+		changes.Notes = append(changes.Notes, &n)
+	}
+	return changes, rows.Err()
+}
+
+// prefixColumns qualifies every column in a comma-separated list with alias.
+func prefixColumns(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, p := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(p)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// noteFields returns scan destinations matching noteColumns. Tags are
+// scanned as raw JSON into tags.
+func noteFields(n *note.Note, tags *[]byte) []any {
+	return []any{&n.ID, &n.UserID, &n.BookID, &n.Content, &n.IsAIGenerated, &n.CreatedAt,
+		&n.UpdatedAt, &n.PageNumber, &n.CFI, &n.Quote, &n.Comment, &n.Color, tags, &n.Version, &n.DeletedAt}
+}
+
+func scanNote(row rowScanner) (*note.Note, error) {
+	var (
+		n    note.Note
+		tags []byte
+	)
+	if err := row.Scan(noteFields(&n, &tags)...); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tags, &n.Tags); err != nil {
+		return nil, err
+	}
+	if n.Tags == nil {
+		n.Tags = []string{}
+	}
+	return &n, nil
+}
+
+func encodeTags(tags []string) (string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	b, err := json.Marshal(tags)
+	return string(b), err
 }
@@ -0,0 +1,308 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+	"github.com/google/uuid"
+)
+
+var _ order.Repository = (*OrderRepositoryPostgres)(nil)
+
+const orderColumns = `id, user_id, status, total_cents, currency, payment_ref, refund_ref, created_at, paid_at, refunded_at`
+
+type OrderRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewOrderRepositoryPostgres(db *sql.DB) *OrderRepositoryPostgres {
+	return &OrderRepositoryPostgres{db: db}
+}
+
+// CreateOrderTables creates the cart_items, orders, order_items and
+// book_entitlements tables if they don't exist.
+func (r *OrderRepositoryPostgres) CreateOrderTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS cart_items (
+			user_id INTEGER NOT NULL,
+			book_id VARCHAR(36) NOT NULL,
+			added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (user_id, book_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS orders (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'pending',
+			total_cents BIGINT NOT NULL CHECK (total_cents >= 0),
+			currency VARCHAR(3) NOT NULL,
+			payment_ref TEXT NOT NULL DEFAULT '',
+			refund_ref TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			paid_at TIMESTAMPTZ,
+			refunded_at TIMESTAMPTZ
+		);
+		CREATE INDEX IF NOT EXISTS idx_orders_user ON orders(user_id, created_at DESC);
+
+		CREATE TABLE IF NOT EXISTS order_items (
+			order_id VARCHAR(36) NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			book_id VARCHAR(36) NOT NULL,
+			title TEXT NOT NULL,
+			author TEXT NOT NULL DEFAULT '',
+			price_cents BIGINT NOT NULL CHECK (price_cents >= 0),
+			PRIMARY KEY (order_id, book_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS book_entitlements (
+			user_id INTEGER NOT NULL,
+			book_id VARCHAR(36) NOT NULL,
+			order_id VARCHAR(36) NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			granted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (user_id, book_id, order_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_book_entitlements_order ON book_entitlements(order_id);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *OrderRepositoryPostgres) AddToCart(userID int, bookID string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO cart_items (user_id, book_id) VALUES ($1, $2)
+		ON CONFLICT (user_id, book_id) DO NOTHING
+	`, userID, bookID)
+	return err
+}
+
+func (r *OrderRepositoryPostgres) RemoveFromCart(userID int, bookID string) error {
+	res, err := r.db.Exec(`DELETE FROM cart_items WHERE user_id = $1 AND book_id = $2`, userID, bookID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return order.ErrItemNotFound
+	}
+	return err
+}
+
+func (r *OrderRepositoryPostgres) ListCart(userID int) ([]*order.CartItem, error) {
+	rows, err := r.db.Query(`
+		SELECT c.book_id, b.title, b.author, b.cover_url,
+			COALESCE(ROUND(b.price::numeric * 100), 0)::bigint, c.added_at
+		FROM cart_items c
+		JOIN books b ON b.id::text = c.book_id
+		WHERE c.user_id = $1
+		ORDER BY c.added_at, c.book_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*order.CartItem
+	for rows.Next() {
+		var it order.CartItem
+		if err := rows.Scan(&it.BookID, &it.Title, &it.Author, &it.CoverUrl, &it.PriceCents, &it.AddedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &it)
+	}
+	return items, rows.Err()
+}
+
+func (r *OrderRepositoryPostgres) CreateOrder(o *order.Order) (*order.Order, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if o.ID == "" {
+		o.ID = uuid.New().String()
+	}
+	err = tx.QueryRow(`
+		INSERT INTO orders (id, user_id, status, total_cents, currency)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`, o.ID, o.UserID, order.StatusPending, o.TotalCents, o.Currency).Scan(&o.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	o.Status = order.StatusPending
+
+	for _, it := range o.Items {
+		if _, err := tx.Exec(`
+			INSERT INTO order_items (order_id, book_id, title, author, price_cents)
+			VALUES ($1, $2, $3, $4, $5)
+		`, o.ID, it.BookID, it.Title, it.Author, it.PriceCents); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`DELETE FROM cart_items WHERE user_id = $1 AND book_id = $2`, o.UserID, it.BookID); err != nil {
+			return nil, err
+		}
+	}
+	return o, tx.Commit()
+}
+
+func (r *OrderRepositoryPostgres) GetOrder(id string) (*order.Order, error) {
+	o, err := scanOrder(r.db.QueryRow(`SELECT `+orderColumns+` FROM orders WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, order.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadItems([]*order.Order{o}); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (r *OrderRepositoryPostgres) ListOrders(userID int) ([]*order.Order, error) {
+	rows, err := r.db.Query(`
+		SELECT `+orderColumns+` FROM orders
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*order.Order
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, r.loadItems(orders)
+}
+
+// loadItems fills in the line items of orders.
+func (r *OrderRepositoryPostgres) loadItems(orders []*order.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byID := make(map[string]*order.Order, len(orders))
+	ids := make([]string, len(orders))
+	for i, o := range orders {
+		byID[o.ID], ids[i] = o, o.ID
+		o.Items = []*order.LineItem{}
+	}
+
+	rows, err := r.db.Query(`
+		SELECT order_id, book_id, title, author, price_cents
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, title, book_id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var orderID string
+		var it order.LineItem
+		if err := rows.Scan(&orderID, &it.BookID, &it.Title, &it.Author, &it.PriceCents); err != nil {
+			return err
+		}
+		o := byID[orderID]
+		o.Items = append(o.Items, &it)
+	}
+	return rows.Err()
+}
+
+func (r *OrderRepositoryPostgres) MarkPaid(orderID, paymentRef string, at time.Time) (*order.Order, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := transitionOrder(tx, orderID, order.StatusPending, order.StatusPaid, `payment_ref = $4, paid_at = $5`, paymentRef, at); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO book_entitlements (user_id, book_id, order_id, granted_at)
+		SELECT o.user_id, i.book_id, o.id, $2
+		FROM orders o
+		JOIN order_items i ON i.order_id = o.id
+		WHERE o.id = $1
+		ON CONFLICT DO NOTHING
+	`, orderID, at); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetOrder(orderID)
+}
+
+func (r *OrderRepositoryPostgres) MarkRefunded(orderID, refundRef string, at time.Time) (*order.Order, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := transitionOrder(tx, orderID, order.StatusPaid, order.StatusRefunded, `refund_ref = $4, refunded_at = $5`, refundRef, at); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM book_entitlements WHERE order_id = $1`, orderID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetOrder(orderID)
+}
+
+// transitionOrder moves an order from one status to another, setting the
+// extra columns in set from args. It fails if the order is not in the from
+// status, so concurrent changes cannot both succeed.
+func transitionOrder(tx *sql.Tx, orderID, from, to, set string, args ...any) error {
+	res, err := tx.Exec(`UPDATE orders SET status = $3, `+set+` WHERE id = $1 AND status = $2`,
+		append([]any{orderID, from, to}, args...)...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	var status string
+	err = tx.QueryRow(`SELECT status FROM orders WHERE id = $1`, orderID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return order.ErrOrderNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return order.CheckTransition(status, to)
+}
+
+func (r *OrderRepositoryPostgres) HasEntitlement(userID int, bookID string) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM book_entitlements WHERE user_id = $1 AND book_id = $2)
+	`, userID, bookID).Scan(&ok)
+	return ok, err
+}
+
+func scanOrder(row rowScanner) (*order.Order, error) {
+	var o order.Order
+	if err := row.Scan(&o.ID, &o.UserID, &o.Status, &o.TotalCents, &o.Currency, &o.PaymentRef, &o.RefundRef, &o.CreatedAt, &o.PaidAt, &o.RefundedAt); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
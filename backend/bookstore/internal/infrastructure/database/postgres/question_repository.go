@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/bereke1t2/bookstore/internal/domain/chat"
+	"github.com/google/uuid"
+)
+
+var _ chat.QuestionRepository = (*QuestionRepositoryPostgres)(nil)
+
+const issuedQuestionColumns = `id, user_id, book_name, question, reference_answer, explanation, created_at`
+
+type QuestionRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewQuestionRepositoryPostgres(db *sql.DB) *QuestionRepositoryPostgres {
+	return &QuestionRepositoryPostgres{db: db}
+}
+
+// CreateIssuedQuestionTable creates the issued_questions table if it
+// doesn't exist.
+func (r *QuestionRepositoryPostgres) CreateIssuedQuestionTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS issued_questions (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			book_name TEXT NOT NULL,
+			question TEXT NOT NULL,
+			reference_answer TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_issued_questions_user ON issued_questions(user_id, created_at);
+		ALTER TABLE issued_questions ADD COLUMN IF NOT EXISTS explanation TEXT NOT NULL DEFAULT '';
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *QuestionRepositoryPostgres) Issue(q *chat.IssuedQuestion) (*chat.IssuedQuestion, error) {
+	if q.ID == "" {
+		q.ID = uuid.New().String()
+	}
+	return scanIssuedQuestion(r.db.QueryRow(`
+		INSERT INTO issued_questions (id, user_id, book_name, question, reference_answer, explanation)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+issuedQuestionColumns,
+		q.ID, q.UserID, q.BookName, q.Question, q.ReferenceAnswer, q.Explanation))
+}
+
+func (r *QuestionRepositoryPostgres) GetIssued(id string, userID int) (*chat.IssuedQuestion, error) {
+	q, err := scanIssuedQuestion(r.db.QueryRow(`
+		SELECT `+issuedQuestionColumns+` FROM issued_questions WHERE id = $1 AND user_id = $2
+	`, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, chat.ErrQuestionNotFound
+	}
+	return q, err
+}
+
+func scanIssuedQuestion(row rowScanner) (*chat.IssuedQuestion, error) {
+	var q chat.IssuedQuestion
+	if err := row.Scan(&q.ID, &q.UserID, &q.BookName, &q.Question, &q.ReferenceAnswer, &q.Explanation, &q.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
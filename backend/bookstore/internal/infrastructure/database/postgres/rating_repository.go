@@ -0,0 +1,292 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/bereke1t2/bookstore/internal/domain/rating"
+	"github.com/google/uuid"
+)
+
+var _ rating.Repository = (*RatingRepositoryPostgres)(nil)
+
+const bookReviewColumns = `id, book_id, user_id, rating, text, status, created_at, updated_at, report_count, moderation_reason, moderated_at`
+
+type RatingRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewRatingRepositoryPostgres(db *sql.DB) *RatingRepositoryPostgres {
+	return &RatingRepositoryPostgres{db: db}
+}
+
+// CreateBookReviewTables creates the book_reviews and review_reports tables
+// if they don't exist, and the rating_count column on books.
+func (r *RatingRepositoryPostgres) CreateBookReviewTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS book_reviews (
+			id VARCHAR(36) PRIMARY KEY,
+			book_id VARCHAR(36) NOT NULL,
+			user_id INTEGER NOT NULL,
+			rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+			text TEXT NOT NULL DEFAULT '',
+			status VARCHAR(16) NOT NULL DEFAULT 'visible',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			report_count INTEGER NOT NULL DEFAULT 0,
+			moderation_reason TEXT NOT NULL DEFAULT '',
+			moderated_at TIMESTAMPTZ,
+			UNIQUE (book_id, user_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_book_reviews_book ON book_reviews(book_id, status, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_book_reviews_reported ON book_reviews(report_count DESC) WHERE report_count > 0;
+
+		CREATE TABLE IF NOT EXISTS review_reports (
+			review_id VARCHAR(36) NOT NULL REFERENCES book_reviews(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (review_id, user_id)
+		);
+
+		ALTER TABLE books ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *RatingRepositoryPostgres) Upsert(rv *rating.Review) (*rating.Review, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	if rv.ID == "" {
+		rv.ID = uuid.New().String()
+	}
+	var saved rating.Review
+	var created bool
+	err = tx.QueryRow(`
+		INSERT INTO book_reviews (id, book_id, user_id, rating, text)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (book_id, user_id) DO UPDATE
+			SET rating = EXCLUDED.rating, text = EXCLUDED.text, updated_at = NOW()
+		RETURNING `+bookReviewColumns+`, (xmax = 0)
+	`, rv.ID, rv.BookID, rv.UserID, rv.Rating, rv.Text).Scan(append(bookReviewFields(&saved), &created)...)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := refreshBookRating(tx, saved.BookID); err != nil {
+		return nil, false, err
+	}
+	return &saved, created, tx.Commit()
+}
+
+func (r *RatingRepositoryPostgres) Delete(bookID string, userID int) error {
+	return r.deleteWhere(`book_id = $1 AND user_id = $2`, bookID, userID)
+}
+
+func (r *RatingRepositoryPostgres) DeleteByID(reviewID string) error {
+	return r.deleteWhere(`id = $1`, reviewID)
+}
+
+func (r *RatingRepositoryPostgres) deleteWhere(where string, args ...any) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var bookID string
+	err = tx.QueryRow(`DELETE FROM book_reviews WHERE `+where+` RETURNING book_id`, args...).Scan(&bookID)
+	if err == sql.ErrNoRows {
+		return rating.ErrReviewNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := refreshBookRating(tx, bookID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *RatingRepositoryPostgres) GetByUser(bookID string, userID int) (*rating.Review, error) {
+	rv, err := scanBookReview(r.db.QueryRow(`
+		SELECT `+prefixColumns("r", bookReviewColumns)+`, u.username, u.profile_image
+		FROM book_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.book_id = $1 AND r.user_id = $2
+	`, bookID, userID))
+	if err == sql.ErrNoRows {
+		return nil, rating.ErrReviewNotFound
+	}
+	return rv, err
+}
+
+func (r *RatingRepositoryPostgres) ListByBook(bookID string, limit, offset int) ([]*rating.Review, error) {
+	return r.list(`
+		SELECT `+prefixColumns("r", bookReviewColumns)+`, u.username, u.profile_image
+		FROM book_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.book_id = $1 AND r.status = $2
+		ORDER BY r.created_at DESC, r.id
+		LIMIT $3 OFFSET $4
+	`, bookID, rating.StatusVisible, limit, offset)
+}
+
+func (r *RatingRepositoryPostgres) Summary(bookID string) (*rating.Summary, error) {
+	rows, err := r.db.Query(`
+		SELECT rating, COUNT(*) FROM book_reviews
+		WHERE book_id = $1 AND status = $2
+		GROUP BY rating
+	`, bookID, rating.StatusVisible)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	s := &rating.Summary{}
+	total := 0
+	for rows.Next() {
+		var stars, count int
+		if err := rows.Scan(&stars, &count); err != nil {
+			return nil, err
+		}
+		if stars >= rating.MinRating && stars <= rating.MaxRating {
+			s.Distribution[stars-1] = count
+			s.Count += count
+			total += stars * count
+		}
+	}
+	if s.Count > 0 {
+		s.Average = float64(total) / float64(s.Count)
+	}
+	return s, rows.Err()
+}
+
+func (r *RatingRepositoryPostgres) Report(reviewID string, userID int, reason string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var author int
+	err = tx.QueryRow(`SELECT user_id FROM book_reviews WHERE id = $1 FOR UPDATE`, reviewID).Scan(&author)
+	if err == sql.ErrNoRows {
+		return rating.ErrReviewNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if author == userID {
+		return rating.ErrOwnReview
+	}
+
+	res, err := tx.Exec(`
+		INSERT INTO review_reports (review_id, user_id, reason) VALUES ($1, $2, $3)
+		ON CONFLICT (review_id, user_id) DO NOTHING
+	`, reviewID, userID, reason)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE book_reviews SET report_count = report_count + 1 WHERE id = $1`, reviewID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *RatingRepositoryPostgres) ListReported(limit, offset int) ([]*rating.Review, error) {
+	return r.list(`
+		SELECT `+prefixColumns("r", bookReviewColumns)+`, u.username, u.profile_image
+		FROM book_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.report_count > 0 AND r.status = $1
+		ORDER BY r.report_count DESC, r.updated_at DESC
+		LIMIT $2 OFFSET $3
+	`, rating.StatusVisible, limit, offset)
+}
+
+// Moderate clears the review's reports when it is restored, so it leaves
+// the moderation queue.
+func (r *RatingRepositoryPostgres) Moderate(reviewID, status, reason string) (*rating.Review, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var saved rating.Review
+	err = tx.QueryRow(`
+		UPDATE book_reviews
+		SET status = $2, moderation_reason = $3, moderated_at = NOW(),
+			report_count = CASE WHEN $2 = 'visible' THEN 0 ELSE report_count END
+		WHERE id = $1
+		RETURNING `+bookReviewColumns,
+		reviewID, status, reason).Scan(bookReviewFields(&saved)...)
+	if err == sql.ErrNoRows {
+		return nil, rating.ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status == rating.StatusVisible {
+		if _, err := tx.Exec(`DELETE FROM review_reports WHERE review_id = $1`, reviewID); err != nil {
+			return nil, err
+		}
+	}
+	if err := refreshBookRating(tx, saved.BookID); err != nil {
+		return nil, err
+	}
+	return &saved, tx.Commit()
+}
+
+func (r *RatingRepositoryPostgres) list(query string, args ...any) ([]*rating.Review, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*rating.Review{}
+	for rows.Next() {
+		rv, err := scanBookReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+	}
+	return reviews, rows.Err()
+}
+
+// refreshBookRating recomputes the book's average and count from its
+// visible reviews.
+func refreshBookRating(tx *sql.Tx, bookID string) error {
+	_, err := tx.Exec(`
+		UPDATE books b
+		SET rating = COALESCE(s.average, 0), rating_count = s.count
+		FROM (
+			SELECT AVG(rating)::real AS average, COUNT(*) AS count
+			FROM book_reviews WHERE book_id = $1 AND status = $2
+		) s
+		WHERE b.id::text = $1
+	`, bookID, rating.StatusVisible)
+	return err
+}
+
+func scanBookReview(row rowScanner) (*rating.Review, error) {
+	var rv rating.Review
+	if err := row.Scan(append(bookReviewFields(&rv), &rv.Username, &rv.ProfileImage)...); err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+func bookReviewFields(rv *rating.Review) []any {
+	return []any{&rv.ID, &rv.BookID, &rv.UserID, &rv.Rating, &rv.Text, &rv.Status, &rv.CreatedAt, &rv.UpdatedAt,
+		&rv.ReportCount, &rv.ModerationReason, &rv.ModeratedAt}
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/bereke1t2/bookstore/internal/domain/reading"
+)
+
+var _ reading.ProgressRepository = (*ReadingProgressRepositoryPostgres)(nil)
+
+type ReadingProgressRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewReadingProgressRepositoryPostgres(db *sql.DB) *ReadingProgressRepositoryPostgres {
+	return &ReadingProgressRepositoryPostgres{db: db}
+}
+
+const readingProgressColumns = `user_id, book_id, page_number, cfi, percent, time_spent_seconds, status,
+	started_at, finished_at, updated_at`
+
+// CreateReadingProgressTable creates the reading_progress table if it
+// doesn't exist. counted_as_read records whether the book has been added to
+// the user's books_read_count, so finishing a book twice counts once.
+func (r *ReadingProgressRepositoryPostgres) CreateReadingProgressTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS reading_progress (
+			user_id INTEGER NOT NULL,
+			book_id VARCHAR(36) NOT NULL,
+			page_number INTEGER,
+			cfi TEXT NOT NULL DEFAULT '',
+			percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+			time_spent_seconds BIGINT NOT NULL DEFAULT 0,
+			status VARCHAR(16) NOT NULL DEFAULT 'reading',
+			started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			finished_at TIMESTAMPTZ,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			counted_as_read BOOLEAN NOT NULL DEFAULT FALSE,
+			PRIMARY KEY (user_id, book_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_reading_progress_user_updated ON reading_progress(user_id, updated_at DESC);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// Get retrieves the user's progress in a book.
+func (r *ReadingProgressRepositoryPostgres) Get(userID int, bookID string) (*reading.Progress, error) {
+	query := `SELECT ` + readingProgressColumns + ` FROM reading_progress WHERE user_id = $1 AND book_id = $2`
+	p, err := scanReadingProgress(r.db.QueryRow(query, userID, bookID))
+	if err == sql.ErrNoRows {
+		return nil, reading.ErrProgressNotFound
+	}
+	return p, err
+}
+
+// Save upserts the progress and, in the same transaction, increments the
+// user's books_read_count the first time the book is finished.
+func (r *ReadingProgressRepositoryPostgres) Save(p *reading.Progress) (*reading.Progress, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO reading_progress (` + readingProgressColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id, book_id) DO UPDATE SET
+			page_number = EXCLUDED.page_number,
+			cfi = EXCLUDED.cfi,
+			percent = EXCLUDED.percent,
+			time_spent_seconds = EXCLUDED.time_spent_seconds,
+			status = EXCLUDED.status,
+			finished_at = COALESCE(reading_progress.finished_at, EXCLUDED.finished_at),
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + readingProgressColumns
+	saved, err := scanReadingProgress(tx.QueryRow(query, p.UserID, p.BookID, p.PageNumber, p.CFI, p.Percent,
+		p.TimeSpentSeconds, p.Status, p.StartedAt, p.FinishedAt, p.UpdatedAt))
+	if err != nil {
+		return nil, err
+	}
+
+	if saved.Status == reading.StatusFinished {
+		res, err := tx.Exec(`
+			UPDATE reading_progress SET counted_as_read = TRUE
+			WHERE user_id = $1 AND book_id = $2 AND NOT counted_as_read
+		`, p.UserID, p.BookID)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if n > 0 {
+			if _, err := tx.Exec(`
+				UPDATE users SET books_read_count = books_read_count + 1, updated_at = NOW()
+				WHERE id = $1
+			`, p.UserID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// ListByUser returns every book the user has progress in, most recently
+// read first.
+func (r *ReadingProgressRepositoryPostgres) ListByUser(userID int) ([]*reading.Entry, error) {
+	query := `
+		SELECT ` + prefixColumns("p", readingProgressColumns) + `,
+			coalesce(b.title, ''), coalesce(b.author, ''), coalesce(b.cover_url, '')
+		FROM reading_progress p
+		LEFT JOIN books b ON b.id::text = p.book_id
+		WHERE p.user_id = $1
+		ORDER BY p.updated_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*reading.Entry
+	for rows.Next() {
+		var e reading.Entry
+		dest := append(readingProgressFields(&e.Progress), &e.Book.Title, &e.Book.Author, &e.Book.CoverUrl)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		e.Book.ID = e.BookID
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+func readingProgressFields(p *reading.Progress) []any {
+	return []any{&p.UserID, &p.BookID, &p.PageNumber, &p.CFI, &p.Percent, &p.TimeSpentSeconds, &p.Status,
+		&p.StartedAt, &p.FinishedAt, &p.UpdatedAt}
+}
+
+func scanReadingProgress(row rowScanner) (*reading.Progress, error) {
+	var p reading.Progress
+	if err := row.Scan(readingProgressFields(&p)...); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
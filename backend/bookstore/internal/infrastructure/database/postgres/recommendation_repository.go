@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/bereke1t2/bookstore/internal/domain/recommendation"
+)
+
+var _ recommendation.Repository = (*RecommendationRepositoryPostgres)(nil)
+
+// interactionsQuery scores every user's engagement with every local book:
+//   - how far they got, from reading progress or built-in shelves: finished
+//     3, reading 2, saved to read or a custom shelf 1;
+//   - their star rating, from a review or an imported shelf rating, moving
+//     the weight by rating-3 so low ratings count against the book;
+//   - half a point per note, up to 2.
+//
+// It reads the source tables directly, which is fine at catalog sizes where
+// a full scan takes milliseconds.
+const interactionsQuery = `
+	engagement AS (
+		SELECT user_id, book_id, MAX(weight) AS weight FROM (
+			SELECT user_id, book_id, CASE WHEN status = 'finished' THEN 3 ELSE 2 END AS weight
+			FROM reading_progress
+			UNION ALL
+			SELECT s.user_id, i.external_id,
+				CASE s.kind WHEN 'finished' THEN 3 WHEN 'reading' THEN 2 ELSE 1 END
+			FROM shelf_items i
+			JOIN shelves s ON s.id = i.shelf_id
+			WHERE i.source = 'local'
+		) e
+		GROUP BY user_id, book_id
+	),
+	ratings AS (
+		SELECT user_id, book_id, MAX(rating) AS rating FROM (
+			SELECT user_id, book_id, rating FROM book_reviews
+			UNION ALL
+			SELECT s.user_id, i.external_id, i.rating
+			FROM shelf_items i
+			JOIN shelves s ON s.id = i.shelf_id
+			WHERE i.source = 'local' AND i.rating > 0
+		) r
+		GROUP BY user_id, book_id
+	),
+	noted AS (
+		SELECT user_id, book_id, COUNT(*) AS notes
+		FROM notes
+		WHERE deleted_at IS NULL
+		GROUP BY user_id, book_id
+	),
+	engaged AS (
+		SELECT user_id, book_id FROM engagement
+		UNION
+		SELECT user_id, book_id FROM ratings
+		UNION
+		SELECT user_id, book_id FROM noted
+	),
+	interactions AS (
+		SELECT k.user_id, k.book_id,
+			COALESCE(e.weight, 0) + COALESCE(r.rating - 3, 0) + LEAST(COALESCE(n.notes, 0) * 0.5, 2)::float8 AS weight,
+			CASE
+				WHEN r.rating >= 4 THEN 'rated'
+				WHEN e.weight = 3 THEN 'finished'
+				WHEN e.weight = 2 THEN 'reading'
+				WHEN n.notes > 0 THEN 'noted'
+				ELSE 'saved'
+			END AS kind
+		FROM engaged k
+		LEFT JOIN engagement e ON e.user_id = k.user_id AND e.book_id = k.book_id
+		LEFT JOIN ratings r ON r.user_id = k.user_id AND r.book_id = k.book_id
+		LEFT JOIN noted n ON n.user_id = k.user_id AND n.book_id = k.book_id
+	)`
+
+type RecommendationRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewRecommendationRepositoryPostgres(db *sql.DB) *RecommendationRepositoryPostgres {
+	return &RecommendationRepositoryPostgres{db: db}
+}
+
+func (r *RecommendationRepositoryPostgres) Signals(userID int) ([]recommendation.Signal, error) {
+	rows, err := r.db.Query(`
+		WITH `+interactionsQuery+`
+		SELECT book_id, weight, kind
+		FROM interactions
+		WHERE user_id = $1
+		ORDER BY weight DESC, book_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	signals := []recommendation.Signal{}
+	for rows.Next() {
+		var s recommendation.Signal
+		if err := rows.Scan(&s.BookID, &s.Weight, &s.Kind); err != nil {
+			return nil, err
+		}
+		signals = append(signals, s)
+	}
+	return signals, rows.Err()
+}
+
+// Neighbors computes item-to-item cosine similarity over positive
+// engagement, scaled by n/(n+2) for n co-readers so a single shared reader
+// does not make two books look alike.
+func (r *RecommendationRepositoryPostgres) Neighbors(seedIDs []string, userID int, limit int) ([]recommendation.Neighbor, error) {
+	if len(seedIDs) == 0 {
+		return []recommendation.Neighbor{}, nil
+	}
+	rows, err := r.db.Query(`
+		WITH `+interactionsQuery+`,
+		positive AS (
+			SELECT user_id, book_id, weight FROM interactions WHERE weight > 0
+		),
+		norms AS (
+			SELECT book_id, SQRT(SUM(weight * weight)) AS norm FROM positive GROUP BY book_id
+		)
+		SELECT a.book_id, b.book_id,
+			SUM(a.weight * b.weight) / (na.norm * nb.norm) * COUNT(*) / (COUNT(*) + 2.0)::float8 AS similarity
+		FROM positive a
+		JOIN positive b ON b.user_id = a.user_id AND b.book_id <> a.book_id
+		JOIN norms na ON na.book_id = a.book_id
+		JOIN norms nb ON nb.book_id = b.book_id
+		WHERE a.book_id = ANY($1) AND a.user_id <> $2
+		GROUP BY a.book_id, b.book_id, na.norm, nb.norm
+		ORDER BY similarity DESC, b.book_id
+		LIMIT $3
+	`, seedIDs, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	neighbors := []recommendation.Neighbor{}
+	for rows.Next() {
+		var n recommendation.Neighbor
+		if err := rows.Scan(&n.SeedID, &n.BookID, &n.Similarity); err != nil {
+			return nil, err
+		}
+		neighbors = append(neighbors, n)
+	}
+	return neighbors, rows.Err()
+}
+
+func (r *RecommendationRepositoryPostgres) Popular(limit int) ([]recommendation.Popularity, error) {
+	rows, err := r.db.Query(`
+		WITH `+interactionsQuery+`
+		SELECT book_id, COUNT(*) AS readers, SUM(weight) AS score
+		FROM interactions
+		WHERE weight > 0
+		GROUP BY book_id
+		ORDER BY readers DESC, score DESC, book_id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	popular := []recommendation.Popularity{}
+	for rows.Next() {
+		var p recommendation.Popularity
+		if err := rows.Scan(&p.BookID, &p.Readers, &p.Score); err != nil {
+			return nil, err
+		}
+		popular = append(popular, p)
+	}
+	return popular, rows.Err()
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/review"
+	"github.com/google/uuid"
+)
+
+var _ review.CardRepository = (*ReviewCardRepositoryPostgres)(nil)
+
+type ReviewCardRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewReviewCardRepositoryPostgres(db *sql.DB) *ReviewCardRepositoryPostgres {
+	return &ReviewCardRepositoryPostgres{db: db}
+}
+
+const reviewCardColumns = `id, user_id, book_id, front, back, source_type, source_key,
+	ease_factor, interval_days, repetitions, due_at, last_reviewed_at, created_at`
+
+// CreateReviewCardTable creates the review_cards table if it doesn't exist.
+func (r *ReviewCardRepositoryPostgres) CreateReviewCardTable() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS review_cards (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			book_id VARCHAR(36) NOT NULL,
+			front TEXT NOT NULL,
+			back TEXT NOT NULL,
+			source_type VARCHAR(32) NOT NULL,
+			source_key VARCHAR(64) NOT NULL,
+			ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+			interval_days INTEGER NOT NULL DEFAULT 0,
+			repetitions INTEGER NOT NULL DEFAULT 0,
+			due_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			last_reviewed_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			UNIQUE (user_id, source_key)
+		);
+		CREATE INDEX IF NOT EXISTS idx_review_cards_user_due ON review_cards(user_id, due_at);
+		CREATE INDEX IF NOT EXISTS idx_review_cards_user_book ON review_cards(user_id, book_id);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// CreateMany inserts new cards, ignoring duplicates of cards the user already has.
+func (r *ReviewCardRepositoryPostgres) CreateMany(cards []*review.Card) ([]*review.Card, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO review_cards (` + reviewCardColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (user_id, source_key) DO NOTHING
+		RETURNING ` + reviewCardColumns
+
+	var created []*review.Card
+	for _, c := range cards {
+		if c.ID == "" {
+			c.ID = uuid.New().String()
+		}
+		if c.CreatedAt.IsZero() {
+			c.CreatedAt = time.Now()
+		}
+		row := tx.QueryRow(query, c.ID, c.UserID, c.BookID, c.Front, c.Back, c.SourceType, c.SourceKey,
+			c.EaseFactor, c.IntervalDays, c.Repetitions, c.DueAt, c.LastReviewedAt, c.CreatedAt)
+		card, err := scanReviewCard(row)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, card)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetByID retrieves a card owned by the user.
+func (r *ReviewCardRepositoryPostgres) GetByID(cardID string, userID int) (*review.Card, error) {
+	query := `SELECT ` + reviewCardColumns + ` FROM review_cards WHERE id = $1 AND user_id = $2`
+	card, err := scanReviewCard(r.db.QueryRow(query, cardID, userID))
+	if err == sql.ErrNoRows {
+		return nil, review.ErrCardNotFound
+	}
+	return card, err
+}
+
+// GetDue lists the user's cards due at now, oldest first. An empty bookID
+// covers every deck.
+func (r *ReviewCardRepositoryPostgres) GetDue(userID int, bookID string, now time.Time, limit int) ([]*review.Card, error) {
+	query := `
+		SELECT ` + reviewCardColumns + `
+		FROM review_cards
+		WHERE user_id = $1 AND due_at <= $2 AND ($3 = '' OR book_id = $3)
+		ORDER BY due_at ASC
+		LIMIT $4
+	`
+	rows, err := r.db.Query(query, userID, now, bookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []*review.Card
+	for rows.Next() {
+		card, err := scanReviewCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+// Update saves the scheduling state of a card.
+func (r *ReviewCardRepositoryPostgres) Update(c *review.Card) (*review.Card, error) {
+	query := `
+		UPDATE review_cards
+		SET ease_factor = $1, interval_days = $2, repetitions = $3, due_at = $4, last_reviewed_at = $5
+		WHERE id = $6 AND user_id = $7
+		RETURNING ` + reviewCardColumns
+	card, err := scanReviewCard(r.db.QueryRow(query, c.EaseFactor, c.IntervalDays, c.Repetitions, c.DueAt, c.LastReviewedAt, c.ID, c.UserID))
+	if err == sql.ErrNoRows {
+		return nil, review.ErrCardNotFound
+	}
+	return card, err
+}
+
+// GetDecks returns one deck per book with total and due card counts.
+func (r *ReviewCardRepositoryPostgres) GetDecks(userID int, now time.Time) ([]*review.Deck, error) {
+	query := `
+		SELECT book_id, COUNT(*), COUNT(*) FILTER (WHERE due_at <= $2)
+		FROM review_cards
+		WHERE user_id = $1
+		GROUP BY book_id
+		ORDER BY book_id
+	`
+	rows, err := r.db.Query(query, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var decks []*review.Deck
+	for rows.Next() {
+		var d review.Deck
+		if err := rows.Scan(&d.BookID, &d.TotalCards, &d.DueCards); err != nil {
+			return nil, err
+		}
+		decks = append(decks, &d)
+	}
+	return decks, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReviewCard(row rowScanner) (*review.Card, error) {
+	var c review.Card
+	err := row.Scan(&c.ID, &c.UserID, &c.BookID, &c.Front, &c.Back, &c.SourceType, &c.SourceKey,
+		&c.EaseFactor, &c.IntervalDays, &c.Repetitions, &c.DueAt, &c.LastReviewedAt, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,368 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+	"github.com/google/uuid"
+)
+
+var _ shelf.Repository = (*ShelfRepositoryPostgres)(nil)
+
+const (
+	shelfColumns     = `id, user_id, name, kind, position, created_at`
+	shelfItemColumns = `id, shelf_id, source, external_id, title, author, cover_url, isbn, rating, position, added_at`
+)
+
+type ShelfRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewShelfRepositoryPostgres(db *sql.DB) *ShelfRepositoryPostgres {
+	return &ShelfRepositoryPostgres{db: db}
+}
+
+// CreateShelfTables creates the shelves and shelf_items tables if they
+// don't exist.
+func (r *ShelfRepositoryPostgres) CreateShelfTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS shelves (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			name VARCHAR(64) NOT NULL,
+			kind VARCHAR(16) NOT NULL DEFAULT 'custom',
+			position INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_shelves_user_name ON shelves(user_id, LOWER(name));
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_shelves_user_builtin ON shelves(user_id, kind) WHERE kind <> 'custom';
+
+		CREATE TABLE IF NOT EXISTS shelf_items (
+			id VARCHAR(36) PRIMARY KEY,
+			shelf_id VARCHAR(36) NOT NULL REFERENCES shelves(id) ON DELETE CASCADE,
+			source VARCHAR(32) NOT NULL,
+			external_id VARCHAR(128) NOT NULL,
+			title TEXT NOT NULL,
+			author TEXT NOT NULL DEFAULT '',
+			cover_url TEXT NOT NULL DEFAULT '',
+			isbn VARCHAR(13) NOT NULL DEFAULT '',
+			position INTEGER NOT NULL DEFAULT 0,
+			added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (shelf_id, source, external_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_shelf_items_book ON shelf_items(source, external_id);
+		ALTER TABLE shelf_items ADD COLUMN IF NOT EXISTS rating SMALLINT NOT NULL DEFAULT 0;
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *ShelfRepositoryPostgres) EnsureBuiltins(userID int) error {
+	for i, b := range shelf.Builtins {
+		_, err := r.db.Exec(`
+			INSERT INTO shelves (id, user_id, name, kind, position)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT DO NOTHING
+		`, uuid.New().String(), userID, b.Name, b.Kind, i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ShelfRepositoryPostgres) ListShelves(userID int) ([]*shelf.Shelf, error) {
+	rows, err := r.db.Query(`
+		SELECT `+prefixColumns("s", shelfColumns)+`,
+			(SELECT COUNT(*) FROM shelf_items i WHERE i.shelf_id = s.id)
+		FROM shelves s
+		WHERE s.user_id = $1
+		ORDER BY s.position, s.created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shelves := []*shelf.Shelf{}
+	for rows.Next() {
+		var s shelf.Shelf
+		if err := rows.Scan(append(shelfFields(&s), &s.ItemCount)...); err != nil {
+			return nil, err
+		}
+		shelves = append(shelves, &s)
+	}
+	return shelves, rows.Err()
+}
+
+func (r *ShelfRepositoryPostgres) GetShelf(userID int, ref string) (*shelf.Shelf, error) {
+	var s shelf.Shelf
+	err := r.db.QueryRow(`
+		SELECT `+prefixColumns("s", shelfColumns)+`,
+			(SELECT COUNT(*) FROM shelf_items i WHERE i.shelf_id = s.id)
+		FROM shelves s
+		WHERE s.user_id = $1 AND (s.id = $2 OR (s.kind = $2 AND s.kind <> $3))
+	`, userID, ref, shelf.KindCustom).Scan(append(shelfFields(&s), &s.ItemCount)...)
+	if err == sql.ErrNoRows {
+		return nil, shelf.ErrShelfNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *ShelfRepositoryPostgres) CreateShelf(s *shelf.Shelf) (*shelf.Shelf, error) {
+	var custom int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM shelves WHERE user_id = $1 AND kind = $2`, s.UserID, shelf.KindCustom).Scan(&custom)
+	if err != nil {
+		return nil, err
+	}
+	if custom >= shelf.MaxCustomShelves {
+		return nil, fmt.Errorf("%w: at most %d custom shelves", shelf.ErrTooManyShelves, shelf.MaxCustomShelves)
+	}
+
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	var created shelf.Shelf
+	err = r.db.QueryRow(`
+		INSERT INTO shelves (id, user_id, name, kind, position)
+		SELECT $1, $2, $3, $4, COALESCE(MAX(position) + 1, 0) FROM shelves WHERE user_id = $2
+		ON CONFLICT DO NOTHING
+		RETURNING `+shelfColumns,
+		s.ID, s.UserID, s.Name, shelf.KindCustom).Scan(shelfFields(&created)...)
+	if err == sql.ErrNoRows {
+		return nil, shelf.ErrShelfExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *ShelfRepositoryPostgres) RenameShelf(userID int, id, name string) (*shelf.Shelf, error) {
+	s, err := r.GetShelf(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.Kind != shelf.KindCustom {
+		return nil, shelf.ErrBuiltinShelf
+	}
+
+	err = r.db.QueryRow(`
+		UPDATE shelves SET name = $3
+		WHERE id = $1 AND user_id = $2
+			AND NOT EXISTS (SELECT 1 FROM shelves WHERE user_id = $2 AND LOWER(name) = LOWER($3) AND id <> $1)
+		RETURNING name
+	`, s.ID, userID, name).Scan(&s.Name)
+	if err == sql.ErrNoRows {
+		return nil, shelf.ErrShelfExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *ShelfRepositoryPostgres) DeleteShelf(userID int, id string) error {
+	s, err := r.GetShelf(userID, id)
+	if err != nil {
+		return err
+	}
+	if s.Kind != shelf.KindCustom {
+		return shelf.ErrBuiltinShelf
+	}
+	_, err = r.db.Exec(`DELETE FROM shelves WHERE id = $1 AND user_id = $2`, s.ID, userID)
+	return err
+}
+
+func (r *ShelfRepositoryPostgres) ReorderShelves(userID int, ids []string) error {
+	return r.reorder(`SELECT id FROM shelves WHERE user_id = $1 FOR UPDATE`, userID,
+		`UPDATE shelves SET position = $2 WHERE id = $1`, ids)
+}
+
+func (r *ShelfRepositoryPostgres) ListItems(shelfID string) ([]*shelf.Item, error) {
+	rows, err := r.db.Query(`
+		SELECT `+shelfItemColumns+` FROM shelf_items
+		WHERE shelf_id = $1
+		ORDER BY position, added_at
+	`, shelfID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*shelf.Item{}
+	for rows.Next() {
+		var i shelf.Item
+		if err := rows.Scan(shelfItemFields(&i)...); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	return items, rows.Err()
+}
+
+func (r *ShelfRepositoryPostgres) AddItem(userID int, item *shelf.Item) (*shelf.Item, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var kind string
+	err = tx.QueryRow(`SELECT kind FROM shelves WHERE id = $1 AND user_id = $2 FOR UPDATE`, item.ShelfID, userID).Scan(&kind)
+	if err == sql.ErrNoRows {
+		return nil, shelf.ErrShelfNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if shelf.IsBuiltin(kind) {
+		_, err := tx.Exec(`
+			DELETE FROM shelf_items
+			WHERE source = $1 AND external_id = $2 AND shelf_id IN (
+				SELECT id FROM shelves WHERE user_id = $3 AND kind <> $4 AND id <> $5
+			)
+		`, item.Source, item.ExternalID, userID, shelf.KindCustom, item.ShelfID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if item.ID == "" {
+		item.ID = uuid.New().String()
+	}
+	var saved shelf.Item
+	err = tx.QueryRow(`
+		INSERT INTO shelf_items (id, shelf_id, source, external_id, title, author, cover_url, isbn, rating, position)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE(MAX(position) + 1, 0) FROM shelf_items WHERE shelf_id = $2
+		ON CONFLICT (shelf_id, source, external_id) DO UPDATE
+			SET rating = CASE WHEN EXCLUDED.rating > 0 THEN EXCLUDED.rating ELSE shelf_items.rating END
+		RETURNING `+shelfItemColumns,
+		item.ID, item.ShelfID, item.Source, item.ExternalID, item.Title, item.Author, item.CoverUrl, item.ISBN, item.Rating,
+	).Scan(shelfItemFields(&saved)...)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, tx.Commit()
+}
+
+func (r *ShelfRepositoryPostgres) RemoveItem(shelfID, itemID string) error {
+	res, err := r.db.Exec(`DELETE FROM shelf_items WHERE id = $1 AND shelf_id = $2`, itemID, shelfID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return shelf.ErrItemNotFound
+	}
+	return nil
+}
+
+func (r *ShelfRepositoryPostgres) ReorderItems(shelfID string, ids []string) error {
+	return r.reorder(`SELECT id FROM shelf_items WHERE shelf_id = $1 FOR UPDATE`, shelfID,
+		`UPDATE shelf_items SET position = $2 WHERE id = $1`, ids)
+}
+
+// reorder sets positions following ids, which must hold exactly the ids
+// selected by list.
+func (r *ShelfRepositoryPostgres) reorder(list string, owner any, update string, ids []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(list, owner)
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(ids) != len(existing) {
+		return shelf.ErrInvalidOrder
+	}
+	for _, id := range ids {
+		if !existing[id] {
+			return shelf.ErrInvalidOrder
+		}
+		delete(existing, id)
+	}
+
+	for pos, id := range ids {
+		if _, err := tx.Exec(update, id, pos); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListExportRows merges the items for the same book across shelves. The
+// first item in shelf order supplies the book's details.
+func (r *ShelfRepositoryPostgres) ListExportRows(userID int, shelfID string) ([]*shelf.ExportRow, error) {
+	rows, err := r.db.Query(`
+		SELECT `+prefixColumns("i", shelfItemColumns)+`, s.kind, s.name, p.finished_at
+		FROM shelf_items i
+		JOIN shelves s ON s.id = i.shelf_id
+		LEFT JOIN reading_progress p ON p.user_id = s.user_id AND p.book_id = i.external_id AND i.source = $3
+		WHERE s.user_id = $1 AND ($2 = '' OR (i.source, i.external_id) IN (
+			SELECT source, external_id FROM shelf_items WHERE shelf_id = $2
+		))
+		ORDER BY s.position, i.position, i.added_at
+	`, userID, shelfID, shelf.SourceLocal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*shelf.ExportRow
+	byBook := map[string]*shelf.ExportRow{}
+	for rows.Next() {
+		var row shelf.ExportRow
+		var kind, name string
+		if err := rows.Scan(append(shelfItemFields(&row.Item), &kind, &name, &row.DateRead)...); err != nil {
+			return nil, err
+		}
+		label := name
+		if shelf.IsBuiltin(kind) {
+			label = kind
+		}
+
+		key := row.Source + ":" + row.ExternalID
+		if existing, ok := byBook[key]; ok {
+			existing.Shelves = append(existing.Shelves, label)
+			continue
+		}
+		row.Shelves = []string{label}
+		byBook[key] = &row
+		result = append(result, &row)
+	}
+	return result, rows.Err()
+}
+
+func shelfFields(s *shelf.Shelf) []any {
+	return []any{&s.ID, &s.UserID, &s.Name, &s.Kind, &s.Position, &s.CreatedAt}
+}
+
+func shelfItemFields(i *shelf.Item) []any {
+	return []any{&i.ID, &i.ShelfID, &i.Source, &i.ExternalID, &i.Title, &i.Author, &i.CoverUrl, &i.ISBN, &i.Rating, &i.Position, &i.AddedAt}
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/trending"
+)
+
+var _ trending.Repository = (*TrendingRepositoryPostgres)(nil)
+
+// TrendingRepositoryPostgres keeps recorded signals in book_events and the
+// scores from the last refresh in book_trending, so /books/trending reads a
+// small precomputed table instead of scanning every signal.
+type TrendingRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewTrendingRepositoryPostgres(db *sql.DB) *TrendingRepositoryPostgres {
+	return &TrendingRepositoryPostgres{db: db}
+}
+
+// CreateTrendingTables creates the book_events and book_trending tables if
+// they don't exist.
+func (r *TrendingRepositoryPostgres) CreateTrendingTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS book_events (
+			id BIGSERIAL PRIMARY KEY,
+			book_id VARCHAR(36) NOT NULL,
+			user_id INTEGER NOT NULL,
+			kind VARCHAR(16) NOT NULL,
+			occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_book_events_occurred ON book_events(occurred_at);
+
+		CREATE TABLE IF NOT EXISTS book_trending (
+			book_id VARCHAR(36) PRIMARY KEY,
+			score DOUBLE PRECISION NOT NULL,
+			refreshed_at TIMESTAMPTZ NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_book_trending_score ON book_trending(score DESC);
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// Record stores the event against the book with its ID or, failing that,
+// its title. Events for books not in the catalog are dropped.
+func (r *TrendingRepositoryPostgres) Record(e *trending.Event) error {
+	_, err := r.db.Exec(`
+		INSERT INTO book_events (book_id, user_id, kind, occurred_at)
+		SELECT b.id::text, $3::int, $4::text, $5::timestamptz
+		FROM books b
+		WHERE ($1 <> '' AND b.id::text = $1) OR ($1 = '' AND LOWER(b.title) = LOWER($2))
+		ORDER BY b.id
+		LIMIT 1
+	`, e.BookID, e.BookTitle, e.UserID, e.Kind, e.OccurredAt)
+	return err
+}
+
+// Refresh replaces the scores in one transaction, so readers see either the
+// old or the new ranking. Each user counts once per book, kind and day, and
+// every signal halves in weight each half-life.
+func (r *TrendingRepositoryPostgres) Refresh(cfg trending.Config, now time.Time) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM book_trending`); err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec(`
+		WITH signals AS (
+			SELECT book_id, kind, MAX(occurred_at) AS at
+			FROM book_events
+			WHERE occurred_at >= $1
+			GROUP BY book_id, user_id, kind, DATE_TRUNC('day', occurred_at)
+			UNION ALL
+			SELECT book_id, 'read', updated_at
+			FROM reading_progress
+			WHERE updated_at >= $1
+			UNION ALL
+			SELECT book_id, 'note', MAX(created_at)
+			FROM notes
+			WHERE created_at >= $1 AND deleted_at IS NULL
+			GROUP BY book_id, user_id, DATE_TRUNC('day', created_at)
+		),
+		scored AS (
+			SELECT s.book_id, SUM(
+				CASE s.kind
+					WHEN 'download' THEN $3::float8
+					WHEN 'read' THEN $4::float8
+					WHEN 'note' THEN $5::float8
+					WHEN 'quiz' THEN $6::float8
+					ELSE 0
+				END
+				* POWER(0.5::float8, EXTRACT(EPOCH FROM ($2::timestamptz - s.at))::float8 / $7::float8)
+			) AS score
+			FROM signals s
+			JOIN books b ON b.id::text = s.book_id
+			GROUP BY s.book_id
+		)
+		INSERT INTO book_trending (book_id, score, refreshed_at)
+		SELECT book_id, score, $2 FROM scored WHERE score > 0
+	`, now.Add(-cfg.Window), now,
+		cfg.Signals[trending.KindDownload], cfg.Signals[trending.KindRead],
+		cfg.Signals[trending.KindNote], cfg.Signals[trending.KindQuiz],
+		cfg.HalfLife.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), tx.Commit()
+}
+
+func (r *TrendingRepositoryPostgres) Top(limit int) ([]*trending.Score, error) {
+	rows, err := r.db.Query(`
+		SELECT book_id, score, refreshed_at
+		FROM book_trending
+		ORDER BY score DESC, book_id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := []*trending.Score{}
+	for rows.Next() {
+		var s trending.Score
+		if err := rows.Scan(&s.BookID, &s.Score, &s.RefreshedAt); err != nil {
+			return nil, err
+		}
+		scores = append(scores, &s)
+	}
+	return scores, rows.Err()
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"database/sql"
+	"strings"
 
 	"github.com/bereke1t2/bookstore/internal/domain/user"
 )
@@ -18,6 +19,17 @@ func NewUserRepositoryPostgres(db *sql.DB) *UserRepositoryPostgres {
 	}
 }
 
+// MigrateUserTable adds the role column and makes emails unique regardless
+// of case. Roles are only ever written by SetRole.
+func (r *UserRepositoryPostgres) MigrateUserTable() error {
+	query := `
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'user';
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users(LOWER(email));
+	`
+	_, err := r.db.Exec(query)
+	return err
+}
+
 func (r *UserRepositoryPostgres) CreateUser(newUser user.User) (user.User, error) {
 	query := `
 		INSERT INTO users (
@@ -44,7 +56,9 @@ func (r *UserRepositoryPostgres) CreateUser(newUser user.User) (user.User, error
 			books_read_count,
 			reading_streak,
 			last_read_date,
-			points
+			points,
+			timezone,
+			role
 	`
 	row := r.db.QueryRow(
 		query,
@@ -71,6 +85,8 @@ func (r *UserRepositoryPostgres) CreateUser(newUser user.User) (user.User, error
 		&createdUser.ReadingStreak,
 		&createdUser.LastReadDate,
 		&createdUser.Points,
+		&createdUser.Timezone,
+		&createdUser.Role,
 	)
 	if err != nil {
 		return user.User{}, err
@@ -91,7 +107,9 @@ func (r *UserRepositoryPostgres) GetUserByID(id string) (user.User, error) {
 			books_read_count,
 			reading_streak,
 			last_read_date,
-			points
+			points,
+			timezone,
+			role
 		FROM users
 		WHERE id = $1
 	`
@@ -110,6 +128,8 @@ func (r *UserRepositoryPostgres) GetUserByID(id string) (user.User, error) {
 		&foundUser.ReadingStreak,
 		&foundUser.LastReadDate,
 		&foundUser.Points,
+		&foundUser.Timezone,
+		&foundUser.Role,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -133,7 +153,9 @@ func (r *UserRepositoryPostgres) GetAllUsers() ([]user.User, error) {
 			books_read_count,
 			reading_streak,
 			last_read_date,
-			points
+			points,
+			timezone,
+			role
 		FROM users
 	`
 	rows, err := r.db.Query(query)
@@ -157,6 +179,8 @@ func (r *UserRepositoryPostgres) GetAllUsers() ([]user.User, error) {
 			&u.ReadingStreak,
 			&u.LastReadDate,
 			&u.Points,
+			&u.Timezone,
+			&u.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -165,6 +189,8 @@ func (r *UserRepositoryPostgres) GetAllUsers() ([]user.User, error) {
 	return users, nil
 }
 
+// UpdateUser saves the user's profile fields. The reading counters and
+// points are derived from activity and are never written here.
 func (r *UserRepositoryPostgres) UpdateUser(updatedUser user.User) (user.User, error) {
 	query := `
 		UPDATE users
@@ -173,12 +199,9 @@ func (r *UserRepositoryPostgres) UpdateUser(updatedUser user.User) (user.User, e
 			email = $2,
 			password_hash = $3,
 			profile_image = $4,
-			books_read_count = $5,
-			reading_streak = $6,
-			last_read_date = $7,
-			points = $8,
+			timezone = $5,
 			updated_at = NOW()
-		WHERE id = $9
+		WHERE id = $6
 		RETURNING
 			id,
 			username,
@@ -190,7 +213,9 @@ func (r *UserRepositoryPostgres) UpdateUser(updatedUser user.User) (user.User, e
 			books_read_count,
 			reading_streak,
 			last_read_date,
-			points
+			points,
+			timezone,
+			role
 	`
 	row := r.db.QueryRow(
 		query,
@@ -198,10 +223,7 @@ func (r *UserRepositoryPostgres) UpdateUser(updatedUser user.User) (user.User, e
 		updatedUser.Email,
 		updatedUser.PasswordHash,
 		updatedUser.ProfileImage,
-		updatedUser.BooksReadCount,
-		updatedUser.ReadingStreak,
-		updatedUser.LastReadDate,
-		updatedUser.Points,
+		updatedUser.Timezone,
 		updatedUser.ID,
 	)
 
@@ -218,6 +240,8 @@ func (r *UserRepositoryPostgres) UpdateUser(updatedUser user.User) (user.User, e
 		&res.ReadingStreak,
 		&res.LastReadDate,
 		&res.Points,
+		&res.Timezone,
+		&res.Role,
 	)
 	if err != nil {
 		return user.User{}, err
@@ -247,11 +271,13 @@ func (r *UserRepositoryPostgres) GetUserByEmail(email string) (user.User, error)
 			books_read_count,
 			reading_streak,
 			last_read_date,
-			points
+			points,
+			timezone,
+			role
 		FROM users
-		WHERE email = $1
+		WHERE LOWER(email) = LOWER($1)
 	`
-	row := r.db.QueryRow(query, email)
+	row := r.db.QueryRow(query, strings.TrimSpace(email))
 
 	var foundUser user.User
 	err := row.Scan(
@@ -266,6 +292,8 @@ func (r *UserRepositoryPostgres) GetUserByEmail(email string) (user.User, error)
 		&foundUser.ReadingStreak,
 		&foundUser.LastReadDate,
 		&foundUser.Points,
+		&foundUser.Timezone,
+		&foundUser.Role,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -274,4 +302,19 @@ func (r *UserRepositoryPostgres) GetUserByEmail(email string) (user.User, error)
 		return user.User{}, err
 	}
 	return foundUser, nil
-}
\ No newline at end of file
+}
+
+func (r *UserRepositoryPostgres) SetRole(id int, role string) error {
+	res, err := r.db.Exec(`UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2`, role, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrNotFound
+	}
+	return nil
+}
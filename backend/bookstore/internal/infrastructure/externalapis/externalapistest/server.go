@@ -0,0 +1,240 @@
+// Package externalapistest provides a fake external book provider for
+// tests and local development. It serves the subset of the Google Books and
+// Open Library APIs the providers in externalapis use, from an in-memory
+// list of books.
+package externalapistest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+)
+
+// Server is a running fake provider. Point a provider at it with
+// externalapis.ProviderOptions{BaseURL: s.GoogleBooksURL()} or
+// s.OpenLibraryURL().
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	books    []*catalog.ExternalBook
+	failures int
+	status   int
+	delay    time.Duration
+	requests int
+}
+
+// NewServer starts a fake serving books. Call Close when done.
+func NewServer(books ...*catalog.ExternalBook) *Server {
+	s := &Server{books: books}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /google/volumes", s.googleVolumes)
+	mux.HandleFunc("GET /google/volumes/{id}", s.googleVolume)
+	mux.HandleFunc("GET /openlibrary/search.json", s.openLibrarySearch)
+	s.Server = httptest.NewServer(s.count(mux))
+	return s
+}
+
+// GoogleBooksURL is the base URL of the fake Google Books API.
+func (s *Server) GoogleBooksURL() string { return s.URL + "/google" }
+
+// OpenLibraryURL is the base URL of the fake Open Library API.
+func (s *Server) OpenLibraryURL() string { return s.URL + "/openlibrary" }
+
+// FailNext makes the next n requests fail with 503 Service Unavailable.
+func (s *Server) FailNext(n int) {
+	s.FailNextWith(n, http.StatusServiceUnavailable)
+}
+
+// FailNextWith makes the next n requests fail with status.
+func (s *Server) FailNextWith(n, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures, s.status = n, status
+}
+
+// Delay makes every request wait d before it is answered, or until the
+// client gives up.
+func (s *Server) Delay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// Requests returns how many requests the fake has received.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		fail, status, delay := s.failures > 0, s.status, s.delay
+		if fail {
+			s.failures--
+		}
+		s.mu.Unlock()
+
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fail {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// match returns up to limit books satisfying keep.
+func (s *Server) match(limit int, keep func(*catalog.ExternalBook) bool) []*catalog.ExternalBook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []*catalog.ExternalBook
+	for _, b := range s.books {
+		if len(found) == limit {
+			break
+		}
+		if keep(b) {
+			found = append(found, b)
+		}
+	}
+	return found
+}
+
+// matchQuery interprets the query prefixes both APIs share: "isbn:",
+// "subject:" and, for Open Library, "key:/works/". Anything else matches
+// the title or authors.
+func (s *Server) matchQuery(q string, limit int) []*catalog.ExternalBook {
+	switch {
+	case strings.HasPrefix(q, "isbn:"):
+		isbn := strings.TrimPrefix(q, "isbn:")
+		return s.match(limit, func(b *catalog.ExternalBook) bool { return b.ISBN10 == isbn || b.ISBN13 == isbn })
+	case strings.HasPrefix(q, "key:/works/"):
+		id := strings.TrimPrefix(q, "key:/works/")
+		return s.match(limit, func(b *catalog.ExternalBook) bool { return b.ID == id })
+	case strings.HasPrefix(q, "subject:"):
+		subject := strings.Trim(strings.TrimPrefix(q, "subject:"), `"`)
+		return s.match(limit, func(b *catalog.ExternalBook) bool {
+			for _, c := range b.Categories {
+				if strings.EqualFold(c, subject) {
+					return true
+				}
+			}
+			return false
+		})
+	default:
+		q = strings.ToLower(q)
+		return s.match(limit, func(b *catalog.ExternalBook) bool {
+			return strings.Contains(strings.ToLower(b.Title+" "+b.Author()), q)
+		})
+	}
+}
+
+func (s *Server) googleVolumes(w http.ResponseWriter, r *http.Request) {
+	books := s.matchQuery(r.URL.Query().Get("q"), queryLimit(r, "maxResults", 10))
+	items := make([]any, 0, len(books))
+	for _, b := range books {
+		items = append(items, googleVolume(b))
+	}
+	writeJSON(w, map[string]any{"totalItems": len(items), "items": items})
+}
+
+func (s *Server) googleVolume(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	books := s.match(1, func(b *catalog.ExternalBook) bool { return b.ID == id })
+	if len(books) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, googleVolume(books[0]))
+}
+
+func (s *Server) openLibrarySearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if isbn := r.URL.Query().Get("isbn"); isbn != "" {
+		q = "isbn:" + isbn
+	}
+	books := s.matchQuery(q, queryLimit(r, "limit", 100))
+	docs := make([]any, 0, len(books))
+	for _, b := range books {
+		docs = append(docs, openLibraryDoc(b))
+	}
+	writeJSON(w, map[string]any{"numFound": len(docs), "docs": docs})
+}
+
+func googleVolume(b *catalog.ExternalBook) map[string]any {
+	var ids []map[string]string
+	if b.ISBN10 != "" {
+		ids = append(ids, map[string]string{"type": "ISBN_10", "identifier": b.ISBN10})
+	}
+	if b.ISBN13 != "" {
+		ids = append(ids, map[string]string{"type": "ISBN_13", "identifier": b.ISBN13})
+	}
+	saleInfo := map[string]any{"saleability": "NOT_FOR_SALE"}
+	if b.Price != nil {
+		saleInfo = map[string]any{
+			"saleability": "FOR_SALE",
+			"listPrice":   map[string]any{"amount": b.Price.Amount, "currencyCode": b.Price.Currency},
+		}
+	}
+	return map[string]any{
+		"id": b.ID,
+		"volumeInfo": map[string]any{
+			"title":               b.Title,
+			"authors":             b.Authors,
+			"description":         b.Description,
+			"categories":          b.Categories,
+			"publishedDate":       b.PublishedDate,
+			"averageRating":       b.Rating,
+			"previewLink":         b.PreviewURL,
+			"industryIdentifiers": ids,
+			"imageLinks":          map[string]string{"thumbnail": b.CoverUrl},
+		},
+		"saleInfo": saleInfo,
+	}
+}
+
+func openLibraryDoc(b *catalog.ExternalBook) map[string]any {
+	var isbns []string
+	for _, n := range []string{b.ISBN13, b.ISBN10} {
+		if n != "" {
+			isbns = append(isbns, n)
+		}
+	}
+	year, _ := strconv.Atoi(b.PublishedDate[:min(len(b.PublishedDate), 4)])
+	return map[string]any{
+		"key":                "/works/" + b.ID,
+		"title":              b.Title,
+		"author_name":        b.Authors,
+		"isbn":               isbns,
+		"first_publish_year": year,
+		"subject":            b.Categories,
+		"ratings_average":    b.Rating,
+	}
+}
+
+func queryLimit(r *http.Request, name string, fallback int) int {
+	if n, err := strconv.Atoi(r.URL.Query().Get(name)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
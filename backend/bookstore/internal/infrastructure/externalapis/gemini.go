@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/bereke1t2/bookstore/internal/domain/chat"
@@ -12,6 +13,9 @@ import (
 	"google.golang.org/api/option"
 )
 
+// GeminiModelName is the model every Gemini-backed repository talks to.
+const GeminiModelName = "gemini-2.5-flash-lite"
+
 type GeminiClient struct {
 	ctx   context.Context
 	model *genai.GenerativeModel
@@ -24,7 +28,7 @@ func NewGeminiClient(apiKey string, ctx context.Context) (*GeminiClient, error)
 	}
 
 	// CHANGED: Use "gemini-2.5-flash-lite" - This is the best free model in late 2025
-	model := client.GenerativeModel("gemini-2.5-flash-lite")
+	model := client.GenerativeModel(GeminiModelName)
 
 	return &GeminiClient{
 		ctx:   ctx,
@@ -32,11 +36,44 @@ func NewGeminiClient(apiKey string, ctx context.Context) (*GeminiClient, error)
 	}, nil
 }
 
-// Model returns the underlying GenerativeModel for external use.
+// Model returns the underlying GenerativeModel for external use. It is
+// shared, so callers must not change its settings; use WithConfig instead.
 func (c *GeminiClient) Model() *genai.GenerativeModel {
 	return c.model
 }
 
+// WithConfig returns a copy of the model that generates with cfg. The shared
+// model is left untouched, so concurrent requests cannot see each other's
+// settings.
+func (c *GeminiClient) WithConfig(cfg genai.GenerationConfig) *genai.GenerativeModel {
+	m := *c.model
+	m.GenerationConfig = cfg
+	return &m
+}
+
+// Generation settings per kind of request. For the Free Lite model, keep
+// tokens lower to avoid hitting the "Tokens Per Minute" limit.
+var (
+	multipleChoiceConfig = genai.GenerationConfig{
+		MaxOutputTokens:  genai.Ptr[int32](4000),
+		Temperature:      genai.Ptr[float32](0.2),
+		ResponseMIMEType: "application/json",
+	}
+	quizConfig = genai.GenerationConfig{
+		MaxOutputTokens:  genai.Ptr[int32](2000),
+		ResponseMIMEType: "application/json",
+	}
+	gradeConfig = genai.GenerationConfig{
+		MaxOutputTokens:  genai.Ptr[int32](500),
+		Temperature:      genai.Ptr[float32](0),
+		ResponseMIMEType: "application/json",
+	}
+	chatConfig = genai.GenerationConfig{
+		MaxOutputTokens:  genai.Ptr[int32](1000),
+		ResponseMIMEType: "text/plain",
+	}
+)
+
 type ChatResponseImpl struct {
 	geminiClient *GeminiClient
 }
@@ -62,114 +99,96 @@ func extractText(resp *genai.GenerateContentResponse) string {
 	return message.String()
 }
 
-func parseQuizzes[T any](raw string) ([]T, error) {
-	type responseDTO struct {
-		BookTitle  string `json:"book_title"`
-		Difficulty string `json:"difficulty"`
-		Quizzes    []T    `json:"quizzes"`
+// generate runs a single prompt against the model with cfg and maps
+// failures to the chat domain errors.
+func (r *ChatResponseImpl) generate(cfg genai.GenerationConfig, prompt string) (string, error) {
+	resp, err := r.geminiClient.WithConfig(cfg).GenerateContent(r.geminiClient.ctx, genai.Text(prompt))
+	if err != nil {
+		return "", classifyGeminiError(err)
 	}
+	return extractText(resp), nil
+}
 
-	raw = strings.TrimSpace(raw)
-	raw = strings.TrimPrefix(raw, "```json")
-	raw = strings.TrimPrefix(raw, "```")
-	raw = strings.TrimSuffix(raw, "```")
-	raw = strings.TrimSpace(raw)
-
-	var data responseDTO
-	if err := json.Unmarshal([]byte(raw), &data); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON from model: %w", err)
+func classifyGeminiError(err error) error {
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return fmt.Errorf("%w: %v", chat.ErrAIMalformedResponse, err)
 	}
-
-	return data.Quizzes, nil
+	msg := err.Error()
+	// Detect if we are hitting the free tier rate limit
+	if strings.Contains(msg, "429") || strings.Contains(msg, "RESOURCE_EXHAUSTED") {
+		return chat.ErrAIRateLimited
+	}
+	return fmt.Errorf("%w: %v", chat.ErrAIUnavailable, err)
 }
 
-func (r *ChatResponseImpl) GetMultipleChoiceQuestion(id string, prompt string) ([]*chat.MultipleQuiz, error) {
-	// For the Free Lite model, keep tokens lower to avoid hitting the "Tokens Per Minute" limit
-	r.geminiClient.model.SetMaxOutputTokens(4000)
-	r.geminiClient.model.SetTemperature(0.2)
-	r.geminiClient.model.ResponseMIMEType = "application/json"
-
-	resp, err := r.geminiClient.model.GenerateContent(r.geminiClient.ctx, genai.Text(prompt))
+// generateQuizzes asks the model for quizzes and, when the output cannot be
+// used at all, retries once with a prompt that points out the problem.
+func generateQuizzes[T any, PT validatable[T]](r *ChatResponseImpl, cfg genai.GenerationConfig, prompt string) ([]*T, error) {
+	raw, err := r.generate(cfg, prompt)
 	if err != nil {
-		// Detect if we are hitting the free tier rate limit
-		if strings.Contains(err.Error(), "429") {
-			return nil, errors.New("free tier limit reached. Please wait 1 minute before trying again")
-		}
 		return nil, err
 	}
+	quizzes, err := parseQuizzes[T, PT](raw)
+	if err == nil {
+		return quizzes, nil
+	}
 
-	raw := extractText(resp)
-	print(raw)
-	quizzes, err := parseQuizzes[chat.MultipleQuiz](raw)
+	log.Printf("quiz output rejected, retrying with corrective prompt: %v", err)
+	raw, err = r.generate(cfg, correctivePrompt(prompt, err))
 	if err != nil {
 		return nil, err
 	}
+	return parseQuizzes[T, PT](raw)
+}
 
-	result := make([]*chat.MultipleQuiz, len(quizzes))
-	for i := range quizzes {
-		result[i] = &quizzes[i]
-	}
-	return result, nil
+func correctivePrompt(prompt string, cause error) string {
+	return prompt + fmt.Sprintf(`
+IMPORTANT: Your previous response could not be used (%v).
+Return ONLY complete, valid JSON that matches the structure above exactly.
+Fill every field, do not add comments or trailing commas, and keep explanations short so the response is not cut off.
+`, cause)
 }
 
-// Implement True/False and Short Answer similarly...
-func (r *ChatResponseImpl) GetTrueFalseQuestion(id string, prompt string) ([]*chat.TrueFalse, error) {
-	r.geminiClient.model.SetMaxOutputTokens(2000)
-	r.geminiClient.model.ResponseMIMEType = "application/json"
-	resp, err := r.geminiClient.model.GenerateContent(r.geminiClient.ctx, genai.Text(prompt))
+func (r *ChatResponseImpl) GetMultipleChoiceQuestion(id string, prompt string) ([]*chat.MultipleQuiz, error) {
+	return generateQuizzes[chat.MultipleQuiz](r, multipleChoiceConfig, prompt)
+}
 
-	if err != nil {
-		return nil, err
-	}
-	raw := extractText(resp)
-	print(raw)
-	quizzes, err := parseQuizzes[chat.TrueFalse](raw)
-	if err != nil {
-		return nil, err
-	}
-	result := make([]*chat.TrueFalse, len(quizzes))
-	for i := range quizzes {
-		result[i] = &quizzes[i]
-	}
-	return result, nil
+func (r *ChatResponseImpl) GetTrueFalseQuestion(id string, prompt string) ([]*chat.TrueFalse, error) {
+	return generateQuizzes[chat.TrueFalse](r, quizConfig, prompt)
 }
 
 func (r *ChatResponseImpl) GetShortAnswerQuestion(id string, prompt string) ([]*chat.ShortAnswer, error) {
-	r.geminiClient.model.SetMaxOutputTokens(2000)
-	r.geminiClient.model.ResponseMIMEType = "application/json"
-	resp, err := r.geminiClient.model.GenerateContent(r.geminiClient.ctx, genai.Text(prompt))
-	if err != nil {
-		return nil, err
-	}
-	raw := extractText(resp)
-	print(raw)
-	quizzes, err := parseQuizzes[chat.ShortAnswer](raw)
+	return generateQuizzes[chat.ShortAnswer](r, quizConfig, prompt)
+}
+
+func (r *ChatResponseImpl) GradeShortAnswer(id string, prompt string) (*chat.ShortAnswerGrade, error) {
+	raw, err := r.generate(gradeConfig, prompt)
 	if err != nil {
 		return nil, err
 	}
-	result := make([]*chat.ShortAnswer, len(quizzes))
-	for i := range quizzes {
-		result[i] = &quizzes[i]
+
+	var grade chat.ShortAnswerGrade
+	body := cleanModelJSON(raw)
+	if err := json.Unmarshal([]byte(body), &grade); err != nil {
+		if err := json.Unmarshal([]byte(repairJSON(body)), &grade); err != nil {
+			return nil, fmt.Errorf("%w: %v", chat.ErrAIMalformedResponse, err)
+		}
 	}
-	return result, nil
+	return &grade, nil
 }
 
 func (r *ChatResponseImpl) GetChatResponses(chatID int, prompt string) (*chat.ChatResponse, error) {
-	r.geminiClient.model.SetMaxOutputTokens(1000)
-	r.geminiClient.model.ResponseMIMEType = "text/plain"
-	resp, err := r.geminiClient.model.GenerateContent(r.geminiClient.ctx, genai.Text(prompt))
+	message, err := r.generate(chatConfig, prompt)
 	if err != nil {
 		return nil, err
 	}
-	return &chat.ChatResponse{ID: chatID, ChatID: chatID, Message: extractText(resp)}, nil
+	return &chat.ChatResponse{ID: chatID, ChatID: chatID, Message: message}, nil
 }
 
 // GetChatResponseStream returns a channel that streams response chunks
 func (r *ChatResponseImpl) GetChatResponseStream(ctx context.Context, prompt string) (<-chan string, error) {
-	r.geminiClient.model.SetMaxOutputTokens(1000)
-	r.geminiClient.model.ResponseMIMEType = "text/plain"
-
-	iter := r.geminiClient.model.GenerateContentStream(ctx, genai.Text(prompt))
+	iter := r.geminiClient.WithConfig(chatConfig).GenerateContentStream(ctx, genai.Text(prompt))
 	stream := make(chan string)
 
 	go func() {
@@ -0,0 +1,154 @@
+package externalapis
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+)
+
+const googleBooksURL = "https://www.googleapis.com/books/v1"
+
+// GoogleBooksProvider is a catalog.ExternalProvider backed by the Google
+// Books volumes API. The API key is optional but raises the quota.
+type GoogleBooksProvider struct {
+	apiKey string
+	opts   ProviderOptions
+	client *http.Client
+}
+
+var _ catalog.ExternalProvider = (*GoogleBooksProvider)(nil)
+
+func NewGoogleBooksProvider(apiKey string) *GoogleBooksProvider {
+	return NewGoogleBooksProviderWithOptions(apiKey, ProviderOptions{})
+}
+
+func NewGoogleBooksProviderWithOptions(apiKey string, opts ProviderOptions) *GoogleBooksProvider {
+	opts = opts.withDefaults(googleBooksURL)
+	return &GoogleBooksProvider{
+		apiKey: apiKey,
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+	}
+}
+
+type googleVolumes struct {
+	Items []googleVolume `json:"items"`
+}
+
+type googleVolume struct {
+	ID         string `json:"id"`
+	VolumeInfo struct {
+		Title               string   `json:"title"`
+		Authors             []string `json:"authors"`
+		Description         string   `json:"description"`
+		Categories          []string `json:"categories"`
+		PublishedDate       string   `json:"publishedDate"`
+		AverageRating       float32  `json:"averageRating"`
+		PreviewLink         string   `json:"previewLink"`
+		IndustryIdentifiers []struct {
+			Type       string `json:"type"`
+			Identifier string `json:"identifier"`
+		} `json:"industryIdentifiers"`
+		ImageLinks struct {
+			Thumbnail string `json:"thumbnail"`
+		} `json:"imageLinks"`
+	} `json:"volumeInfo"`
+	SaleInfo struct {
+		Saleability string `json:"saleability"`
+		ListPrice   *struct {
+			Amount       float64 `json:"amount"`
+			CurrencyCode string  `json:"currencyCode"`
+		} `json:"listPrice"`
+	} `json:"saleInfo"`
+}
+
+func (p *GoogleBooksProvider) LookupISBN(ctx context.Context, isbn string) (*catalog.ExternalBook, error) {
+	books, err := p.query(ctx, url.Values{"q": {"isbn:" + isbn}}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(books) == 0 {
+		return nil, catalog.ErrExternalNotFound
+	}
+	return books[0], nil
+}
+
+func (p *GoogleBooksProvider) Lookup(ctx context.Context, id string) (*catalog.ExternalBook, error) {
+	var v googleVolume
+	if err := getJSON(ctx, p.client, p.opts, "Google Books", p.url("/volumes/"+url.PathEscape(id), url.Values{}), &v); err != nil {
+		return nil, err
+	}
+	return googleBook(v), nil
+}
+
+func (p *GoogleBooksProvider) Search(ctx context.Context, query string, limit int) ([]*catalog.ExternalBook, error) {
+	return p.query(ctx, url.Values{"q": {query}}, limit)
+}
+
+// Trending returns the newest English books on the subject, which is the
+// closest the volumes API has to a trending list.
+func (p *GoogleBooksProvider) Trending(ctx context.Context, subject string, limit int) ([]*catalog.ExternalBook, error) {
+	return p.query(ctx, url.Values{
+		"q":            {"subject:" + subject},
+		"orderBy":      {"newest"},
+		"langRestrict": {"en"},
+	}, limit)
+}
+
+func (p *GoogleBooksProvider) query(ctx context.Context, params url.Values, limit int) ([]*catalog.ExternalBook, error) {
+	params.Set("maxResults", strconv.Itoa(max(1, min(limit, 40))))
+	var volumes googleVolumes
+	if err := getJSON(ctx, p.client, p.opts, "Google Books", p.url("/volumes", params), &volumes); err != nil {
+		return nil, err
+	}
+
+	books := make([]*catalog.ExternalBook, 0, len(volumes.Items))
+	for _, v := range volumes.Items {
+		books = append(books, googleBook(v))
+	}
+	return books, nil
+}
+
+func (p *GoogleBooksProvider) url(path string, params url.Values) string {
+	if p.apiKey != "" {
+		params.Set("key", p.apiKey)
+	}
+	u := p.opts.BaseURL + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	return u
+}
+
+func googleBook(v googleVolume) *catalog.ExternalBook {
+	info := v.VolumeInfo
+	b := &catalog.ExternalBook{
+		Source:        catalog.SourceGoogleBooks,
+		ID:            v.ID,
+		Title:         info.Title,
+		Authors:       info.Authors,
+		Description:   info.Description,
+		Categories:    info.Categories,
+		PublishedDate: info.PublishedDate,
+		CoverUrl:      strings.Replace(info.ImageLinks.Thumbnail, "http://", "https://", 1),
+		PreviewURL:    info.PreviewLink,
+		Rating:        info.AverageRating,
+	}
+	for _, id := range info.IndustryIdentifiers {
+		switch id.Type {
+		case "ISBN_10":
+			b.ISBN10 = id.Identifier
+		case "ISBN_13":
+			b.ISBN13 = id.Identifier
+		}
+	}
+	// Only books Google sells have a list price
+	if lp := v.SaleInfo.ListPrice; lp != nil && v.SaleInfo.Saleability == "FOR_SALE" {
+		b.Price = &catalog.Price{Amount: lp.Amount, Currency: lp.CurrencyCode}
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+package externalapis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+)
+
+// ProviderOptions tunes the HTTP behaviour of the external book providers.
+// Zero fields take the defaults.
+type ProviderOptions struct {
+	// BaseURL overrides the provider's API root, e.g. to point it at a fake
+	// server.
+	BaseURL string
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// Retries is how many times a failed request is retried; a negative
+	// value disables retries. Only network errors, 429 and 5xx responses
+	// are retried.
+	Retries int
+	// RetryBackoff is the wait before the first retry; it doubles after
+	// each attempt.
+	RetryBackoff time.Duration
+}
+
+const (
+	defaultProviderTimeout = 10 * time.Second
+	defaultProviderRetries = 2
+	defaultRetryBackoff    = 300 * time.Millisecond
+)
+
+func (o ProviderOptions) withDefaults(baseURL string) ProviderOptions {
+	if o.BaseURL == "" {
+		o.BaseURL = baseURL
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = defaultProviderTimeout
+	}
+	if o.Retries < 0 {
+		o.Retries = 0
+	} else if o.Retries == 0 {
+		o.Retries = defaultProviderRetries
+	}
+	if o.RetryBackoff <= 0 {
+		o.RetryBackoff = defaultRetryBackoff
+	}
+	return o
+}
+
+// getJSON fetches url and decodes the JSON body into dst, retrying
+// transient failures. A 404 is reported as catalog.ErrExternalNotFound and
+// exhausted retries as catalog.ErrExternalUnavailable.
+func getJSON(ctx context.Context, client *http.Client, opts ProviderOptions, provider, url string, dst any) error {
+	backoff := opts.RetryBackoff
+	var lastErr error
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		retry, err := getJSONOnce(ctx, client, url, dst)
+		if err == nil || !retry {
+			return err
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("%w: %s: %v", catalog.ErrExternalUnavailable, provider, lastErr)
+}
+
+// getJSONOnce makes one attempt and reports whether a failure is worth
+// retrying.
+func getJSONOnce(ctx context.Context, client *http.Client, url string, dst any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return false, catalog.ErrExternalNotFound
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return false, nil
+}
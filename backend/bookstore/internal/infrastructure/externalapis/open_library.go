@@ -0,0 +1,142 @@
+package externalapis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+)
+
+const (
+	openLibraryURL       = "https://openlibrary.org"
+	openLibraryCoversURL = "https://covers.openlibrary.org"
+	// openLibraryFields limits search responses to what we map.
+	openLibraryFields = "key,title,author_name,isbn,cover_i,first_publish_year,subject,ratings_average"
+)
+
+// OpenLibraryProvider is a catalog.ExternalProvider backed by the Open
+// Library search API. Books are identified by their work ID, such as
+// "OL45804W". Open Library does not sell books, so prices are always
+// unknown.
+type OpenLibraryProvider struct {
+	opts   ProviderOptions
+	client *http.Client
+}
+
+var _ catalog.ExternalProvider = (*OpenLibraryProvider)(nil)
+
+func NewOpenLibraryProvider() *OpenLibraryProvider {
+	return NewOpenLibraryProviderWithOptions(ProviderOptions{})
+}
+
+func NewOpenLibraryProviderWithOptions(opts ProviderOptions) *OpenLibraryProvider {
+	opts = opts.withDefaults(openLibraryURL)
+	return &OpenLibraryProvider{
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+	}
+}
+
+type openLibrarySearch struct {
+	Docs []openLibraryDoc `json:"docs"`
+}
+
+type openLibraryDoc struct {
+	Key              string   `json:"key"`
+	Title            string   `json:"title"`
+	AuthorName       []string `json:"author_name"`
+	ISBN             []string `json:"isbn"`
+	CoverID          int      `json:"cover_i"`
+	FirstPublishYear int      `json:"first_publish_year"`
+	Subject          []string `json:"subject"`
+	RatingsAverage   float32  `json:"ratings_average"`
+}
+
+func (p *OpenLibraryProvider) LookupISBN(ctx context.Context, isbn string) (*catalog.ExternalBook, error) {
+	return p.first(ctx, url.Values{"isbn": {isbn}})
+}
+
+func (p *OpenLibraryProvider) Lookup(ctx context.Context, id string) (*catalog.ExternalBook, error) {
+	id = strings.TrimPrefix(id, "/works/")
+	if id == "" || strings.ContainsAny(id, " /:") {
+		return nil, catalog.ErrExternalNotFound
+	}
+	return p.first(ctx, url.Values{"q": {"key:/works/" + id}})
+}
+
+func (p *OpenLibraryProvider) Search(ctx context.Context, query string, limit int) ([]*catalog.ExternalBook, error) {
+	return p.search(ctx, url.Values{"q": {query}}, limit)
+}
+
+// Trending returns the newest books on the subject.
+func (p *OpenLibraryProvider) Trending(ctx context.Context, subject string, limit int) ([]*catalog.ExternalBook, error) {
+	return p.search(ctx, url.Values{
+		"q":    {fmt.Sprintf("subject:%q", subject)},
+		"sort": {"new"},
+	}, limit)
+}
+
+func (p *OpenLibraryProvider) first(ctx context.Context, params url.Values) (*catalog.ExternalBook, error) {
+	books, err := p.search(ctx, params, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(books) == 0 {
+		return nil, catalog.ErrExternalNotFound
+	}
+	return books[0], nil
+}
+
+func (p *OpenLibraryProvider) search(ctx context.Context, params url.Values, limit int) ([]*catalog.ExternalBook, error) {
+	params.Set("limit", strconv.Itoa(max(1, min(limit, 100))))
+	params.Set("fields", openLibraryFields)
+	var result openLibrarySearch
+	if err := getJSON(ctx, p.client, p.opts, "Open Library", p.opts.BaseURL+"/search.json?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	books := make([]*catalog.ExternalBook, 0, len(result.Docs))
+	for _, d := range result.Docs {
+		books = append(books, openLibraryBook(d, params.Get("isbn")))
+	}
+	return books, nil
+}
+
+// openLibraryBook maps a search result. Works list every edition's ISBN, so
+// the looked-up ISBN is preferred when there is one.
+func openLibraryBook(d openLibraryDoc, isbn string) *catalog.ExternalBook {
+	id := strings.TrimPrefix(d.Key, "/works/")
+	b := &catalog.ExternalBook{
+		Source:     catalog.SourceOpenLibrary,
+		ID:         id,
+		Title:      d.Title,
+		Authors:    d.AuthorName,
+		Categories: d.Subject[:min(len(d.Subject), 5)],
+		PreviewURL: openLibraryURL + "/works/" + id,
+		Rating:     d.RatingsAverage,
+	}
+	if d.FirstPublishYear > 0 {
+		b.PublishedDate = strconv.Itoa(d.FirstPublishYear)
+	}
+	if d.CoverID > 0 {
+		b.CoverUrl = fmt.Sprintf("%s/b/id/%d-M.jpg", openLibraryCoversURL, d.CoverID)
+	}
+
+	isbns := d.ISBN
+	if isbn != "" {
+		isbns = append([]string{isbn}, isbns...)
+	}
+	for _, n := range isbns {
+		switch {
+		case len(n) == 13 && b.ISBN13 == "":
+			b.ISBN13 = n
+		case len(n) == 10 && b.ISBN10 == "":
+			b.ISBN10 = n
+		}
+	}
+	return b
+}
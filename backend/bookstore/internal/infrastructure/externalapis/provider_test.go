@@ -0,0 +1,231 @@
+package externalapis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/externalapis/externalapistest"
+)
+
+func dune() *catalog.ExternalBook {
+	return &catalog.ExternalBook{
+		ID:            "OL893415W",
+		Title:         "Dune",
+		Authors:       []string{"Frank Herbert"},
+		ISBN10:        "0441172717",
+		ISBN13:        "9780441172719",
+		Categories:    []string{"Science fiction"},
+		PublishedDate: "1965-08-01",
+		Rating:        4.5,
+		CoverUrl:      "http://books.google.com/dune.jpg",
+		PreviewURL:    "https://books.google.com/dune",
+		Price:         &catalog.Price{Amount: 9.99, Currency: "USD"},
+	}
+}
+
+func emma() *catalog.ExternalBook {
+	return &catalog.ExternalBook{
+		ID:            "OL66554W",
+		Title:         "Emma",
+		Authors:       []string{"Jane Austen"},
+		ISBN13:        "9780141439587",
+		Categories:    []string{"Classics"},
+		PublishedDate: "1815",
+	}
+}
+
+// fastOptions points a provider at s with short retry waits.
+func fastOptions(baseURL string) ProviderOptions {
+	return ProviderOptions{BaseURL: baseURL, Timeout: time.Second, RetryBackoff: time.Millisecond}
+}
+
+func TestGoogleBooksParsesVolumes(t *testing.T) {
+	s := externalapistest.NewServer(dune(), emma())
+	defer s.Close()
+	p := NewGoogleBooksProviderWithOptions("", fastOptions(s.GoogleBooksURL()))
+	ctx := context.Background()
+
+	b, err := p.LookupISBN(ctx, "9780441172719")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dune()
+	if b.Source != catalog.SourceGoogleBooks || b.ID != want.ID || b.Title != want.Title ||
+		!slices.Equal(b.Authors, want.Authors) || b.ISBN10 != want.ISBN10 || b.ISBN13 != want.ISBN13 ||
+		b.PublishedDate != want.PublishedDate || b.Rating != want.Rating || b.PreviewURL != want.PreviewURL {
+		t.Errorf("got %+v, want %+v", b, want)
+	}
+	if b.CoverUrl != "https://books.google.com/dune.jpg" {
+		t.Errorf("cover %q is not served over https", b.CoverUrl)
+	}
+	if b.Price == nil || *b.Price != *want.Price {
+		t.Errorf("price = %v, want %v", b.Price, want.Price)
+	}
+
+	free, err := p.Lookup(ctx, "OL66554W")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if free.Title != "Emma" || free.Price != nil {
+		t.Errorf("got %q priced %v, want Emma with no price", free.Title, free.Price)
+	}
+
+	results, err := p.Trending(ctx, "Classics", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != "OL66554W" {
+		t.Errorf("trending classics = %v", results)
+	}
+
+	if _, err := p.Lookup(ctx, "missing"); !errors.Is(err, catalog.ErrExternalNotFound) {
+		t.Errorf("unknown volume: got %v, want ErrExternalNotFound", err)
+	}
+	if _, err := p.LookupISBN(ctx, "0000000000"); !errors.Is(err, catalog.ErrExternalNotFound) {
+		t.Errorf("unknown ISBN: got %v, want ErrExternalNotFound", err)
+	}
+}
+
+func TestOpenLibraryParsesSearchResults(t *testing.T) {
+	s := externalapistest.NewServer(dune(), emma())
+	defer s.Close()
+	p := NewOpenLibraryProviderWithOptions(fastOptions(s.OpenLibraryURL()))
+	ctx := context.Background()
+
+	b, err := p.LookupISBN(ctx, "0441172717")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Source != catalog.SourceOpenLibrary || b.ID != "OL893415W" || b.Title != "Dune" ||
+		!slices.Equal(b.Authors, []string{"Frank Herbert"}) || b.PublishedDate != "1965" || b.Rating != 4.5 {
+		t.Errorf("got %+v", b)
+	}
+	// the looked-up ISBN wins over the first edition listed
+	if b.ISBN10 != "0441172717" || b.ISBN13 != "9780441172719" {
+		t.Errorf("isbns = %q, %q", b.ISBN10, b.ISBN13)
+	}
+	if b.Price != nil {
+		t.Errorf("Open Library book has price %v", b.Price)
+	}
+	if b.PreviewURL != "https://openlibrary.org/works/OL893415W" {
+		t.Errorf("preview = %q", b.PreviewURL)
+	}
+
+	found, err := p.Lookup(ctx, "/works/OL66554W")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Title != "Emma" {
+		t.Errorf("lookup by work = %q, want Emma", found.Title)
+	}
+
+	results, err := p.Search(ctx, "austen", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Title != "Emma" {
+		t.Errorf("search austen = %v", results)
+	}
+
+	if _, err := p.Lookup(ctx, "OL1W/../x"); !errors.Is(err, catalog.ErrExternalNotFound) {
+		t.Errorf("malformed work id: got %v, want ErrExternalNotFound", err)
+	}
+	if _, err := p.LookupISBN(ctx, "0000000000"); !errors.Is(err, catalog.ErrExternalNotFound) {
+		t.Errorf("unknown ISBN: got %v, want ErrExternalNotFound", err)
+	}
+}
+
+func TestProvidersRetryTransientFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		status   int
+		retries  int
+		requests int
+		// unavailable is whether the lookup fails with
+		// ErrExternalUnavailable; ok whether it succeeds.
+		ok, unavailable bool
+	}{
+		{"503 then success", 2, http.StatusServiceUnavailable, 2, 3, true, false},
+		{"429 then success", 2, http.StatusTooManyRequests, 2, 3, true, false},
+		{"500 until retries run out", 5, http.StatusInternalServerError, 2, 3, false, true},
+		{"retries disabled", 1, http.StatusServiceUnavailable, -1, 1, false, true},
+		{"400 is not retried", 1, http.StatusBadRequest, 2, 1, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := externalapistest.NewServer(dune())
+			defer s.Close()
+			opts := fastOptions(s.GoogleBooksURL())
+			opts.Retries = tt.retries
+			p := NewGoogleBooksProviderWithOptions("", opts)
+
+			s.FailNextWith(tt.failures, tt.status)
+			_, err := p.LookupISBN(context.Background(), "9780441172719")
+			if (err == nil) != tt.ok || errors.Is(err, catalog.ErrExternalUnavailable) != tt.unavailable {
+				t.Errorf("got %v, want ok %v, unavailable %v", err, tt.ok, tt.unavailable)
+			}
+			if got := s.Requests(); got != tt.requests {
+				t.Errorf("server got %d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestProvidersBackOffBetweenRetries(t *testing.T) {
+	s := externalapistest.NewServer(emma())
+	defer s.Close()
+	opts := fastOptions(s.OpenLibraryURL())
+	opts.RetryBackoff = 20 * time.Millisecond
+	p := NewOpenLibraryProviderWithOptions(opts)
+
+	s.FailNext(2)
+	start := time.Now()
+	if _, err := p.Lookup(context.Background(), "OL66554W"); err != nil {
+		t.Fatal(err)
+	}
+	// 20ms before the first retry, 40ms before the second
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("two retries took %s, want at least 60ms of backoff", elapsed)
+	}
+}
+
+func TestProvidersTimeOut(t *testing.T) {
+	s := externalapistest.NewServer(dune())
+	defer s.Close()
+	s.Delay(2 * time.Second)
+
+	opts := fastOptions(s.GoogleBooksURL())
+	opts.Timeout, opts.Retries = 20*time.Millisecond, 1
+	p := NewGoogleBooksProviderWithOptions("", opts)
+
+	start := time.Now()
+	_, err := p.LookupISBN(context.Background(), "9780441172719")
+	if !errors.Is(err, catalog.ErrExternalUnavailable) {
+		t.Errorf("slow provider: got %v, want ErrExternalUnavailable", err)
+	}
+	if s.Requests() != 2 {
+		t.Errorf("server got %d requests, want 2: a timed-out attempt is retried", s.Requests())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("timed-out lookup took %s", elapsed)
+	}
+
+	// a cancelled caller is not retried
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	opts.Timeout, opts.Retries = time.Minute, 3
+	p = NewGoogleBooksProviderWithOptions("", opts)
+	start = time.Now()
+	if _, err := p.LookupISBN(ctx, "9780441172719"); err == nil {
+		t.Error("cancelled lookup succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancelled lookup took %s", elapsed)
+	}
+}
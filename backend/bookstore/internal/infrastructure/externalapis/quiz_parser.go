@@ -0,0 +1,180 @@
+package externalapis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/chat"
+)
+
+// validatable is implemented by every quiz item type in the chat domain.
+type validatable[T any] interface {
+	*T
+	Validate() error
+}
+
+// parseQuizzes decodes model output into quiz items. It tolerates code
+// fences, surrounding prose, trailing commas and truncated output, and keeps
+// every item that decodes and validates. It fails only when no item is usable.
+func parseQuizzes[T any, PT validatable[T]](raw string) ([]*T, error) {
+	body := cleanModelJSON(raw)
+	if body == "" {
+		return nil, fmt.Errorf("%w: empty response", chat.ErrAIMalformedResponse)
+	}
+
+	items, err := quizItems(body)
+	if err != nil {
+		items, err = quizItems(repairJSON(body))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", chat.ErrAIMalformedResponse, err)
+		}
+	}
+
+	result := make([]*T, 0, len(items))
+	var dropped int
+	for i, item := range items {
+		q := new(T)
+		if err := json.Unmarshal(item, q); err != nil {
+			dropped++
+			log.Printf("quiz item %d dropped: %v", i+1, err)
+			continue
+		}
+		if err := PT(q).Validate(); err != nil {
+			dropped++
+			log.Printf("quiz item %d dropped: %v", i+1, err)
+			continue
+		}
+		result = append(result, q)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: none of the %d quiz items were valid", chat.ErrAIMalformedResponse, len(items))
+	}
+	if dropped > 0 {
+		log.Printf("accepted %d of %d quiz items", len(result), len(items))
+	}
+	return result, nil
+}
+
+// quizItems extracts the raw quiz items from either the {"quizzes": [...]}
+// envelope the prompts ask for or a bare JSON array.
+func quizItems(body string) ([]json.RawMessage, error) {
+	if strings.HasPrefix(body, "[") {
+		var items []json.RawMessage
+		if err := json.Unmarshal([]byte(body), &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+
+	var envelope struct {
+		Quizzes   []json.RawMessage `json:"quizzes"`
+		Questions []json.RawMessage `json:"questions"`
+	}
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return nil, err
+	}
+	if len(envelope.Quizzes) > 0 {
+		return envelope.Quizzes, nil
+	}
+	if len(envelope.Questions) > 0 {
+		return envelope.Questions, nil
+	}
+	return nil, fmt.Errorf("no quizzes in response")
+}
+
+// cleanModelJSON strips markdown fences and any prose around the outermost
+// JSON value.
+func cleanModelJSON(raw string) string {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+	raw = strings.TrimSpace(raw)
+
+	start := strings.IndexAny(raw, "{[")
+	if start < 0 {
+		return ""
+	}
+	raw = raw[start:]
+	if end := strings.LastIndexAny(raw, "}]"); end >= 0 && json.Valid([]byte(raw[:end+1])) {
+		return raw[:end+1]
+	}
+	return raw
+}
+
+// repairJSON fixes the two ways model output usually breaks: trailing commas
+// and truncation. A truncated document is cut back to the last complete
+// array element and the still-open brackets are closed.
+func repairJSON(s string) string {
+	var (
+		out      bytes.Buffer
+		stack    []byte
+		inString bool
+		escaped  bool
+		cutLen   = -1
+		cutStack []byte
+	)
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if inString {
+			out.WriteByte(ch)
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch ch {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, ch)
+		case '}', ']':
+			trimTrailingComma(&out)
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) > 0 && stack[len(stack)-1] == '[' {
+				out.WriteByte(ch)
+				cutLen = out.Len()
+				cutStack = append(cutStack[:0], stack...)
+				continue
+			}
+		}
+		out.WriteByte(ch)
+	}
+
+	if len(stack) == 0 && !inString {
+		return out.String()
+	}
+	if cutLen < 0 {
+		return out.String()
+	}
+
+	repaired := out.Bytes()[:cutLen]
+	for i := len(cutStack) - 1; i >= 0; i-- {
+		if cutStack[i] == '[' {
+			repaired = append(repaired, ']')
+		} else {
+			repaired = append(repaired, '}')
+		}
+	}
+	return string(repaired)
+}
+
+func trimTrailingComma(out *bytes.Buffer) {
+	b := bytes.TrimRight(out.Bytes(), " \t\r\n")
+	if len(b) > 0 && b[len(b)-1] == ',' {
+		out.Truncate(len(b) - 1)
+	}
+}
@@ -0,0 +1,155 @@
+package externalapis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/chat"
+)
+
+func TestCleanModelJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"bare", `{"quizzes":[]}`, `{"quizzes":[]}`},
+		{"json fence", "```json\n{\"quizzes\":[]}\n```", `{"quizzes":[]}`},
+		{"plain fence", "```\n[1, 2]\n```", `[1, 2]`},
+		{"surrounding prose", `Here is your quiz: {"quizzes":[]} Good luck!`, `{"quizzes":[]}`},
+		{"truncated", "```json\n{\"quizzes\":[{\"id\":1", `{"quizzes":[{"id":1`},
+		{"no json", "Sorry, I can't help with that.", ""},
+		{"empty", "  ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanModelJSON(tt.raw); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"valid", `{"quizzes":[{"id":1}]}`, `{"quizzes":[{"id":1}]}`},
+		{"trailing comma in array", `[1, 2, ]`, `[1, 2]`},
+		{"trailing comma in object", `{"id":1,}`, `{"id":1}`},
+		{"trailing commas nested", "{\"quizzes\":[{\"id\":1,},\n]}", `{"quizzes":[{"id":1}]}`},
+		{"comma in string", `{"q":"a,}"}`, `{"q":"a,}"}`},
+		{"escaped quote in string", `{"q":"say \",]\"",}`, `{"q":"say \",]\""}`},
+		{"truncated object in array", `{"quizzes":[{"id":1},{"id":2,"q":"Wh`, `{"quizzes":[{"id":1}]}`},
+		{"truncated between items", `{"quizzes":[{"id":1},`, `{"quizzes":[{"id":1}]}`},
+		{"truncated bare array", `[{"id":1},{"id":2},{"id"`, `[{"id":1},{"id":2}]`},
+		{"truncated nested array", `{"quizzes":[{"options":["a","b"]},{"options":["c"`, `{"quizzes":[{"options":["a","b"]}]}`},
+		{"truncated before any item", `{"quizzes":[{"id":1`, `{"quizzes":[{"id":1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repairJSON(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMultipleChoice(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []int // correct index of each accepted item
+	}{
+		{"correct_answer_index", `{"quizzes":[{"question":"Q?","options":["a","b"],"correct_answer_index":1}]}`, []int{1}},
+		{"correct_index", `{"quizzes":[{"question":"Q?","options":["a","b"],"correct_index":0}]}`, []int{0}},
+		{"correct_answer_index wins", `[{"question":"Q?","options":["a","b","c"],"correct_index":0,"correct_answer_index":2}]`, []int{2}},
+		{"questions envelope", `{"questions":[{"question":"Q?","options":["a","b"],"correct_index":1}]}`, []int{1}},
+		{"fenced with trailing commas", "```json\n{\"quizzes\":[{\"question\":\"Q?\",\"options\":[\"a\",\"b\",],\"correct_index\":1,},]}\n```", []int{1}},
+		{"truncated", `{"quizzes":[{"question":"Q1?","options":["a","b"],"correct_index":0},{"question":"Q2?","options":["a"`, []int{0}},
+		{"invalid items dropped", `{"quizzes":[
+			{"question":"No index?","options":["a","b"]},
+			{"question":"Out of range?","options":["a","b"],"correct_index":2},
+			{"question":"One option?","options":["a"],"correct_index":0},
+			{"question":"","options":["a","b"],"correct_index":0},
+			{"question":"Blank option?","options":["a"," "],"correct_index":0},
+			{"question":"Wrong type?","options":"a, b","correct_index":0},
+			{"question":"Good?","options":["a","b"],"correct_index":1}
+		]}`, []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQuizzes[chat.MultipleQuiz](tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d items, want %d", len(got), len(tt.want))
+			}
+			for i, q := range got {
+				if q.CorrectIndex != tt.want[i] {
+					t.Errorf("item %d: correct index %d, want %d", i, q.CorrectIndex, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseTrueFalse(t *testing.T) {
+	raw := `{"quizzes":[
+		{"question":"A?","answer":true},
+		{"question":"B?","answer":false},
+		{"question":"C?","answer":"True"},
+		{"question":"D?","answer":" FALSE "},
+		{"question":"E?","answer":"yes"},
+		{"question":"F?","answer":1},
+		{"question":"G?"}
+	]}`
+	got, err := parseQuizzes[chat.TrueFalse](raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"true", "false", "true", "false"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d items, want %d", len(got), len(want))
+	}
+	for i, q := range got {
+		if q.Answer != want[i] {
+			t.Errorf("%s: answer %q, want %q", q.Question, q.Answer, want[i])
+		}
+	}
+}
+
+func TestParseShortAnswer(t *testing.T) {
+	raw := `[
+		{"question":"Who narrates?","answer":"Nelly Dean","explanation":"She tells Lockwood."},
+		{"question":"Blank answer?","answer":"  "},
+		{"answer":"No question"}
+	]`
+	got, err := parseQuizzes[chat.ShortAnswer](raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].CorrectAnswer != "Nelly Dean" || got[0].Explanation != "She tells Lockwood." {
+		t.Errorf("got %+v, want only the complete question", got)
+	}
+}
+
+func TestParseQuizzesFailsWithoutAUsableItem(t *testing.T) {
+	for name, raw := range map[string]string{
+		"empty":             "",
+		"prose":             "I cannot generate a quiz for that book.",
+		"no quizzes":        `{"book_title":"Dune"}`,
+		"empty list":        `{"quizzes":[]}`,
+		"all invalid":       `{"quizzes":[{"question":"Q?","options":["a"],"correct_index":0}]}`,
+		"truncated in item": `{"quizzes":[{"question":"Q?","options":["a","b"],"correct_in`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseQuizzes[chat.MultipleQuiz](raw); !errors.Is(err, chat.ErrAIMalformedResponse) {
+				t.Errorf("got %v, want ErrAIMalformedResponse", err)
+			}
+		})
+	}
+}
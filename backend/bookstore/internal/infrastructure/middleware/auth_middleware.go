@@ -1,9 +1,9 @@
 package middleware
 
 import (
-	"context"
 	"net/http"
 
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
 	"github.com/bereke1t2/bookstore/internal/infrastructure/security"
 	"github.com/gin-gonic/gin"
 )
@@ -16,18 +16,26 @@ func AuthMiddleware(c *gin.Context) {
 	}
 
 	claims, err := security.ValidateJWT(token)
-	if err != nil {
+	if err != nil || claims.UserID == 0 {
 		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
 		return
 	}
 
-	// 1. Set the variable in Gin context (accessible in handlers via c.Get("userID"))
-	c.Set("userID", claims.UserID)
-
-	// 2. Update the standard request context (accessible in UseCases via ctx.Value("userID"))
-	// This ensures your Clean Architecture layers receiving c.Request.Context() still work.
-	ctx := context.WithValue(c.Request.Context(), "userID", claims.UserID)
-	c.Request = c.Request.WithContext(ctx)
+	// Handlers and usecases read the caller with identity.FromContext(c.Request.Context()).
+	c.Request = c.Request.WithContext(identity.WithPrincipal(c.Request.Context(), principalFromClaims(claims)))
 
 	c.Next()
-}
\ No newline at end of file
+}
+
+func principalFromClaims(claims *security.JWTClaims) identity.Principal {
+	// Tokens issued before roles were added carry none; treat them as plain users.
+	roles := claims.Roles
+	if len(roles) == 0 {
+		roles = []string{identity.RoleUser}
+	}
+	return identity.Principal{
+		UserID:  claims.UserID,
+		Roles:   roles,
+		TokenID: claims.Id,
+	}
+}
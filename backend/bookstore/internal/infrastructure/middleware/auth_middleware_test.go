@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/security"
+	"github.com/gin-gonic/gin"
+)
+
+// whoami answers with the principal's user ID, or 0 if there is none.
+func whoami(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/me", AuthMiddleware, func(c *gin.Context) {
+		p, _ := identity.FromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"user_id": p.UserID, "admin": p.IsAdmin()})
+	})
+	return r
+}
+
+func TestAuthMiddlewareIgnoresUserIDHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("X-User-ID", "1")
+	w := httptest.NewRecorder()
+	whoami(t).ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("got %d, want 401 without a token", w.Code)
+	}
+}
+
+func TestAuthMiddlewareRejectsForgedToken(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer not.a.token")
+	w := httptest.NewRecorder()
+	whoami(t).ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("got %d, want 403", w.Code)
+	}
+}
+
+func TestAuthMiddlewareStoresPrincipal(t *testing.T) {
+	token, err := security.GenerateJWT(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-User-ID", "1")
+	w := httptest.NewRecorder()
+	whoami(t).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"admin":false,"user_id":42}` {
+		t.Errorf("got %d %s, want user 42 from the token", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/security"
+	"github.com/gin-gonic/gin"
+)
+
+// BasicAuthenticator checks an email and password and returns the user they
+// belong to.
+type BasicAuthenticator func(email, password string) (identity.Principal, error)
+
+// OPDSRealm is the HTTP Basic realm reader apps show when asking to log in.
+const OPDSRealm = "btluBook"
+
+// OPDSAuthMiddleware authenticates reader apps, which often cannot log in
+// through the API. It accepts HTTP Basic credentials checked with
+// authenticate, an access token in the Authorization header, or an access
+// token in the "token" query parameter. Failures always answer 401 with a
+// Basic challenge so apps prompt for credentials.
+func OPDSAuthMiddleware(authenticate BasicAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := opdsPrincipal(c, authenticate)
+		if !ok {
+			c.Header("WWW-Authenticate", `Basic realm="`+OPDSRealm+`", charset="UTF-8"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		c.Request = c.Request.WithContext(identity.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+func opdsPrincipal(c *gin.Context, authenticate BasicAuthenticator) (identity.Principal, bool) {
+	if email, password, ok := c.Request.BasicAuth(); ok {
+		p, err := authenticate(email, password)
+		return p, err == nil && p.UserID != 0
+	}
+
+	token := c.GetHeader("Authorization")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if strings.TrimSpace(token) == "" {
+		return identity.Principal{}, false
+	}
+	claims, err := security.ValidateJWT(token)
+	if err != nil || claims.UserID == 0 {
+		return identity.Principal{}, false
+	}
+	return principalFromClaims(claims), true
+}
@@ -0,0 +1,161 @@
+package opdsfeed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/opds"
+)
+
+type atomFeed struct {
+	XMLName    xml.Name    `xml:"feed"`
+	Xmlns      string      `xml:"xmlns,attr"`
+	XmlnsDC    string      `xml:"xmlns:dc,attr"`
+	XmlnsOPDS  string      `xml:"xmlns:opds,attr"`
+	XmlnsOS    string      `xml:"xmlns:opensearch,attr"`
+	ID         string      `xml:"id"`
+	Title      string      `xml:"title"`
+	Updated    string      `xml:"updated"`
+	Author     atomAuthor  `xml:"author"`
+	Links      []atomLink  `xml:"link"`
+	PerPage    int         `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex int         `xml:"opensearch:startIndex,omitempty"`
+	Entries    []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title       string         `xml:"title"`
+	ID          string         `xml:"id"`
+	Updated     string         `xml:"updated"`
+	Authors     []atomAuthor   `xml:"author"`
+	Identifiers []string       `xml:"dc:identifier"`
+	Categories  []atomCategory `xml:"category"`
+	Content     *atomContent   `xml:"content"`
+	Links       []atomLink     `xml:"link"`
+}
+
+// AtomType returns the media type of f as an OPDS 1.2 feed.
+func AtomType(f *opds.Feed) string {
+	if f.Kind == opds.KindNavigation {
+		return TypeAtomNavigation
+	}
+	return TypeAtomAcquisition
+}
+
+// EncodeAtom writes f as an OPDS 1.2 Atom feed.
+func EncodeAtom(w io.Writer, f *opds.Feed, l Links) error {
+	feed := atomFeed{
+		Xmlns:     "http://www.w3.org/2005/Atom",
+		XmlnsDC:   "http://purl.org/dc/terms/",
+		XmlnsOPDS: "http://opds-spec.org/2010/catalog",
+		XmlnsOS:   "http://a9.com/-/spec/opensearch/1.1/",
+		ID:        feedID(f),
+		Title:     f.Title,
+		Updated:   atomTime(f.Updated),
+		Author:    atomAuthor{Name: "btluBook"},
+		Links: []atomLink{
+			{Rel: "self", Href: l.Feed(PathV1, f.Path, f.Page, f.Query), Type: AtomType(f)},
+			{Rel: "start", Href: l.Feed(PathV1, opds.PathRoot, 0, ""), Type: TypeAtomNavigation},
+			{Rel: "search", Href: l.OpenSearch(), Type: TypeOpenSearch},
+		},
+	}
+	if f.Path != opds.PathRoot {
+		feed.Links = append(feed.Links, atomLink{Rel: "up", Href: l.Feed(PathV1, f.Up, 0, ""), Type: TypeAtomNavigation})
+	}
+	if f.Kind == opds.KindAcquisition {
+		feed.PerPage = f.PageSize
+		feed.StartIndex = (f.Page-1)*f.PageSize + 1
+		if f.Page > 1 {
+			feed.Links = append(feed.Links, atomLink{Rel: "previous", Href: l.Feed(PathV1, f.Path, f.Page-1, f.Query), Type: TypeAtomAcquisition})
+		}
+		if f.HasNext {
+			feed.Links = append(feed.Links, atomLink{Rel: "next", Href: l.Feed(PathV1, f.Path, f.Page+1, f.Query), Type: TypeAtomAcquisition})
+		}
+	}
+
+	for _, n := range f.Navigation {
+		typ := TypeAtomAcquisition
+		if n.Kind == opds.KindNavigation {
+			typ = TypeAtomNavigation
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   n.Title,
+			ID:      navigationID(n.Path),
+			Updated: feed.Updated,
+			Content: &atomContent{Type: "text", Text: n.Summary},
+			Links:   []atomLink{{Rel: "subsection", Href: l.Feed(PathV1, n.Path, 0, ""), Type: typ}},
+		})
+	}
+	for _, b := range f.Books {
+		feed.Entries = append(feed.Entries, atomBook(b, f.Updated, l))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(feed)
+}
+
+func atomBook(b *book.Book, fallback time.Time, l Links) atomEntry {
+	updated := b.CreatedAt
+	if updated.IsZero() {
+		updated = fallback
+	}
+	e := atomEntry{
+		Title:       b.Title,
+		ID:          bookID(b),
+		Updated:     atomTime(updated),
+		Identifiers: identifiers(b),
+	}
+	if b.Author != "" {
+		e.Authors = []atomAuthor{{Name: b.Author}}
+	}
+	if b.Category != "" {
+		e.Categories = []atomCategory{{Term: b.Category, Label: b.Category}}
+	}
+	if b.RatingCount > 0 {
+		e.Content = &atomContent{Type: "text", Text: fmt.Sprintf("Rated %.1f/5 by %d readers", b.Rating, b.RatingCount)}
+	}
+	if cover := l.Asset(b.CoverUrl); cover != "" {
+		typ := imageType(cover)
+		e.Links = append(e.Links,
+			atomLink{Rel: relImage, Href: cover, Type: typ},
+			atomLink{Rel: relThumbnail, Href: cover, Type: typ})
+	}
+	if typ := acquisitionType(b); typ != "" {
+		e.Links = append(e.Links, atomLink{Rel: relAcquisition, Href: l.Download(b.ID), Type: typ})
+	} else if preview := previewURL(b); preview != "" {
+		e.Links = append(e.Links, atomLink{Rel: "alternate", Href: preview, Type: "text/html", Title: "Preview"})
+	}
+	return e
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
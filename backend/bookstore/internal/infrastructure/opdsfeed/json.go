@@ -0,0 +1,129 @@
+package opdsfeed
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/opds"
+)
+
+type jsonFeed struct {
+	Metadata     jsonFeedMetadata  `json:"metadata"`
+	Links        []jsonLink        `json:"links"`
+	Navigation   []jsonLink        `json:"navigation,omitempty"`
+	Publications []jsonPublication `json:"publications,omitempty"`
+}
+
+type jsonFeedMetadata struct {
+	Title        string `json:"title"`
+	Modified     string `json:"modified"`
+	ItemsPerPage int    `json:"itemsPerPage,omitempty"`
+	CurrentPage  int    `json:"currentPage,omitempty"`
+}
+
+type jsonLink struct {
+	Rel       string `json:"rel,omitempty"`
+	Href      string `json:"href"`
+	Type      string `json:"type,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Templated bool   `json:"templated,omitempty"`
+}
+
+type jsonPublication struct {
+	Metadata jsonPublicationMetadata `json:"metadata"`
+	Links    []jsonLink              `json:"links"`
+	Images   []jsonLink              `json:"images,omitempty"`
+}
+
+type jsonPublicationMetadata struct {
+	Type       string   `json:"@type"`
+	Identifier string   `json:"identifier"`
+	Title      string   `json:"title"`
+	Author     string   `json:"author,omitempty"`
+	Subject    []string `json:"subject,omitempty"`
+	Modified   string   `json:"modified,omitempty"`
+}
+
+// EncodeJSON writes f as an OPDS 2.0 feed.
+func EncodeJSON(w io.Writer, f *opds.Feed, l Links) error {
+	feed := jsonFeed{
+		Metadata: jsonFeedMetadata{Title: f.Title, Modified: f.Updated.UTC().Format(time.RFC3339)},
+		Links: []jsonLink{
+			{Rel: "self", Href: l.Feed(PathV2, f.Path, f.Page, f.Query), Type: TypeOPDSJSON},
+			{Rel: "start", Href: l.Feed(PathV2, opds.PathRoot, 0, ""), Type: TypeOPDSJSON},
+			{Rel: "search", Href: searchTemplate(l), Type: TypeOPDSJSON, Templated: true},
+		},
+	}
+	if f.Path != opds.PathRoot {
+		feed.Links = append(feed.Links, jsonLink{Rel: "up", Href: l.Feed(PathV2, f.Up, 0, ""), Type: TypeOPDSJSON})
+	}
+	if f.Kind == opds.KindAcquisition {
+		feed.Metadata.ItemsPerPage, feed.Metadata.CurrentPage = f.PageSize, f.Page
+		if f.Page > 1 {
+			feed.Links = append(feed.Links, jsonLink{Rel: "previous", Href: l.Feed(PathV2, f.Path, f.Page-1, f.Query), Type: TypeOPDSJSON})
+		}
+		if f.HasNext {
+			feed.Links = append(feed.Links, jsonLink{Rel: "next", Href: l.Feed(PathV2, f.Path, f.Page+1, f.Query), Type: TypeOPDSJSON})
+		}
+		// OPDS 2.0 requires the collection even when it is empty
+		feed.Publications = []jsonPublication{}
+	}
+
+	for _, n := range f.Navigation {
+		feed.Navigation = append(feed.Navigation, jsonLink{
+			Rel:   "subsection",
+			Href:  l.Feed(PathV2, n.Path, 0, ""),
+			Type:  TypeOPDSJSON,
+			Title: n.Title,
+		})
+	}
+	for _, b := range f.Books {
+		feed.Publications = append(feed.Publications, jsonBook(b, l))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(feed)
+}
+
+func jsonBook(b *book.Book, l Links) jsonPublication {
+	p := jsonPublication{
+		Metadata: jsonPublicationMetadata{
+			Type:       "http://schema.org/Book",
+			Identifier: bookID(b),
+			Title:      b.Title,
+			Author:     b.Author,
+		},
+		Links: []jsonLink{},
+	}
+	if ids := identifiers(b); len(ids) > 0 {
+		p.Metadata.Identifier = ids[0]
+	}
+	if b.Category != "" {
+		p.Metadata.Subject = []string{b.Category}
+	}
+	if !b.CreatedAt.IsZero() {
+		p.Metadata.Modified = b.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if cover := l.Asset(b.CoverUrl); cover != "" {
+		p.Images = []jsonLink{{Href: cover, Type: imageType(cover)}}
+	}
+	if typ := acquisitionType(b); typ != "" {
+		p.Links = append(p.Links, jsonLink{Rel: relAcquisition, Href: l.Download(b.ID), Type: typ})
+	} else if preview := previewURL(b); preview != "" {
+		p.Links = append(p.Links, jsonLink{Rel: "alternate", Href: preview, Type: "text/html", Title: "Preview"})
+	}
+	return p
+}
+
+// searchTemplate returns the RFC 6570 URI template of the search feed.
+func searchTemplate(l Links) string {
+	u := l.Feed(PathV2, opds.PathSearch, 0, "")
+	if strings.Contains(u, "?") {
+		return u + "{&q}"
+	}
+	return u + "{?q}"
+}
@@ -0,0 +1,178 @@
+// Package opdsfeed renders catalog feeds as OPDS 1.2 Atom and OPDS 2.0 JSON.
+package opdsfeed
+
+import (
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/opds"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+// Catalog roots, relative to the server.
+const (
+	PathV1         = "/opds/v1"
+	PathV2         = "/opds/v2"
+	PathOpenSearch = "/opds/opensearch.xml"
+	pathDownload   = "/opds/books/"
+)
+
+// Media types.
+const (
+	TypeAtomNavigation  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	TypeAtomAcquisition = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	TypeOPDSJSON        = "application/opds+json"
+	TypeOpenSearch      = "application/opensearchdescription+xml"
+)
+
+// Link relations defined by OPDS.
+const (
+	relAcquisition = "http://opds-spec.org/acquisition"
+	relImage       = "http://opds-spec.org/image"
+	relThumbnail   = "http://opds-spec.org/image/thumbnail"
+)
+
+// Links builds absolute URLs into the catalog. Token, if set, is added to
+// every authenticated link so apps that logged in with a token in the URL
+// can follow them.
+type Links struct {
+	Base  string
+	Token string
+}
+
+// Feed returns the URL of a feed under root, PathV1 or PathV2.
+func (l Links) Feed(root, feedPath string, page int, query string) string {
+	u := l.Base + root + "/" + escapePath(feedPath)
+	q := url.Values{}
+	if query != "" {
+		q.Set("q", query)
+	}
+	if page > 1 {
+		q.Set("page", strconv.Itoa(page))
+	}
+	return l.withToken(u, q)
+}
+
+// Download returns the acquisition URL of a book.
+func (l Links) Download(id string) string {
+	return l.withToken(l.Base+pathDownload+url.PathEscape(id)+"/download", url.Values{})
+}
+
+// OpenSearch returns the URL of the OpenSearch description.
+func (l Links) OpenSearch() string {
+	return l.withToken(l.Base+PathOpenSearch, url.Values{})
+}
+
+// Asset returns an absolute URL for a cover, which may already be one or
+// may be a path served by this server.
+func (l Links) Asset(ref string) string {
+	if ref == "" || isRemote(ref) {
+		return ref
+	}
+	return l.Base + "/" + strings.TrimPrefix(ref, "/")
+}
+
+func (l Links) withToken(u string, q url.Values) string {
+	if l.Token != "" {
+		q.Set("token", l.Token)
+	}
+	if len(q) == 0 {
+		return u
+	}
+	return u + "?" + q.Encode()
+}
+
+// escapePath escapes each segment of a feed path, so category names may
+// contain any character.
+func escapePath(p string) string {
+	if category, ok := opds.CategoryFromPath(p); ok {
+		return opds.PathCategories + "/" + url.PathEscape(category)
+	}
+	return p
+}
+
+// feedID is a stable identifier of a feed across formats.
+func feedID(f *opds.Feed) string {
+	return navigationID(f.Path)
+}
+
+func navigationID(feedPath string) string {
+	return "urn:btlubook:feed:" + escapePath(feedPath)
+}
+
+func bookID(b *book.Book) string {
+	return "urn:btlubook:book:" + b.ID
+}
+
+// identifiers returns the book's ISBN URNs.
+func identifiers(b *book.Book) []string {
+	var ids []string
+	if b.ISBN13 != "" {
+		ids = append(ids, "urn:isbn:"+b.ISBN13)
+	}
+	if b.ISBN10 != "" {
+		ids = append(ids, "urn:isbn:"+b.ISBN10)
+	}
+	return ids
+}
+
+// ebookTypes are the media types of book files reader apps can open.
+var ebookTypes = map[string]string{
+	".epub": "application/epub+zip",
+	".pdf":  "application/pdf",
+	".mobi": "application/x-mobipocket-ebook",
+	".azw3": "application/vnd.amazon.ebook",
+	".cbz":  "application/vnd.comicbook+zip",
+	".fb2":  "application/x-fictionbook+xml",
+	".txt":  "text/plain",
+}
+
+// acquisitionType returns the media type of the book's file, or "" if the
+// book has no file the caller may download, as with paid books they do not
+// own or imported books that only link to a preview page.
+func acquisitionType(b *book.Book) string {
+	if b.BookURL == "" || (b.Owned != nil && !*b.Owned) {
+		return ""
+	}
+	ref := b.BookURL
+	if u, err := url.Parse(ref); err == nil {
+		ref = u.Path
+	}
+	if typ, ok := ebookTypes[strings.ToLower(path.Ext(ref))]; ok {
+		return typ
+	}
+	if isRemote(b.BookURL) {
+		return ""
+	}
+	return "application/octet-stream"
+}
+
+// previewURL returns the page an imported book links to instead of a file.
+// Only imported books without a price get one, so the link of a book that
+// is sold here never reaches readers who have not bought it.
+func previewURL(b *book.Book) string {
+	imported := b.Source != "" && b.Source != book.SourceUpload
+	if !imported || order.PriceCents(b) > 0 || acquisitionType(b) != "" || !isRemote(b.BookURL) {
+		return ""
+	}
+	return b.BookURL
+}
+
+func isRemote(ref string) bool {
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://")
+}
+
+// imageType guesses a cover's media type from its extension.
+func imageType(ref string) string {
+	if u, err := url.Parse(ref); err == nil {
+		ref = u.Path
+	}
+	if t := mime.TypeByExtension(strings.ToLower(path.Ext(ref))); strings.HasPrefix(t, "image/") {
+		return t
+	}
+	return "image/jpeg"
+}
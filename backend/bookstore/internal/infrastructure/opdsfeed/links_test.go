@@ -0,0 +1,54 @@
+package opdsfeed
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/opds"
+)
+
+func price(p float32) *float32 { return &p }
+
+func TestPreviewLinksOnlyForFreeImportedBooks(t *testing.T) {
+	notOwned := false
+	tests := []struct {
+		name    string
+		book    book.Book
+		preview bool
+	}{
+		{"free imported book", book.Book{Source: "open_library", BookURL: "https://openlibrary.org/works/OL1W"}, true},
+		{"imported book priced zero", book.Book{Source: "google_books", Price: price(0), BookURL: "https://books.google.com/x"}, true},
+		{"paid imported book", book.Book{Source: "google_books", Price: price(12.5), BookURL: "https://books.google.com/x", Owned: &notOwned}, false},
+		{"paid upload not owned", book.Book{Source: book.SourceUpload, Price: price(5), BookURL: "https://cdn.example.com/dune", Owned: &notOwned}, false},
+		{"free upload with remote link", book.Book{Source: book.SourceUpload, BookURL: "https://cdn.example.com/dune"}, false},
+		{"legacy book without source", book.Book{BookURL: "https://cdn.example.com/dune"}, false},
+		{"imported book with a file", book.Book{Source: "open_library", BookURL: "https://example.com/emma.epub"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.book
+			b.ID, b.Title = "b1", "Book"
+			if got := previewURL(&b) != ""; got != tt.preview {
+				t.Errorf("preview link = %v, want %v", got, tt.preview)
+			}
+
+			f := &opds.Feed{Path: "new", Title: "New", Kind: opds.KindAcquisition, Updated: time.Now(), Books: []*book.Book{&b}}
+			for name, encode := range map[string]func(*bytes.Buffer) error{
+				"atom": func(w *bytes.Buffer) error { return EncodeAtom(w, f, Links{Base: "https://shelf.example"}) },
+				"json": func(w *bytes.Buffer) error { return EncodeJSON(w, f, Links{Base: "https://shelf.example"}) },
+			} {
+				var out bytes.Buffer
+				if err := encode(&out); err != nil {
+					t.Fatal(err)
+				}
+				// files are linked through the download route, never directly
+				if shown := strings.Contains(out.String(), b.BookURL); shown != tt.preview {
+					t.Errorf("%s feed shows book_url = %v, want %v:\n%s", name, shown, tt.preview, out.String())
+				}
+			}
+		})
+	}
+}
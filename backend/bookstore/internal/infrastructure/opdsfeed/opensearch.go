@@ -0,0 +1,53 @@
+package opdsfeed
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/opds"
+)
+
+type openSearchDescription struct {
+	XMLName        xml.Name        `xml:"OpenSearchDescription"`
+	Xmlns          string          `xml:"xmlns,attr"`
+	ShortName      string          `xml:"ShortName"`
+	Description    string          `xml:"Description"`
+	InputEncoding  string          `xml:"InputEncoding"`
+	OutputEncoding string          `xml:"OutputEncoding"`
+	URLs           []openSearchURL `xml:"Url"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// EncodeOpenSearch writes the OpenSearch description OPDS 1.2 apps use to
+// search the catalog.
+func EncodeOpenSearch(w io.Writer, l Links) error {
+	desc := openSearchDescription{
+		Xmlns:          "http://a9.com/-/spec/opensearch/1.1/",
+		ShortName:      "btluBook",
+		Description:    "Search the btluBook catalog by title, author or ISBN",
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URLs: []openSearchURL{
+			{Type: TypeAtomAcquisition, Template: openSearchTemplate(l)},
+		},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(desc)
+}
+
+func openSearchTemplate(l Links) string {
+	u := l.Feed(PathV1, opds.PathSearch, 0, "")
+	if strings.Contains(u, "?") {
+		return u + "&q={searchTerms}"
+	}
+	return u + "?q={searchTerms}"
+}
@@ -0,0 +1,88 @@
+// Package payment holds payment gateway implementations.
+package payment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+var _ order.PaymentGateway = (*FakeGateway)(nil)
+
+// FakeGateway approves every charge without moving money. It is meant for
+// development and tests; DeclineNext and FailNext script failures.
+type FakeGateway struct {
+	mu       sync.Mutex
+	next     int
+	decline  int
+	fail     int
+	byOrder  map[string]string
+	charges  []order.Charge
+	refunded map[string]string
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{byOrder: map[string]string{}, refunded: map[string]string{}}
+}
+
+// DeclineNext makes the next n charges fail with ErrPaymentDeclined.
+func (g *FakeGateway) DeclineNext(n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.decline = n
+}
+
+// FailNext makes the next n charges or refunds fail with
+// ErrPaymentUnavailable.
+func (g *FakeGateway) FailNext(n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.fail = n
+}
+
+// Charges returns the charges taken so far.
+func (g *FakeGateway) Charges() []order.Charge {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]order.Charge(nil), g.charges...)
+}
+
+func (g *FakeGateway) Charge(ctx context.Context, c order.Charge) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.fail > 0 {
+		g.fail--
+		return "", fmt.Errorf("%w: fake gateway failure", order.ErrPaymentUnavailable)
+	}
+	if g.decline > 0 {
+		g.decline--
+		return "", fmt.Errorf("%w: fake card declined", order.ErrPaymentDeclined)
+	}
+	// Charging an order again returns the first payment
+	if ref, ok := g.byOrder[c.OrderID]; ok {
+		return ref, nil
+	}
+	g.next++
+	ref := fmt.Sprintf("fake_ch_%d", g.next)
+	g.byOrder[c.OrderID] = ref
+	g.charges = append(g.charges, c)
+	return ref, nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, paymentRef string, amountCents int64, currency string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.fail > 0 {
+		g.fail--
+		return "", fmt.Errorf("%w: fake gateway failure", order.ErrPaymentUnavailable)
+	}
+	// Refunding a payment again returns the first refund
+	if ref, ok := g.refunded[paymentRef]; ok {
+		return ref, nil
+	}
+	ref := "fake_re_" + paymentRef
+	g.refunded[paymentRef] = ref
+	return ref, nil
+}
@@ -8,20 +8,24 @@ import (
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
 var jwtKey = []byte(os.Getenv("JWT_SECRET"))
 
 type JWTClaims struct {
-	UserID   int `json:"user_id"`	
+	UserID int      `json:"user_id"`
+	Roles  []string `json:"roles,omitempty"`
 	jwt.StandardClaims
 }
 
-func GenerateJWT(userID int) (string, error) {
+func GenerateJWT(userID int, roles ...string) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 	claims := &JWTClaims{
 		UserID: userID,
+		Roles:  roles,
 		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.New().String(),
 			ExpiresAt: expirationTime.Unix(),
 		},
 	}
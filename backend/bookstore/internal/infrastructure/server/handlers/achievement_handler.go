@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	achievementuc "github.com/bereke1t2/bookstore/internal/usecase/achievement"
+	"github.com/gin-gonic/gin"
+)
+
+type AchievementHandler struct {
+	getAchievementsUC *achievementuc.GetAchievementsUseCase
+}
+
+func NewAchievementHandler(getAchievementsUC *achievementuc.GetAchievementsUseCase) *AchievementHandler {
+	return &AchievementHandler{getAchievementsUC: getAchievementsUC}
+}
+
+// GetMyAchievements lists every badge with the reader's progress and award time.
+// GET /me/achievements
+func (h *AchievementHandler) GetMyAchievements(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	achievements, err := h.getAchievementsUC.Execute(c.Request.Context())
+	if err != nil {
+		writeActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"achievements": achievements}})
+}
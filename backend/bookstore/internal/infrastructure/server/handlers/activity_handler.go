@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	activityuc "github.com/bereke1t2/bookstore/internal/usecase/activity"
+	"github.com/gin-gonic/gin"
+)
+
+type ActivityHandler struct {
+	getStatsUC *activityuc.GetStatsUseCase
+}
+
+func NewActivityHandler(getStatsUC *activityuc.GetStatsUseCase) *ActivityHandler {
+	return &ActivityHandler{getStatsUC: getStatsUC}
+}
+
+// GetMyStats returns the reader's points, books read and current streak.
+// GET /me/stats
+func (h *ActivityHandler) GetMyStats(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	stats, err := h.getStatsUC.Execute(c.Request.Context())
+	if err != nil {
+		writeActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"stats": stats}})
+}
+
+func writeActivityError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	if errors.Is(err, activity.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
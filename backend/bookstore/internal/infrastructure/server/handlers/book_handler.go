@@ -1,14 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	book "github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
 	usecase "github.com/bereke1t2/bookstore/internal/usecase/book"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,6 +25,8 @@ type BookHandler struct {
 	getBookByIDUseCase      usecase.GetBookByID
 	updateBookUseCase       usecase.UpdateBook
 	getTrendingBooksUseCase usecase.GetTrendingBooks
+	downloadBookUseCase     usecase.DownloadBook
+	importBookUseCase       usecase.ImportBook
 }
 
 func NewBookHandler(
@@ -30,6 +36,8 @@ func NewBookHandler(
 	getBookByIDUC usecase.GetBookByID,
 	updateBookUC usecase.UpdateBook,
 	getTrendingBooksUC usecase.GetTrendingBooks,
+	downloadBookUC usecase.DownloadBook,
+	importBookUC usecase.ImportBook,
 ) *BookHandler {
 	return &BookHandler{
 		createBookUseCase:       createBookUC,
@@ -38,12 +46,18 @@ func NewBookHandler(
 		getBookByIDUseCase:      getBookByIDUC,
 		updateBookUseCase:       updateBookUC,
 		getTrendingBooksUseCase: getTrendingBooksUC,
+		downloadBookUseCase:     downloadBookUC,
+		importBookUseCase:       importBookUC,
 	}
 }
 
 func (h *BookHandler) GetAllBooks(c *gin.Context) {
 	// Gin handles Content-Type automatically when using c.JSON
-	books, err := h.getAllBooksUseCase.Execute()
+	books, err := h.getAllBooksUseCase.Execute(c.Request.Context(), c.Query("sort"))
+	if errors.Is(err, book.ErrInvalidBookInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be title, rating or newest"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -56,22 +70,28 @@ func (h *BookHandler) GetAllBooks(c *gin.Context) {
 	})
 }
 
+// DeleteBook deletes a book the caller uploaded. Admins may delete any book.
+// DELETE /books/:id
 func (h *BookHandler) DeleteBook(c *gin.Context) {
-	// Original code used Query param (?id=...) for delete
-	id := c.Query("id")
-
-	err := h.deleteBookUseCase.Execute(id)
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+	err := h.deleteBookUseCase.Execute(c.Request.Context(), bookIDParam(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeBookOwnerError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
+// UpdateBook updates a book the caller uploaded. Admins may update any book.
+// PUT /books/:id
 func (h *BookHandler) UpdateBook(c *gin.Context) {
-	// Original code used Query param (?id=...) for update
-	id := c.Query("id")
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+	id := bookIDParam(c)
 
 	var updatedBook book.Book
 	// ShouldBindJSON binds the request body to the struct
@@ -80,9 +100,9 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 		return
 	}
 
-	result, err := h.updateBookUseCase.Execute(id, &updatedBook)
+	result, err := h.updateBookUseCase.Execute(c.Request.Context(), id, &updatedBook)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeBookOwnerError(c, err)
 		return
 	}
 
@@ -93,11 +113,31 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 	})
 }
 
+// bookIDParam returns the book ID from the path. The original clients sent
+// it as ?id=..., which is still accepted.
+func bookIDParam(c *gin.Context) string {
+	if id := c.Param("id"); id != "" {
+		return id
+	}
+	return c.Query("id")
+}
+
+func writeBookOwnerError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	if errors.Is(err, book.ErrBookNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
 func (h *BookHandler) GetBookByID(c *gin.Context) {
 	// Original code used Mux Vars, which translates to Path Params in Gin (/books/:id)
 	id := c.Param("id")
 
-	book, err := h.getBookByIDUseCase.Execute(id)
+	book, err := h.getBookByIDUseCase.Execute(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -115,7 +155,11 @@ func (h *BookHandler) GetBookByID(c *gin.Context) {
 }
 
 func (h *BookHandler) GetTrendingBooks(c *gin.Context) {
-	books, err := h.getTrendingBooksUseCase.Execute()
+	books, err := h.getTrendingBooksUseCase.Execute(c.Request.Context())
+	if errors.Is(err, catalog.ErrExternalUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -127,29 +171,129 @@ func (h *BookHandler) GetTrendingBooks(c *gin.Context) {
 		},
 	})
 }
+
+// DownloadBook serves a book's file and counts the download towards
+// trending. Files kept elsewhere are redirected to. Paid books the caller
+// has not bought answer 402.
+// GET /books/:id/download
+func (h *BookHandler) DownloadBook(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	b, err := h.downloadBookUseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if writeIdentityError(c, err) {
+			return
+		}
+		if errors.Is(err, book.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+			return
+		}
+		if errors.Is(err, entitlement.ErrNotEntitled) {
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if strings.HasPrefix(b.BookURL, "http://") || strings.HasPrefix(b.BookURL, "https://") {
+		c.Redirect(http.StatusFound, b.BookURL)
+		return
+	}
+	// Uploaded files are stored as "/uploads/<name>"
+	path := filepath.Clean(strings.TrimPrefix(b.BookURL, "/"))
+	if !strings.HasPrefix(path, "uploads"+string(filepath.Separator)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book file not found"})
+		return
+	}
+	c.FileAttachment(path, b.Title+filepath.Ext(path))
+}
+
+// coverTypes are the extensions of the images ServeCover serves.
+var coverTypes = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".webp": true,
+	".gif":  true,
+}
+
+// ServeCover serves an uploaded cover image. Older book files sit next to
+// the covers in the uploads directory, so anything but an image is
+// reported missing; book files are only served by DownloadBook, which
+// checks the caller may read them.
+// GET /uploads/:name
+func ServeCover(c *gin.Context) {
+	name := c.Param("name")
+	if name != filepath.Base(name) || !coverTypes[strings.ToLower(filepath.Ext(name))] {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		return
+	}
+	c.File(filepath.Join("uploads", name))
+}
+
+// ImportBook adds a book from the external provider to the catalog. An
+// already imported book is returned with 200 instead of 201.
+// POST /books/import
+// Body: { "isbn": "..." } or { "volume_id": "..." }
+func (h *BookHandler) ImportBook(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var body struct {
+		ISBN     string `json:"isbn"`
+		VolumeID string `json:"volume_id"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	b, created, err := h.importBookUseCase.Execute(c.Request.Context(), body.ISBN, body.VolumeID)
+	if err != nil {
+		if writeIdentityError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, book.ErrInvalidISBN), errors.Is(err, book.ErrInvalidBookInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, catalog.ErrExternalNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, catalog.ErrExternalUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"data": gin.H{
+			"book":    b,
+			"created": created,
+		},
+	})
+}
+
 func (h *BookHandler) CreateBook(c *gin.Context) {
 	// 1. Get text fields from form
 	title := c.PostForm("title")
 	author := c.PostForm("author")
 	category := c.PostForm("category")
 	sharedBy := c.PostForm("shared_by")
-	ratingStr := c.PostForm("rating")
 	var tag string
 	if c.PostForm("tag") != "" {
 		tag = c.PostForm("tag")
 	}
 	isFeatured := c.PostForm("is_featured") == "true"
 
-	// Parse rating to float32
-	var rating float32
-	if ratingStr != "" {
-		rf, err := strconv.ParseFloat(ratingStr, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rating"})
-			return
-		}
-		rating = float32(rf)
-	}
 	var price float32
 	priceStr := c.PostForm("price")
 	if priceStr != "" {
@@ -161,6 +305,15 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		price = float32(pf)
 	}
 
+	var isbn10, isbn13 string
+	if isbn := c.PostForm("isbn"); isbn != "" {
+		var err error
+		if isbn10, isbn13, err = book.ParseISBN(isbn); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	// 2. Get cover image file
 	coverFile, err := c.FormFile("cover_url")
 	if err != nil {
@@ -190,10 +343,15 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		return
 	}
 
+	// Book files are kept apart from covers, out of reach of ServeCover
 	bookExt := filepath.Ext(bookFile.Filename)
 	bookName := fmt.Sprintf("%s%s", uuid.New().String(), bookExt)
-	bookPath := filepath.Join("uploads", bookName)
+	bookPath := filepath.Join("uploads", "books", bookName)
 
+	if err := os.MkdirAll(filepath.Dir(bookPath), 0755); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save book file"})
+		return
+	}
 	if err := c.SaveUploadedFile(bookFile, bookPath); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save book file"})
 		return
@@ -205,12 +363,13 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		Author:     author,
 		Category:   category,
 		SharedBy:   sharedBy,
-		Price:      price,
-		Rating:     rating,
+		Price:      &price,
 		Tag:        tag,
 		IsFeatured: isFeatured,
 		CoverUrl:   coverPath,
 		BookURL:    bookPath,
+		ISBN10:     isbn10,
+		ISBN13:     isbn13,
 	}
 
 	// 5. Call use case
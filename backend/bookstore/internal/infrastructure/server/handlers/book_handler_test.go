@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServeCoverServesOnlyImages(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, name := range []string{"cover.jpg", "cover.PNG", "book.pdf", "book.epub", filepath.Join("books", "new.pdf")} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join("uploads", name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join("uploads", name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/uploads/:name", ServeCover)
+
+	tests := map[string]int{
+		"/uploads/cover.jpg":       http.StatusOK,
+		"/uploads/cover.PNG":       http.StatusOK,
+		"/uploads/missing.jpg":     http.StatusNotFound,
+		"/uploads/book.pdf":        http.StatusNotFound,
+		"/uploads/book.epub":       http.StatusNotFound,
+		"/uploads/books/new.pdf":   http.StatusNotFound,
+		"/uploads/..%2Fsecret.jpg": http.StatusNotFound,
+		"/uploads/books%2Fnew.pdf": http.StatusNotFound,
+	}
+	for path, want := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, w.Code, want)
+		}
+	}
+}
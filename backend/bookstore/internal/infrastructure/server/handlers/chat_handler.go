@@ -1,12 +1,20 @@
 package handlers
 
 import (
+	"errors"
 	"io"
 	"net/http"
 
 	// "strconv"
 
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/chat"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/cache"
 	usecase "github.com/bereke1t2/bookstore/internal/usecase/chat"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
+	trendinguc "github.com/bereke1t2/bookstore/internal/usecase/trending"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,6 +24,10 @@ type ChatHandler struct {
 	GetShortAnswerUseCase        usecase.GetShortAnswerUseCase
 	GetChatResponsesUseCase      usecase.GetChatResponseUseCase
 	GetChatResponseStreamUseCase *usecase.GetChatResponseStreamUseCase
+	GradeShortAnswerUseCase      *usecase.GradeShortAnswerUseCase
+	ResponseCache                *cache.CachedChatRepository
+	RecordQuizActivityUseCase    *trendinguc.RecordQuizActivityUseCase
+	CheckAccessUseCase           *entitlementuc.CheckAccessUseCase
 }
 
 func NewChatHandler(
@@ -24,6 +36,10 @@ func NewChatHandler(
 	getShortAnswerUC usecase.GetShortAnswerUseCase,
 	getChatResponsesUC usecase.GetChatResponseUseCase,
 	getChatResponseStreamUC *usecase.GetChatResponseStreamUseCase,
+	gradeShortAnswerUC *usecase.GradeShortAnswerUseCase,
+	responseCache *cache.CachedChatRepository,
+	recordQuizActivityUC *trendinguc.RecordQuizActivityUseCase,
+	checkAccessUC *entitlementuc.CheckAccessUseCase,
 ) *ChatHandler {
 	return &ChatHandler{
 		GetMultipleQuuizUseCase:      getMultipleQuuizUC,
@@ -31,9 +47,47 @@ func NewChatHandler(
 		GetShortAnswerUseCase:        getShortAnswerUC,
 		GetChatResponsesUseCase:      getChatResponsesUC,
 		GetChatResponseStreamUseCase: getChatResponseStreamUC,
+		GradeShortAnswerUseCase:      gradeShortAnswerUC,
+		ResponseCache:                responseCache,
+		RecordQuizActivityUseCase:    recordQuizActivityUC,
+		CheckAccessUseCase:           checkAccessUC,
 	}
 }
 
+// quizRequest is the body accepted by every quiz generation endpoint.
+// The quiz options are optional and fall back to the usecase defaults.
+type quizRequest struct {
+	BookName string `json:"book_name" binding:"required"`
+	chat.QuizOptions
+}
+
+// writeChatError maps chat and AI errors to HTTP responses: invalid input is
+// a 400, a paid book the user is not entitled to a 402, an unknown book or
+// issued question a 404, an unusable model
+// response a 502 and an unreachable or rate-limited model a 503.
+func writeChatError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, chat.ErrInvalidQuizOptions), errors.Is(err, chat.ErrInvalidChatInput):
+		status = http.StatusBadRequest
+	case errors.Is(err, entitlement.ErrNotEntitled):
+		status = http.StatusPaymentRequired
+	case errors.Is(err, chat.ErrQuestionNotFound), errors.Is(err, book.ErrBookNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, chat.ErrAIMalformedResponse):
+		status = http.StatusBadGateway
+	case errors.Is(err, chat.ErrAIRateLimited):
+		c.Header("Retry-After", "60")
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, chat.ErrAIUnavailable):
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
 func (h *ChatHandler) GetChatResponses(c *gin.Context) {
 	chatID := 1
 	var body struct {
@@ -51,9 +105,12 @@ func (h *ChatHandler) GetChatResponses(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required parameters"})
 		return
 	}
+	if !h.authorizeBook(c, body.BookName) {
+		return
+	}
 	responses, err := h.GetChatResponsesUseCase.Execute(chatID, body.Prompt, body.BookName)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeChatError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, responses)
@@ -88,9 +145,7 @@ func (h *ChatHandler) StreamChatResponses(c *gin.Context) {
 }
 func (h *ChatHandler) GetMultipleChoiceQuestion(c *gin.Context) {
 	chatID := c.Param("id")
-	var body struct {
-		BookName string `json:"book_name" binding:"required"`
-	}
+	var body quizRequest
 
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -103,19 +158,21 @@ func (h *ChatHandler) GetMultipleChoiceQuestion(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required parameters", "chatID": chatID, "bookName": bookName})
 		return
 	}
-	question, err := h.GetMultipleQuuizUseCase.Execute(chatID, bookName)
+	if !h.authorizeBook(c, bookName) {
+		return
+	}
+	question, err := h.GetMultipleQuuizUseCase.Execute(chatID, bookName, body.QuizOptions)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeChatError(c, err)
 		return
 	}
+	h.recordQuizActivity(c, bookName)
 	c.JSON(http.StatusOK, question)
 }
 
 func (h *ChatHandler) GetTrueFalseQuestion(c *gin.Context) {
 	chatID := c.Param("id")
-	var body struct {
-		BookName string `json:"book_name" binding:"required"`
-	}
+	var body quizRequest
 
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -128,18 +185,20 @@ func (h *ChatHandler) GetTrueFalseQuestion(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required parameters", "chatID": chatID, "bookName": bookName})
 		return
 	}
-	question, err := h.GetTrueFalseUseCase.Execute(chatID, bookName)
+	if !h.authorizeBook(c, bookName) {
+		return
+	}
+	question, err := h.GetTrueFalseUseCase.Execute(chatID, bookName, body.QuizOptions)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeChatError(c, err)
 		return
 	}
+	h.recordQuizActivity(c, bookName)
 	c.JSON(http.StatusOK, question)
 }
 func (h *ChatHandler) GetShortAnswerQuestion(c *gin.Context) {
 	chatID := c.Param("id")
-	var body struct {
-		BookName string `json:"book_name" binding:"required"`
-	}
+	var body quizRequest
 
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -152,10 +211,96 @@ func (h *ChatHandler) GetShortAnswerQuestion(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required parameters", "chatID": chatID, "bookName": bookName})
 		return
 	}
-	question, err := h.GetShortAnswerUseCase.Execute(chatID, bookName)
+	if !h.authorizeBook(c, bookName) {
+		return
+	}
+	question, err := h.GetShortAnswerUseCase.Execute(c.Request.Context(), chatID, bookName, body.QuizOptions)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeChatError(c, err)
 		return
 	}
+	h.recordQuizActivity(c, bookName)
 	c.JSON(http.StatusOK, question)
 }
+
+// GradeShortAnswer grades a learner's answer to a short-answer question.
+// Answers to issued questions, named by question_id, earn points; a client
+// supplied question and reference answer are graded as practice only.
+// POST /quizzes/short-answer/:id/grade
+// Body: { "book_name": "...", "question_id": "...", "answer": "..." }
+// or { "book_name": "...", "question": "...", "reference_answer": "...", "answer": "..." }
+func (h *ChatHandler) GradeShortAnswer(c *gin.Context) {
+	chatID := c.Param("id")
+	var body struct {
+		BookName        string `json:"book_name" binding:"required"`
+		QuestionID      string `json:"question_id"`
+		Question        string `json:"question"`
+		ReferenceAnswer string `json:"reference_answer"`
+		Answer          string `json:"answer"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required parameters", "chatID": chatID})
+		return
+	}
+	if !h.authorizeBook(c, body.BookName) {
+		return
+	}
+	grade, err := h.GradeShortAnswerUseCase.Execute(c.Request.Context(), chatID, body.QuestionID, body.BookName, body.Question, body.ReferenceAnswer, body.Answer)
+	if err != nil {
+		writeChatError(c, err)
+		return
+	}
+	h.recordQuizActivity(c, body.BookName)
+	c.JSON(http.StatusOK, grade)
+}
+
+// authorizeBook checks that the user may read the named book before its
+// text is put to the AI, writing the error response if not.
+func (h *ChatHandler) authorizeBook(c *gin.Context, bookName string) bool {
+	if h.CheckAccessUseCase == nil {
+		return true
+	}
+	if err := h.CheckAccessUseCase.AuthorizeTitle(c.Request.Context(), bookName); err != nil {
+		writeChatError(c, err)
+		return false
+	}
+	return true
+}
+
+// recordQuizActivity counts a quiz towards the book's trending score.
+func (h *ChatHandler) recordQuizActivity(c *gin.Context, bookName string) {
+	if h.RecordQuizActivityUseCase != nil {
+		h.RecordQuizActivityUseCase.Execute(c.Request.Context(), bookName)
+	}
+}
+
+// GetCacheStats reports AI response cache hits and misses per endpoint to
+// admins.
+// GET /chats/cache/stats
+func (h *ChatHandler) GetCacheStats(c *gin.Context) {
+	p, ok := requirePrincipal(c)
+	if !ok {
+		return
+	}
+	if !p.IsAdmin() {
+		writeIdentityError(c, identity.ErrForbidden)
+		return
+	}
+	if h.ResponseCache == nil {
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"enabled": false}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"enabled": true,
+			"stats":   h.ResponseCache.Stats(),
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCacheStatsAreForAdmins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &ChatHandler{}
+
+	tests := []struct {
+		name  string
+		roles []string
+		want  int
+	}{
+		{"anonymous", nil, http.StatusUnauthorized},
+		{"user", []string{identity.RoleUser}, http.StatusForbidden},
+		{"admin", []string{identity.RoleUser, identity.RoleAdmin}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/chats/cache/stats", nil)
+			if tt.roles != nil {
+				req = req.WithContext(identity.WithPrincipal(req.Context(), identity.Principal{UserID: 1, Roles: tt.roles}))
+			}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			h.GetCacheStats(c)
+			if w.Code != tt.want {
+				t.Errorf("got %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/goal"
+	goaluc "github.com/bereke1t2/bookstore/internal/usecase/goal"
+	"github.com/gin-gonic/gin"
+)
+
+type GoalHandler struct {
+	createGoalUC *goaluc.CreateGoalUseCase
+	getGoalsUC   *goaluc.GetGoalsUseCase
+	deleteGoalUC *goaluc.DeleteGoalUseCase
+}
+
+func NewGoalHandler(
+	createGoalUC *goaluc.CreateGoalUseCase,
+	getGoalsUC *goaluc.GetGoalsUseCase,
+	deleteGoalUC *goaluc.DeleteGoalUseCase,
+) *GoalHandler {
+	return &GoalHandler{
+		createGoalUC: createGoalUC,
+		getGoalsUC:   getGoalsUC,
+		deleteGoalUC: deleteGoalUC,
+	}
+}
+
+// GetMyGoals lists the reader's goals with progress and pace projections.
+// GET /me/goals
+func (h *GoalHandler) GetMyGoals(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	goals, err := h.getGoalsUC.Execute(c.Request.Context())
+	if err != nil {
+		writeGoalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"goals": goals}})
+}
+
+// CreateGoal sets a new reading goal.
+// POST /me/goals
+// Body: { "type": "books", "target": 24, "year": 2027 }
+// or: { "type": "daily_minutes", "target": 30, "start_date": "2027-01-01", "end_date": "2027-03-31" }
+// Without a year or dates the goal covers the current year.
+func (h *GoalHandler) CreateGoal(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		Type      string `json:"type" binding:"required"`
+		Target    int    `json:"target" binding:"required"`
+		Year      int    `json:"year"`
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	g := &goal.Goal{Type: req.Type, Target: req.Target}
+	switch {
+	case req.Year != 0:
+		g.StartDate, g.EndDate = goal.YearPeriod(req.Year)
+	case req.StartDate != "" || req.EndDate != "":
+		var startErr, endErr error
+		g.StartDate, startErr = time.Parse(time.DateOnly, req.StartDate)
+		g.EndDate, endErr = time.Parse(time.DateOnly, req.EndDate)
+		if startErr != nil || endErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date must be YYYY-MM-DD"})
+			return
+		}
+	}
+
+	progress, err := h.createGoalUC.Execute(c.Request.Context(), g)
+	if err != nil {
+		writeGoalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{"goal": progress}})
+}
+
+// DeleteGoal removes one of the reader's goals.
+// DELETE /me/goals/:id
+func (h *GoalHandler) DeleteGoal(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	if err := h.deleteGoalUC.Execute(c.Request.Context(), c.Param("id")); err != nil {
+		writeGoalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "Goal deleted"}})
+}
+
+func writeGoalError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, goal.ErrInvalidGoal):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, goal.ErrGoalNotFound), errors.Is(err, activity.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/gin-gonic/gin"
+)
+
+// requirePrincipal returns the caller authenticated by AuthMiddleware. If
+// there is none it writes a 401 and returns false.
+func requirePrincipal(c *gin.Context) (identity.Principal, bool) {
+	p, ok := identity.FromContext(c.Request.Context())
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return identity.Principal{}, false
+	}
+	return p, true
+}
+
+// writeIdentityError writes a 401 or 403 for identity errors and reports
+// whether err was one.
+func writeIdentityError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	case errors.Is(err, identity.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/bereke1t2/bookstore/internal/domain/library"
+	libraryuc "github.com/bereke1t2/bookstore/internal/usecase/library"
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	startImportUC *libraryuc.StartImportUseCase
+	getImportUC   *libraryuc.GetImportUseCase
+	listImportsUC *libraryuc.ListImportsUseCase
+}
+
+func NewImportHandler(
+	startImportUC *libraryuc.StartImportUseCase,
+	getImportUC *libraryuc.GetImportUseCase,
+	listImportsUC *libraryuc.ListImportsUseCase,
+) *ImportHandler {
+	return &ImportHandler{
+		startImportUC: startImportUC,
+		getImportUC:   getImportUC,
+		listImportsUC: listImportsUC,
+	}
+}
+
+// StartImport uploads a library and starts importing it onto the reader's
+// shelves in the background.
+// POST /me/imports (multipart form, field "file")
+// Accepts a Goodreads export (.csv), a Calibre metadata.opf (.opf), a Calibre
+// metadata.db (.db) or a zipped Calibre library folder (.zip). Poll
+// GET /me/imports/:id for progress.
+func (h *ImportHandler) StartImport(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if file.Size > library.MaxImportBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, library.MaxImportBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.startImportUC.Execute(c.Request.Context(), file.Filename, data)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": gin.H{"import": job}})
+}
+
+// GetImport returns an import job's status, progress and unmatched rows.
+// GET /me/imports/:id
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	job, err := h.getImportUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"import": job}})
+}
+
+// ListImports lists the reader's recent import jobs.
+// GET /me/imports
+func (h *ImportHandler) ListImports(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	jobs, err := h.listImportsUC.Execute(c.Request.Context())
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"imports": jobs}})
+}
+
+func writeImportError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, library.ErrImportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, library.ErrImportInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, library.ErrUnsupportedFile):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, library.ErrInvalidImport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bereke1t2/bookstore/internal/domain/leaderboard"
+	"github.com/bereke1t2/bookstore/internal/domain/social"
+	leaderboarduc "github.com/bereke1t2/bookstore/internal/usecase/leaderboard"
+	socialuc "github.com/bereke1t2/bookstore/internal/usecase/social"
+	"github.com/gin-gonic/gin"
+)
+
+type LeaderboardHandler struct {
+	getLeaderboardUC *leaderboarduc.GetLeaderboardUseCase
+	addFriendUC      *socialuc.AddFriendUseCase
+	removeFriendUC   *socialuc.RemoveFriendUseCase
+	listFriendsUC    *socialuc.ListFriendsUseCase
+}
+
+func NewLeaderboardHandler(
+	getLeaderboardUC *leaderboarduc.GetLeaderboardUseCase,
+	addFriendUC *socialuc.AddFriendUseCase,
+	removeFriendUC *socialuc.RemoveFriendUseCase,
+	listFriendsUC *socialuc.ListFriendsUseCase,
+) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		getLeaderboardUC: getLeaderboardUC,
+		addFriendUC:      addFriendUC,
+		removeFriendUC:   removeFriendUC,
+		listFriendsUC:    listFriendsUC,
+	}
+}
+
+// GetLeaderboard returns the top users and the caller's own rank.
+// GET /leaderboards?scope=global|weekly|friends&metric=points|streak|books_read&limit=
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	board, err := h.getLeaderboardUC.Execute(c.Request.Context(), leaderboard.Query{
+		Scope:  c.Query("scope"),
+		Metric: c.Query("metric"),
+		Limit:  limit,
+	})
+	if err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"leaderboard": board}})
+}
+
+// ListFriends lists the users on the caller's friends list.
+// GET /me/friends
+func (h *LeaderboardHandler) ListFriends(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	friends, err := h.listFriendsUC.Execute(c.Request.Context())
+	if err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"friends": friends}})
+}
+
+// AddFriend adds a user to the caller's friends list.
+// POST /me/friends/:user_id
+func (h *LeaderboardHandler) AddFriend(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	friendID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := h.addFriendUC.Execute(c.Request.Context(), friendID); err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "Friend added"}})
+}
+
+// RemoveFriend removes a user from the caller's friends list.
+// DELETE /me/friends/:user_id
+func (h *LeaderboardHandler) RemoveFriend(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	friendID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := h.removeFriendUC.Execute(c.Request.Context(), friendID); err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "Friend removed"}})
+}
+
+func writeLeaderboardError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, leaderboard.ErrInvalidQuery), errors.Is(err, social.ErrInvalidFriend):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, leaderboard.ErrUserNotFound), errors.Is(err, social.ErrUserNotFound), errors.Is(err, social.ErrFriendNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
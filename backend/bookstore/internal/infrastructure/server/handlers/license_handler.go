@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
+	"github.com/gin-gonic/gin"
+)
+
+type LicenseHandler struct {
+	createLicenseUC *entitlementuc.CreateLicenseUseCase
+	listLicensesUC  *entitlementuc.ListLicensesUseCase
+	deleteLicenseUC *entitlementuc.DeleteLicenseUseCase
+	addMemberUC     *entitlementuc.AddLicenseMemberUseCase
+	removeMemberUC  *entitlementuc.RemoveLicenseMemberUseCase
+	listMembersUC   *entitlementuc.ListLicenseMembersUseCase
+}
+
+func NewLicenseHandler(
+	createLicenseUC *entitlementuc.CreateLicenseUseCase,
+	listLicensesUC *entitlementuc.ListLicensesUseCase,
+	deleteLicenseUC *entitlementuc.DeleteLicenseUseCase,
+	addMemberUC *entitlementuc.AddLicenseMemberUseCase,
+	removeMemberUC *entitlementuc.RemoveLicenseMemberUseCase,
+	listMembersUC *entitlementuc.ListLicenseMembersUseCase,
+) *LicenseHandler {
+	return &LicenseHandler{
+		createLicenseUC: createLicenseUC,
+		listLicensesUC:  listLicensesUC,
+		deleteLicenseUC: deleteLicenseUC,
+		addMemberUC:     addMemberUC,
+		removeMemberUC:  removeMemberUC,
+		listMembersUC:   listMembersUC,
+	}
+}
+
+// ListLicenses returns every institutional license. Admin only.
+// GET /admin/licenses
+func (h *LicenseHandler) ListLicenses(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	licenses, err := h.listLicensesUC.Execute(c.Request.Context())
+	if err != nil {
+		writeLicenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"licenses": licenses}})
+}
+
+// CreateLicense creates a license giving its members access to paid books,
+// all of them or one category's. Admin only.
+// POST /admin/licenses
+// Body: { "name": "...", "email_domain": "example.edu", "category": "...", "expires_at": "..." }
+func (h *LicenseHandler) CreateLicense(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var l entitlement.License
+	if err := c.ShouldBindJSON(&l); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	l.ID = ""
+
+	created, err := h.createLicenseUC.Execute(c.Request.Context(), &l)
+	if err != nil {
+		writeLicenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{"license": created}})
+}
+
+// DeleteLicense revokes an institutional license. Admin only.
+// DELETE /admin/licenses/:id
+func (h *LicenseHandler) DeleteLicense(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	if err := h.deleteLicenseUC.Execute(c.Request.Context(), c.Param("id")); err != nil {
+		writeLicenseError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListLicenseMembers lists the users on a license. Admin only.
+// GET /admin/licenses/:id/members
+func (h *LicenseHandler) ListLicenseMembers(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	members, err := h.listMembersUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeLicenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"members": members}})
+}
+
+// AddLicenseMember adds a user to a license. Admin only.
+// POST /admin/licenses/:id/members
+// Body: { "user_id": 42 }
+func (h *LicenseHandler) AddLicenseMember(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var body struct {
+		UserID int `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.addMemberUC.Execute(c.Request.Context(), c.Param("id"), body.UserID)
+	if err != nil {
+		writeLicenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{"member": member}})
+}
+
+// RemoveLicenseMember takes a user off a license. Admin only.
+// DELETE /admin/licenses/:id/members/:user_id
+func (h *LicenseHandler) RemoveLicenseMember(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	if err := h.removeMemberUC.Execute(c.Request.Context(), c.Param("id"), userID); err != nil {
+		writeLicenseError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func writeLicenseError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, entitlement.ErrLicenseNotFound), errors.Is(err, entitlement.ErrMemberNotFound), errors.Is(err, user.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entitlement.ErrInvalidLicense):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
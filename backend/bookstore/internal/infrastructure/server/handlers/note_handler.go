@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
 	"github.com/bereke1t2/bookstore/internal/domain/note"
 	noteuc "github.com/bereke1t2/bookstore/internal/usecase/note"
 	"github.com/gin-gonic/gin"
@@ -14,6 +17,10 @@ type NoteHandler struct {
 	getNotesUC       *noteuc.GetNotesUseCase
 	deleteNoteUC     *noteuc.DeleteNoteUseCase
 	generateAINoteUC *noteuc.GenerateAINoteUseCase
+	updateNoteUC     *noteuc.UpdateNoteUseCase
+	searchNotesUC    *noteuc.SearchNotesUseCase
+	exportNotesUC    *noteuc.ExportNotesUseCase
+	syncNotesUC      *noteuc.SyncNotesUseCase
 }
 
 func NewNoteHandler(
@@ -21,28 +28,42 @@ func NewNoteHandler(
 	getNotesUC *noteuc.GetNotesUseCase,
 	deleteNoteUC *noteuc.DeleteNoteUseCase,
 	generateAINoteUC *noteuc.GenerateAINoteUseCase,
+	updateNoteUC *noteuc.UpdateNoteUseCase,
+	searchNotesUC *noteuc.SearchNotesUseCase,
+	exportNotesUC *noteuc.ExportNotesUseCase,
+	syncNotesUC *noteuc.SyncNotesUseCase,
 ) *NoteHandler {
 	return &NoteHandler{
 		createNoteUC:     createNoteUC,
 		getNotesUC:       getNotesUC,
 		deleteNoteUC:     deleteNoteUC,
 		generateAINoteUC: generateAINoteUC,
+		updateNoteUC:     updateNoteUC,
+		searchNotesUC:    searchNotesUC,
+		exportNotesUC:    exportNotesUC,
+		syncNotesUC:      syncNotesUC,
 	}
 }
 
 // CreateNote handles manual note creation.
 // POST /notes
-// Body: { "book_id": "...", "content": "..." }
+// Body: { "id": "<optional client UUID>", "book_id": "...", "content": "...", "quote": "...",
+// "page_number": 12, "cfi": "epubcfi(...)", "color": "yellow", "tags": ["..."], "comment": "..." }
+// Either content or quote is required; a note with a quote is a highlight.
 func (h *NoteHandler) CreateNote(c *gin.Context) {
-	userID, err := getUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	if _, ok := requirePrincipal(c); !ok {
 		return
 	}
 
 	var req struct {
-		BookID  string `json:"book_id" binding:"required"`
-		Content string `json:"content" binding:"required"`
+		ID     string `json:"id"`
+		BookID string `json:"book_id" binding:"required"`
+		note.Anchor
+		Content string   `json:"content"`
+		Quote   string   `json:"quote"`
+		Comment string   `json:"comment"`
+		Color   string   `json:"color"`
+		Tags    []string `json:"tags"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -50,15 +71,20 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 	}
 
 	n := &note.Note{
-		UserID:        userID,
+		ID:            req.ID,
 		BookID:        req.BookID,
+		Anchor:        req.Anchor,
 		Content:       req.Content,
+		Quote:         req.Quote,
+		Comment:       req.Comment,
+		Color:         req.Color,
+		Tags:          req.Tags,
 		IsAIGenerated: false,
 	}
 
-	created, err := h.createNoteUC.Execute(n)
+	created, err := h.createNoteUC.Execute(c.Request.Context(), n)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeNoteError(c, err)
 		return
 	}
 
@@ -67,26 +93,25 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 
 // GenerateAINote handles AI-powered note generation.
 // POST /notes/ai
-// Body: { "book_id": "...", "text": "selected text..." }
+// Body: { "book_id": "...", "text": "selected text...", "page_number": 12, "cfi": "epubcfi(...)" }
 func (h *NoteHandler) GenerateAINote(c *gin.Context) {
-	userID, err := getUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	if _, ok := requirePrincipal(c); !ok {
 		return
 	}
 
 	var req struct {
 		BookID string `json:"book_id" binding:"required"`
 		Text   string `json:"text" binding:"required"`
+		note.Anchor
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	created, err := h.generateAINoteUC.Execute(c.Request.Context(), userID, req.BookID, req.Text)
+	created, err := h.generateAINoteUC.Execute(c.Request.Context(), req.BookID, req.Text, req.Anchor)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeNoteError(c, err)
 		return
 	}
 
@@ -94,11 +119,9 @@ func (h *NoteHandler) GenerateAINote(c *gin.Context) {
 }
 
 // GetNotes retrieves notes for a specific book.
-// GET /notes/:book_id
+// GET /notes/:book_id?tag=...&color=...&page=...
 func (h *NoteHandler) GetNotes(c *gin.Context) {
-	userID, err := getUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	if _, ok := requirePrincipal(c); !ok {
 		return
 	}
 
@@ -108,21 +131,152 @@ func (h *NoteHandler) GetNotes(c *gin.Context) {
 		return
 	}
 
-	notes, err := h.getNotesUC.Execute(bookID, userID)
+	filter := note.Filter{
+		Tag:   c.Query("tag"),
+		Color: c.Query("color"),
+	}
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page"})
+			return
+		}
+		filter.Page = &page
+	}
+
+	notes, err := h.getNotesUC.Execute(c.Request.Context(), bookID, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeNoteError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": gin.H{"notes": notes}})
 }
 
+// SearchNotes runs a full-text search across all of the user's notes.
+// GET /notes/search?q=...&ai=true|false&book_id=...&limit=20&offset=0
+func (h *NoteHandler) SearchNotes(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	query := note.SearchQuery{
+		Text:   c.Query("q"),
+		BookID: c.Query("book_id"),
+	}
+	if v := c.Query("ai"); v != "" {
+		ai, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ai"})
+			return
+		}
+		query.IsAIGenerated = &ai
+	}
+	var err error
+	if query.Limit, err = queryInt(c, "limit"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	if query.Offset, err = queryInt(c, "offset"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+
+	results, err := h.searchNotesUC.Execute(c.Request.Context(), query)
+	if err != nil {
+		writeNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"results": results}})
+}
+
+// ExportNotes downloads the user's notes for one book, or all books when
+// book_id is omitted.
+// GET /notes/export?format=markdown|json|anki&book_id=...
+func (h *NoteHandler) ExportNotes(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	format, err := note.ParseExportFormat(c.Query("format"))
+	if err != nil {
+		writeNoteError(c, err)
+		return
+	}
+
+	export, err := h.exportNotesUC.Execute(c.Request.Context(), c.Query("book_id"), format)
+	if err != nil {
+		writeNoteError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	c.Data(http.StatusOK, export.ContentType, export.Body)
+}
+
+// SyncNotes exchanges note changes with an offline-capable client.
+// POST /notes/sync
+// Body: { "sync_token": "<from the last sync, empty the first time>", "changes": [ <note>, ... ] }
+// Each change carries the version it was based on and deleted_at for deletions.
+// The response holds the server's changes since sync_token and a new sync_token;
+// when has_more is true the client should sync again straight away.
+func (h *NoteHandler) SyncNotes(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		SyncToken string       `json:"sync_token"`
+		Changes   []*note.Note `json:"changes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.syncNotesUC.Execute(c.Request.Context(), req.SyncToken, req.Changes)
+	if err != nil {
+		writeNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// UpdateNote applies a partial update to a note.
+// PATCH /notes/:note_id
+// Body: any of { "content", "quote", "comment", "color", "tags", "page_number", "cfi" }
+func (h *NoteHandler) UpdateNote(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	noteID := c.Param("note_id")
+	if noteID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "note_id is required"})
+		return
+	}
+
+	var patch note.Patch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.updateNoteUC.Execute(c.Request.Context(), noteID, patch)
+	if err != nil {
+		writeNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"note": updated}})
+}
+
 // DeleteNote removes a note.
 // DELETE /notes/:note_id
 func (h *NoteHandler) DeleteNote(c *gin.Context) {
-	userID, err := getUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	if _, ok := requirePrincipal(c); !ok {
 		return
 	}
 
@@ -132,34 +286,47 @@ func (h *NoteHandler) DeleteNote(c *gin.Context) {
 		return
 	}
 
-	if err := h.deleteNoteUC.Execute(noteID, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.deleteNoteUC.Execute(c.Request.Context(), noteID); err != nil {
+		writeNoteError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
-// Helper to extract user ID from JWT claims set by middleware.
-func getUserIDFromContext(c *gin.Context) (int, error) {
-	// The middleware should set "user_id" in the context
-	userIDRaw, exists := c.Get("user_id")
-	if !exists {
-		// Try header fallback for testing
-		userIDStr := c.GetHeader("X-User-ID")
-		if userIDStr != "" {
-			return strconv.Atoi(userIDStr)
-		}
+// queryInt parses an optional non-negative integer query parameter; a
+// missing parameter yields 0.
+func queryInt(c *gin.Context, name string) (int, error) {
+	v := c.Query(name)
+	if v == "" {
 		return 0, nil
 	}
-	switch v := userIDRaw.(type) {
-	case int:
-		return v, nil
-	case float64:
-		return int(v), nil
-	case string:
-		return strconv.Atoi(v)
-	default:
-		return 0, nil
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, errors.New("invalid " + name)
+	}
+	return n, nil
+}
+
+func writeNoteError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	if errors.Is(err, note.ErrNoteNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, note.ErrInvalidNote) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, note.ErrNoteExists) || errors.Is(err, note.ErrNoteVersionConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, entitlement.ErrNotEntitled) {
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		return
 	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 }
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/opds"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/opdsfeed"
+	opdsuc "github.com/bereke1t2/bookstore/internal/usecase/opds"
+	useruc "github.com/bereke1t2/bookstore/internal/usecase/user"
+	"github.com/gin-gonic/gin"
+)
+
+type OPDSHandler struct {
+	getFeedUC *opdsuc.GetFeedUseCase
+	loginUC   *useruc.LoginUseCase
+}
+
+func NewOPDSHandler(getFeedUC *opdsuc.GetFeedUseCase, loginUC *useruc.LoginUseCase) *OPDSHandler {
+	return &OPDSHandler{getFeedUC: getFeedUC, loginUC: loginUC}
+}
+
+// Authenticate checks the HTTP Basic credentials reader apps send.
+func (h *OPDSHandler) Authenticate(email, password string) (identity.Principal, error) {
+	return h.loginUC.Authenticate(email, password)
+}
+
+// GetAtomFeed serves a catalog feed as OPDS 1.2.
+// GET /opds/v1/*feed?page=&q=
+func (h *OPDSHandler) GetAtomFeed(c *gin.Context) {
+	feed, ok := h.feed(c)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	if err := opdsfeed.EncodeAtom(&buf, feed, opdsLinks(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, opdsfeed.AtomType(feed)+";charset=utf-8", buf.Bytes())
+}
+
+// GetJSONFeed serves a catalog feed as OPDS 2.0.
+// GET /opds/v2/*feed?page=&q=
+func (h *OPDSHandler) GetJSONFeed(c *gin.Context) {
+	feed, ok := h.feed(c)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	if err := opdsfeed.EncodeJSON(&buf, feed, opdsLinks(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, opdsfeed.TypeOPDSJSON, buf.Bytes())
+}
+
+// GetOpenSearch serves the OpenSearch description of the catalog search.
+// GET /opds/opensearch.xml
+func (h *OPDSHandler) GetOpenSearch(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := opdsfeed.EncodeOpenSearch(&buf, opdsLinks(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, opdsfeed.TypeOpenSearch+";charset=utf-8", buf.Bytes())
+}
+
+func (h *OPDSHandler) feed(c *gin.Context) (*opds.Feed, bool) {
+	if _, ok := requirePrincipal(c); !ok {
+		return nil, false
+	}
+	page, err := queryInt(c, "page")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	query := c.Query("q")
+	if query == "" {
+		query = c.Query("query")
+	}
+
+	feed, err := h.getFeedUC.Execute(c.Request.Context(), opds.Request{
+		Path:  c.Param("feed"),
+		Query: query,
+		Page:  page,
+	})
+	if err != nil {
+		writeOPDSError(c, err)
+		return nil, false
+	}
+	return feed, true
+}
+
+// opdsLinks builds feed links against the host the app reached us on,
+// honouring the headers set by a TLS-terminating proxy.
+func opdsLinks(c *gin.Context) opdsfeed.Links {
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	host := c.GetHeader("X-Forwarded-Host")
+	if host == "" {
+		host = c.Request.Host
+	}
+	return opdsfeed.Links{Base: scheme + "://" + host, Token: c.Query("token")}
+}
+
+func writeOPDSError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, opds.ErrInvalidRequest):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, opds.ErrFeedNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
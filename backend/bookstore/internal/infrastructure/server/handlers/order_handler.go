@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+	orderuc "github.com/bereke1t2/bookstore/internal/usecase/order"
+	"github.com/gin-gonic/gin"
+)
+
+type OrderHandler struct {
+	getCartUC        *orderuc.GetCartUseCase
+	addToCartUC      *orderuc.AddToCartUseCase
+	removeFromCartUC *orderuc.RemoveFromCartUseCase
+	checkoutUC       *orderuc.CheckoutUseCase
+	payOrderUC       *orderuc.PayOrderUseCase
+	listOrdersUC     *orderuc.ListOrdersUseCase
+	getOrderUC       *orderuc.GetOrderUseCase
+	refundOrderUC    *orderuc.RefundOrderUseCase
+}
+
+func NewOrderHandler(
+	getCartUC *orderuc.GetCartUseCase,
+	addToCartUC *orderuc.AddToCartUseCase,
+	removeFromCartUC *orderuc.RemoveFromCartUseCase,
+	checkoutUC *orderuc.CheckoutUseCase,
+	payOrderUC *orderuc.PayOrderUseCase,
+	listOrdersUC *orderuc.ListOrdersUseCase,
+	getOrderUC *orderuc.GetOrderUseCase,
+	refundOrderUC *orderuc.RefundOrderUseCase,
+) *OrderHandler {
+	return &OrderHandler{
+		getCartUC:        getCartUC,
+		addToCartUC:      addToCartUC,
+		removeFromCartUC: removeFromCartUC,
+		checkoutUC:       checkoutUC,
+		payOrderUC:       payOrderUC,
+		listOrdersUC:     listOrdersUC,
+		getOrderUC:       getOrderUC,
+		refundOrderUC:    refundOrderUC,
+	}
+}
+
+// GetCart returns the caller's cart at current prices.
+// GET /me/cart
+func (h *OrderHandler) GetCart(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	cart, err := h.getCartUC.Execute(c.Request.Context())
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"cart": cart}})
+}
+
+// AddToCart puts a book in the caller's cart and returns the cart.
+// POST /me/cart/items
+// Body: { "book_id": "..." }
+func (h *OrderHandler) AddToCart(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		BookID string `json:"book_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.addToCartUC.Execute(c.Request.Context(), req.BookID); err != nil {
+		writeOrderError(c, err)
+		return
+	}
+	h.GetCart(c)
+}
+
+// RemoveFromCart takes a book out of the caller's cart and returns the cart.
+// DELETE /me/cart/items/:book_id
+func (h *OrderHandler) RemoveFromCart(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	if err := h.removeFromCartUC.Execute(c.Request.Context(), c.Param("book_id")); err != nil {
+		writeOrderError(c, err)
+		return
+	}
+	h.GetCart(c)
+}
+
+// Checkout orders the books in the caller's cart and pays for them. A
+// declined payment answers 402 with the pending order, which can be paid
+// again with PayOrder.
+// POST /me/cart/checkout
+func (h *OrderHandler) Checkout(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	o, err := h.checkoutUC.Execute(c.Request.Context())
+	if err != nil {
+		writeOrderError(c, err, o)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{"order": o}})
+}
+
+// PayOrder retries the payment of a pending order.
+// POST /me/orders/:id/pay
+func (h *OrderHandler) PayOrder(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	o, err := h.payOrderUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeOrderError(c, err, o)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"order": o}})
+}
+
+// ListOrders lists the caller's orders, newest first.
+// GET /me/orders
+func (h *OrderHandler) ListOrders(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	orders, err := h.listOrdersUC.Execute(c.Request.Context())
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"orders": orders}})
+}
+
+// GetOrder returns one of the caller's orders.
+// GET /me/orders/:id
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	o, err := h.getOrderUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"order": o}})
+}
+
+// RefundOrder refunds a paid order and revokes its books. Admin only.
+// POST /admin/orders/:id/refund
+func (h *OrderHandler) RefundOrder(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	o, err := h.refundOrderUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"order": o}})
+}
+
+// writeOrderError maps order errors to responses. A failed payment includes
+// the pending order, if there is one, so the client can retry it.
+func writeOrderError(c *gin.Context, err error, pending ...*order.Order) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	body := gin.H{"error": err.Error()}
+	if len(pending) > 0 && pending[0] != nil {
+		body["data"] = gin.H{"order": pending[0]}
+	}
+	switch {
+	case errors.Is(err, order.ErrOrderNotFound), errors.Is(err, order.ErrItemNotFound), errors.Is(err, book.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, body)
+	case errors.Is(err, order.ErrCartEmpty), errors.Is(err, order.ErrNotForSale):
+		c.JSON(http.StatusBadRequest, body)
+	case errors.Is(err, order.ErrAlreadyOwned), errors.Is(err, order.ErrCartFull), errors.Is(err, order.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, body)
+	case errors.Is(err, order.ErrPaymentDeclined):
+		c.JSON(http.StatusPaymentRequired, body)
+	case errors.Is(err, order.ErrPaymentUnavailable):
+		c.JSON(http.StatusServiceUnavailable, body)
+	default:
+		c.JSON(http.StatusInternalServerError, body)
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/rating"
+	ratinguc "github.com/bereke1t2/bookstore/internal/usecase/rating"
+	"github.com/gin-gonic/gin"
+)
+
+type RatingHandler struct {
+	submitReviewUC        *ratinguc.SubmitReviewUseCase
+	deleteReviewUC        *ratinguc.DeleteReviewUseCase
+	listReviewsUC         *ratinguc.ListReviewsUseCase
+	reportReviewUC        *ratinguc.ReportReviewUseCase
+	listReportedReviewsUC *ratinguc.ListReportedReviewsUseCase
+	moderateReviewUC      *ratinguc.ModerateReviewUseCase
+}
+
+func NewRatingHandler(
+	submitReviewUC *ratinguc.SubmitReviewUseCase,
+	deleteReviewUC *ratinguc.DeleteReviewUseCase,
+	listReviewsUC *ratinguc.ListReviewsUseCase,
+	reportReviewUC *ratinguc.ReportReviewUseCase,
+	listReportedReviewsUC *ratinguc.ListReportedReviewsUseCase,
+	moderateReviewUC *ratinguc.ModerateReviewUseCase,
+) *RatingHandler {
+	return &RatingHandler{
+		submitReviewUC:        submitReviewUC,
+		deleteReviewUC:        deleteReviewUC,
+		listReviewsUC:         listReviewsUC,
+		reportReviewUC:        reportReviewUC,
+		listReportedReviewsUC: listReportedReviewsUC,
+		moderateReviewUC:      moderateReviewUC,
+	}
+}
+
+// SubmitReview creates or edits the caller's review of a book.
+// PUT /books/:id/reviews/me
+// Body: { "rating": 4, "text": "optional" }
+func (h *RatingHandler) SubmitReview(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req rating.Draft
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, created, err := h.submitReviewUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		writeRatingError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"data": gin.H{"review": review}})
+}
+
+// DeleteReview deletes the caller's review of a book.
+// DELETE /books/:id/reviews/me
+func (h *RatingHandler) DeleteReview(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	if err := h.deleteReviewUC.Execute(c.Request.Context(), c.Param("id")); err != nil {
+		writeRatingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListReviews returns a page of a book's reviews with its rating summary.
+// GET /books/:id/reviews?limit=&offset=
+func (h *RatingHandler) ListReviews(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	limit, offset, ok := pageQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.listReviewsUC.Execute(c.Request.Context(), c.Param("id"), limit, offset)
+	if err != nil {
+		writeRatingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": page})
+}
+
+// ReportReview flags a review as abusive.
+// POST /books/:id/reviews/:review_id/report
+// Body: { "reason": "optional" }
+func (h *RatingHandler) ReportReview(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := h.reportReviewUC.Execute(c.Request.Context(), c.Param("review_id"), req.Reason); err != nil {
+		writeRatingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListReportedReviews returns reported reviews awaiting moderation.
+// GET /admin/reviews/reported?limit=&offset=
+func (h *RatingHandler) ListReportedReviews(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	limit, offset, ok := pageQuery(c)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.listReportedReviewsUC.Execute(c.Request.Context(), limit, offset)
+	if err != nil {
+		writeRatingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"reviews": reviews}})
+}
+
+// ModerateReview hides, restores or deletes a review.
+// PUT /admin/reviews/:review_id/moderation
+// Body: { "action": "hide|restore|delete", "reason": "optional" }
+func (h *RatingHandler) ModerateReview(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		Action string `json:"action" binding:"required"`
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.moderateReviewUC.Execute(c.Request.Context(), c.Param("review_id"), req.Action, req.Reason)
+	if err != nil {
+		writeRatingError(c, err)
+		return
+	}
+	if review == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"review": review}})
+}
+
+// pageQuery parses the optional limit and offset query parameters, writing
+// a 400 response if either is invalid.
+func pageQuery(c *gin.Context) (int, int, bool) {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, 0, false
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, 0, false
+	}
+	return limit, offset, true
+}
+
+func writeRatingError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, rating.ErrReviewNotFound), errors.Is(err, book.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, rating.ErrInvalidReview), errors.Is(err, rating.ErrOwnReview):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/reading"
+	readinguc "github.com/bereke1t2/bookstore/internal/usecase/reading"
+	"github.com/gin-gonic/gin"
+)
+
+type ReadingHandler struct {
+	updateProgressUC *readinguc.UpdateProgressUseCase
+	getReadingUC     *readinguc.GetReadingUseCase
+}
+
+func NewReadingHandler(
+	updateProgressUC *readinguc.UpdateProgressUseCase,
+	getReadingUC *readinguc.GetReadingUseCase,
+) *ReadingHandler {
+	return &ReadingHandler{
+		updateProgressUC: updateProgressUC,
+		getReadingUC:     getReadingUC,
+	}
+}
+
+// UpdateProgress records where the reader stopped in a book.
+// PUT /books/:id/progress
+// Body: { "page_number": 42, "cfi": "epubcfi(...)", "percent": 37.5, "time_spent_seconds": 600, "finished": false }
+// time_spent_seconds is the reading time since the previous update.
+func (h *ReadingHandler) UpdateProgress(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var update reading.Update
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	progress, err := h.updateProgressUC.Execute(c.Request.Context(), c.Param("id"), update)
+	if err != nil {
+		writeReadingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"progress": progress}})
+}
+
+// GetMyReading lists the books the reader has started and finished.
+// GET /me/reading
+func (h *ReadingHandler) GetMyReading(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	list, err := h.getReadingUC.Execute(c.Request.Context())
+	if err != nil {
+		writeReadingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+func writeReadingError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, book.ErrBookNotFound), errors.Is(err, reading.ErrProgressNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, reading.ErrInvalidProgress):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	recommendationuc "github.com/bereke1t2/bookstore/internal/usecase/recommendation"
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	getRecommendationsUC *recommendationuc.GetRecommendationsUseCase
+}
+
+func NewRecommendationHandler(getRecommendationsUC *recommendationuc.GetRecommendationsUseCase) *RecommendationHandler {
+	return &RecommendationHandler{getRecommendationsUC: getRecommendationsUC}
+}
+
+// GetMyRecommendations returns books picked for the reader, each with the
+// reasons it was picked.
+// GET /me/recommendations?limit=
+func (h *RecommendationHandler) GetMyRecommendations(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recs, err := h.getRecommendationsUC.Execute(c.Request.Context(), limit)
+	if err != nil {
+		if writeIdentityError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"recommendations": recs}})
+}
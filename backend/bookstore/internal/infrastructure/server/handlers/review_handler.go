@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bereke1t2/bookstore/internal/domain/review"
+	reviewuc "github.com/bereke1t2/bookstore/internal/usecase/review"
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	createFromQuizUC  *reviewuc.CreateCardsFromQuizUseCase
+	createFromNotesUC *reviewuc.CreateCardsFromNotesUseCase
+	getDueCardsUC     *reviewuc.GetDueCardsUseCase
+	reviewCardUC      *reviewuc.ReviewCardUseCase
+	getDecksUC        *reviewuc.GetDecksUseCase
+}
+
+func NewReviewHandler(
+	createFromQuizUC *reviewuc.CreateCardsFromQuizUseCase,
+	createFromNotesUC *reviewuc.CreateCardsFromNotesUseCase,
+	getDueCardsUC *reviewuc.GetDueCardsUseCase,
+	reviewCardUC *reviewuc.ReviewCardUseCase,
+	getDecksUC *reviewuc.GetDecksUseCase,
+) *ReviewHandler {
+	return &ReviewHandler{
+		createFromQuizUC:  createFromQuizUC,
+		createFromNotesUC: createFromNotesUC,
+		getDueCardsUC:     getDueCardsUC,
+		reviewCardUC:      reviewCardUC,
+		getDecksUC:        getDecksUC,
+	}
+}
+
+// GetDueCards lists cards that are due for review.
+// GET /review/due?book_id=...&limit=20
+func (h *ReviewHandler) GetDueCards(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = n
+	}
+
+	cards, err := h.getDueCardsUC.Execute(c.Request.Context(), c.Query("book_id"), limit)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"cards": cards}})
+}
+
+// ReviewCard records how well the reader recalled a card.
+// POST /review/:card_id
+// Body: { "grade": 0-5 }
+func (h *ReviewHandler) ReviewCard(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		Grade *int `json:"grade" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, err := h.reviewCardUC.Execute(c.Request.Context(), c.Param("card_id"), *req.Grade)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"card": card}})
+}
+
+// GetDecks lists the reader's per-book decks.
+// GET /review/decks
+func (h *ReviewHandler) GetDecks(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	decks, err := h.getDecksUC.Execute(c.Request.Context())
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"decks": decks}})
+}
+
+// CreateCardsFromQuiz adds missed quiz questions to the book's deck.
+// POST /review/cards/quiz
+// Body: { "book_id": "...", "multiple_choice": [...], "true_false": [...], "short_answer": [...] }
+func (h *ReviewHandler) CreateCardsFromQuiz(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		BookID string `json:"book_id" binding:"required"`
+		reviewuc.MissedQuestions
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cards, err := h.createFromQuizUC.Execute(c.Request.Context(), req.BookID, req.MissedQuestions)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{"cards": cards}})
+}
+
+// CreateCardsFromNotes adds the reader's notes on a book to its deck.
+// POST /review/cards/notes
+// Body: { "book_id": "..." }
+func (h *ReviewHandler) CreateCardsFromNotes(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		BookID string `json:"book_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cards, err := h.createFromNotesUC.Execute(c.Request.Context(), req.BookID)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{"cards": cards}})
+}
+
+func writeReviewError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, review.ErrCardNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, review.ErrInvalidGrade), errors.Is(err, review.ErrInvalidCard):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
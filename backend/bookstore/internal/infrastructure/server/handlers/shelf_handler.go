@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+	shelfuc "github.com/bereke1t2/bookstore/internal/usecase/shelf"
+	"github.com/gin-gonic/gin"
+)
+
+type ShelfHandler struct {
+	listShelvesUC     *shelfuc.ListShelvesUseCase
+	createShelfUC     *shelfuc.CreateShelfUseCase
+	renameShelfUC     *shelfuc.RenameShelfUseCase
+	deleteShelfUC     *shelfuc.DeleteShelfUseCase
+	reorderShelvesUC  *shelfuc.ReorderShelvesUseCase
+	getShelfUC        *shelfuc.GetShelfUseCase
+	addToShelfUC      *shelfuc.AddToShelfUseCase
+	removeFromShelfUC *shelfuc.RemoveFromShelfUseCase
+	reorderShelfUC    *shelfuc.ReorderShelfUseCase
+	exportShelvesUC   *shelfuc.ExportShelvesUseCase
+}
+
+func NewShelfHandler(
+	listShelvesUC *shelfuc.ListShelvesUseCase,
+	createShelfUC *shelfuc.CreateShelfUseCase,
+	renameShelfUC *shelfuc.RenameShelfUseCase,
+	deleteShelfUC *shelfuc.DeleteShelfUseCase,
+	reorderShelvesUC *shelfuc.ReorderShelvesUseCase,
+	getShelfUC *shelfuc.GetShelfUseCase,
+	addToShelfUC *shelfuc.AddToShelfUseCase,
+	removeFromShelfUC *shelfuc.RemoveFromShelfUseCase,
+	reorderShelfUC *shelfuc.ReorderShelfUseCase,
+	exportShelvesUC *shelfuc.ExportShelvesUseCase,
+) *ShelfHandler {
+	return &ShelfHandler{
+		listShelvesUC:     listShelvesUC,
+		createShelfUC:     createShelfUC,
+		renameShelfUC:     renameShelfUC,
+		deleteShelfUC:     deleteShelfUC,
+		reorderShelvesUC:  reorderShelvesUC,
+		getShelfUC:        getShelfUC,
+		addToShelfUC:      addToShelfUC,
+		removeFromShelfUC: removeFromShelfUC,
+		reorderShelfUC:    reorderShelfUC,
+		exportShelvesUC:   exportShelvesUC,
+	}
+}
+
+// ListShelves lists the reader's shelves in order, with item counts.
+// GET /me/shelves
+func (h *ShelfHandler) ListShelves(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	shelves, err := h.listShelvesUC.Execute(c.Request.Context())
+	if err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"shelves": shelves}})
+}
+
+// CreateShelf adds a custom shelf.
+// POST /me/shelves
+// Body: { "name": "Favourites" }
+func (h *ShelfHandler) CreateShelf(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s, err := h.createShelfUC.Execute(c.Request.Context(), req.Name)
+	if err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{"shelf": s}})
+}
+
+// RenameShelf renames a custom shelf.
+// PATCH /me/shelves/:shelf
+// Body: { "name": "Favourites" }
+func (h *ShelfHandler) RenameShelf(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s, err := h.renameShelfUC.Execute(c.Request.Context(), c.Param("shelf"), req.Name)
+	if err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"shelf": s}})
+}
+
+// DeleteShelf deletes a custom shelf and its items.
+// DELETE /me/shelves/:shelf
+func (h *ShelfHandler) DeleteShelf(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	if err := h.deleteShelfUC.Execute(c.Request.Context(), c.Param("shelf")); err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "Shelf deleted"}})
+}
+
+// ReorderShelves sets the order of the reader's shelves.
+// PUT /me/shelves/order
+// Body: { "shelf_ids": ["<id>", ...] } listing every shelf
+func (h *ShelfHandler) ReorderShelves(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		ShelfIDs []string `json:"shelf_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shelves, err := h.reorderShelvesUC.Execute(c.Request.Context(), req.ShelfIDs)
+	if err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"shelves": shelves}})
+}
+
+// GetShelf returns a shelf with its items in order. :shelf is a shelf ID or
+// one of want_to_read, reading and finished.
+// GET /me/shelves/:shelf
+func (h *ShelfHandler) GetShelf(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	contents, err := h.getShelfUC.Execute(c.Request.Context(), c.Param("shelf"))
+	if err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": contents})
+}
+
+// AddToShelf puts a book on a shelf.
+// POST /me/shelves/:shelf/items
+// Body: { "book_id": "<local book id>", "rating": 4 }
+// or: { "external_id": "<Google Books volume id>", "title": "...", "author": "...", "cover_url": "...", "isbn": "...", "rating": 4 }
+// rating is optional; adding a book that is already on the shelf updates it.
+func (h *ShelfHandler) AddToShelf(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		BookID     string `json:"book_id"`
+		ExternalID string `json:"external_id"`
+		Source     string `json:"source"`
+		Title      string `json:"title"`
+		Author     string `json:"author"`
+		CoverUrl   string `json:"cover_url"`
+		ISBN       string `json:"isbn"`
+		Rating     int    `json:"rating"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item := &shelf.Item{
+		Source:     req.Source,
+		ExternalID: req.ExternalID,
+		Title:      req.Title,
+		Author:     req.Author,
+		CoverUrl:   req.CoverUrl,
+		ISBN:       req.ISBN,
+		Rating:     req.Rating,
+	}
+	if req.BookID != "" {
+		item.Source, item.ExternalID = shelf.SourceLocal, req.BookID
+	}
+
+	saved, err := h.addToShelfUC.Execute(c.Request.Context(), c.Param("shelf"), item)
+	if err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{"item": saved}})
+}
+
+// RemoveFromShelf takes an item off a shelf.
+// DELETE /me/shelves/:shelf/items/:item_id
+func (h *ShelfHandler) RemoveFromShelf(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	if err := h.removeFromShelfUC.Execute(c.Request.Context(), c.Param("shelf"), c.Param("item_id")); err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "Removed from shelf"}})
+}
+
+// ReorderShelf sets the order of the items on a shelf.
+// PUT /me/shelves/:shelf/order
+// Body: { "item_ids": ["<id>", ...] } listing every item on the shelf
+func (h *ShelfHandler) ReorderShelf(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	var req struct {
+		ItemIDs []string `json:"item_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contents, err := h.reorderShelfUC.Execute(c.Request.Context(), c.Param("shelf"), req.ItemIDs)
+	if err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": contents})
+}
+
+// ExportShelves downloads shelved books as a Goodreads-compatible CSV.
+// GET /me/shelves/export?shelf=<id or built-in kind>
+// Without shelf every shelved book is exported.
+func (h *ShelfHandler) ExportShelves(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	export, err := h.exportShelvesUC.Execute(c.Request.Context(), c.Query("shelf"))
+	if err != nil {
+		writeShelfError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	c.Data(http.StatusOK, export.ContentType, export.Body)
+}
+
+func writeShelfError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, shelf.ErrShelfNotFound), errors.Is(err, shelf.ErrItemNotFound), errors.Is(err, book.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, shelf.ErrShelfExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, shelf.ErrBuiltinShelf):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, shelf.ErrInvalidShelf), errors.Is(err, shelf.ErrInvalidItem),
+		errors.Is(err, shelf.ErrInvalidOrder), errors.Is(err, shelf.ErrTooManyShelves):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -18,6 +19,7 @@ type UserHandler struct {
 	getAllUsersUseCase  *usecase.GetAllUsers
 	getUsersByIDUseCase *usecase.GetUserByIDUseCase
 	loginUseCase        *usecase.LoginUseCase
+	setUserRoleUseCase  *usecase.SetUserRoleUseCase
 }
 
 func NewUserHandler(
@@ -27,6 +29,7 @@ func NewUserHandler(
 	getAllUsersUC *usecase.GetAllUsers,
 	getUserByIDUC *usecase.GetUserByIDUseCase,
 	loginUC *usecase.LoginUseCase,
+	setUserRoleUC *usecase.SetUserRoleUseCase,
 ) *UserHandler {
 	return &UserHandler{
 		createUserUseCase:   createUserUC,
@@ -35,6 +38,7 @@ func NewUserHandler(
 		getAllUsersUseCase:  getAllUsersUC,
 		getUsersByIDUseCase: getUserByIDUC,
 		loginUseCase:        loginUC,
+		setUserRoleUseCase:  setUserRoleUC,
 	}
 }
 
@@ -72,8 +76,8 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	}
 	print("User struct prepared.")
 	createdUser, err := h.createUserUseCase.Execute(newUser)
-	if err != nil || createdUser.ID == 0 {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err != nil {
+		writeUserError(c, err)
 		return
 	}
 	println("Created Username: " + createdUser.Username)
@@ -114,8 +118,11 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	
 	userToDelete := bookUser.User{ID: parsedID}
 
-	_, err = h.deleteUserUseCase.Execute(userToDelete)
+	_, err = h.deleteUserUseCase.Execute(c.Request.Context(), userToDelete)
 	if err != nil {
+		if writeIdentityError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -133,18 +140,25 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	// Accept partial updates, map only allowed fields
+	// Reject before loading the target so callers cannot probe other users.
+	principal, ok := requirePrincipal(c)
+	if !ok {
+		return
+	}
+	if !principal.CanActAs(parsedID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	// Accept partial updates, map only allowed fields. Points, streaks and
+	// read counts are computed from activity and cannot be set here.
 	var input struct {
 		Username     *string `json:"username"`
 		Email        *string `json:"email"`
 		Password     *string `json:"passwordHash"`
 		ProfileImage *string `json:"profile_image"`
-		// numeric fields are optional; if provided, use them
-		BooksReadCount *int `json:"books_read_count"`
-		ReadingStreak  *int `json:"reading_streak"`
-		Points         *int `json:"points"`
-		// lastReadDate optional RFC3339
-		LastReadDate *string `json:"last_read_date"`
+		// IANA timezone such as "Africa/Addis_Ababa"
+		Timezone *string `json:"timezone"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -175,6 +189,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		ReadingStreak:  prev.ReadingStreak,
 		LastReadDate:   prev.LastReadDate,
 		Points:         prev.Points,
+		Timezone:       prev.Timezone,
 	}
 
 	// Apply changes if provided
@@ -200,27 +215,20 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 			updated.ProfileImage = input.ProfileImage
 		}
 	}
-	if input.BooksReadCount != nil {
-		updated.BooksReadCount = *input.BooksReadCount
-	}
-	if input.ReadingStreak != nil {
-		updated.ReadingStreak = *input.ReadingStreak
-	}
-	if input.Points != nil {
-		updated.Points = *input.Points
-	}
-	if input.LastReadDate != nil {
-		if *input.LastReadDate == "" {
-			updated.LastReadDate = nil
+	if input.Timezone != nil {
+		if *input.Timezone == "" {
+			updated.Timezone = bookUser.DefaultTimezone
 		} else {
-			t, err := time.Parse(time.RFC3339, *input.LastReadDate)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lastReadDate format, must be RFC3339"})
+			if _, err := time.LoadLocation(*input.Timezone); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timezone, must be an IANA name such as Europe/London"})
 				return
 			}
-			updated.LastReadDate = &t
+			updated.Timezone = *input.Timezone
 		}
 	}
+	if updated.Timezone == "" {
+		updated.Timezone = bookUser.DefaultTimezone
+	}
 
 	// Always update UpdatedAt to now
 	updated.UpdatedAt = time.Now()
@@ -228,9 +236,9 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		updated.CreatedAt = time.Now()
 	}
 
-	user, err := h.updateUserUseCase.Execute(updated)
+	user, err := h.updateUserUseCase.Execute(c.Request.Context(), updated)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeUserError(c, err)
 		return
 	}
 
@@ -284,4 +292,48 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 			"token": token,
 		},
 	})
-}
\ No newline at end of file
+}
+
+// SetUserRole makes a user an admin or a regular user. Admins only.
+// PUT /users/:id/role
+// Body: { "role": "user" | "admin" }
+func (h *UserHandler) SetUserRole(c *gin.Context) {
+	if _, ok := requirePrincipal(c); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.setUserRoleUseCase.Execute(c.Request.Context(), id, req.Role); err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func writeUserError(c *gin.Context, err error) {
+	if writeIdentityError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, bookUser.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, bookUser.ErrAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "email is already in use"})
+	case errors.Is(err, bookUser.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterActivityRoutes(r *gin.Engine, activityHandler *handlers.ActivityHandler, achievementHandler *handlers.AchievementHandler) {
+	me := r.Group("/me")
+	me.Use(middleware.AuthMiddleware)
+
+	me.GET("/stats", activityHandler.GetMyStats)
+	me.GET("/achievements", achievementHandler.GetMyAchievements)
+}
@@ -1,5 +1,6 @@
 package router
 import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
 	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
 	"github.com/gin-gonic/gin"
 )
@@ -11,7 +12,7 @@ func RegisterAuthRoutes(r *gin.Engine, userHandler *handlers.UserHandler) {
 	{
 		auth.POST("/login", userHandler.LoginUser)
 		auth.POST("/signup", userHandler.CreateUser)
-		auth.POST("/users/update/:id", userHandler.UpdateUser)
+		auth.POST("/users/update/:id", middleware.AuthMiddleware, userHandler.UpdateUser)
 	}
 	
 }
\ No newline at end of file
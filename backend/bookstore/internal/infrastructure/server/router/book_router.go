@@ -18,8 +18,10 @@ func RegisterBookRoutes(r *gin.Engine, bookHandler *handlers.BookHandler) {
 	// Note: Gin uses ":id" for path parameters, not "{id}"
 	books.GET("/trending", bookHandler.GetTrendingBooks) // Add this before :id to avoid conflict
 	books.POST("", bookHandler.CreateBook)
+	books.POST("/import", bookHandler.ImportBook)
 	books.GET("", bookHandler.GetAllBooks)
 	books.GET("/:id", bookHandler.GetBookByID)
+	books.GET("/:id/download", bookHandler.DownloadBook)
 	books.PUT("/:id", bookHandler.UpdateBook)
 	books.DELETE("/:id", bookHandler.DeleteBook)
 	books.POST("/upload", bookHandler.CreateBook)
@@ -15,5 +15,11 @@ func RegisterChatRoutes(r *gin.Engine, chatHandler *handlers.ChatHandler) {
 	chat.POST("/responses/:id", chatHandler.GetChatResponses)
 	chat.POST("/questions/short-answer/:id", chatHandler.GetShortAnswerQuestion)
 	chat.POST("/questions/true-false/:id", chatHandler.GetTrueFalseQuestion)
+	chat.GET("/cache/stats", chatHandler.GetCacheStats)
 	r.GET("/stream", chatHandler.StreamChatResponses)
+
+	quizzes := r.Group("/quizzes")
+	quizzes.Use(middleware.AuthMiddleware)
+
+	quizzes.POST("/short-answer/:id/grade", chatHandler.GradeShortAnswer)
 }
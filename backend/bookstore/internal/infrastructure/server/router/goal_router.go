@@ -0,0 +1,16 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterGoalRoutes(r *gin.Engine, goalHandler *handlers.GoalHandler) {
+	me := r.Group("/me")
+	me.Use(middleware.AuthMiddleware)
+
+	me.GET("/goals", goalHandler.GetMyGoals)
+	me.POST("/goals", goalHandler.CreateGoal)
+	me.DELETE("/goals/:id", goalHandler.DeleteGoal)
+}
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterImportRoutes(r *gin.Engine, importHandler *handlers.ImportHandler) {
+	imports := r.Group("/me/imports")
+	imports.Use(middleware.AuthMiddleware)
+
+	imports.POST("", importHandler.StartImport)
+	imports.GET("", importHandler.ListImports)
+	imports.GET("/:id", importHandler.GetImport)
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterLeaderboardRoutes(r *gin.Engine, leaderboardHandler *handlers.LeaderboardHandler) {
+	r.GET("/leaderboards", middleware.AuthMiddleware, leaderboardHandler.GetLeaderboard)
+
+	me := r.Group("/me")
+	me.Use(middleware.AuthMiddleware)
+
+	me.GET("/friends", leaderboardHandler.ListFriends)
+	me.POST("/friends/:user_id", leaderboardHandler.AddFriend)
+	me.DELETE("/friends/:user_id", leaderboardHandler.RemoveFriend)
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterLicenseRoutes(r *gin.Engine, licenseHandler *handlers.LicenseHandler) {
+	admin := r.Group("/admin/licenses")
+	admin.Use(middleware.AuthMiddleware)
+
+	admin.GET("", licenseHandler.ListLicenses)
+	admin.POST("", licenseHandler.CreateLicense)
+	admin.DELETE("/:id", licenseHandler.DeleteLicense)
+	admin.GET("/:id/members", licenseHandler.ListLicenseMembers)
+	admin.POST("/:id/members", licenseHandler.AddLicenseMember)
+	admin.DELETE("/:id/members/:user_id", licenseHandler.RemoveLicenseMember)
+}
@@ -12,6 +12,10 @@ func RegisterNoteRoutes(r *gin.Engine, noteHandler *handlers.NoteHandler) {
 
 	notes.POST("", noteHandler.CreateNote)
 	notes.POST("/ai", noteHandler.GenerateAINote)
+	notes.POST("/sync", noteHandler.SyncNotes)
+	notes.GET("/search", noteHandler.SearchNotes)
+	notes.GET("/export", noteHandler.ExportNotes)
 	notes.GET("/:book_id", noteHandler.GetNotes)
+	notes.PATCH("/:note_id", noteHandler.UpdateNote)
 	notes.DELETE("/:note_id", noteHandler.DeleteNote)
 }
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/opdsfeed"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOPDSRoutes serves the catalog to reader apps. They authenticate
+// with HTTP Basic or a token rather than the API's bearer header, so books
+// are downloaded through an OPDS route with the same handler as
+// /books/:id/download.
+func RegisterOPDSRoutes(r *gin.Engine, opdsHandler *handlers.OPDSHandler, bookHandler *handlers.BookHandler) {
+	catalog := r.Group("/opds")
+	catalog.Use(middleware.OPDSAuthMiddleware(opdsHandler.Authenticate))
+
+	catalog.GET("", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, opdsfeed.PathV1+"/")
+	})
+	catalog.GET("/v1/*feed", opdsHandler.GetAtomFeed)
+	catalog.GET("/v2/*feed", opdsHandler.GetJSONFeed)
+	catalog.GET("/opensearch.xml", opdsHandler.GetOpenSearch)
+	catalog.GET("/books/:id/download", bookHandler.DownloadBook)
+}
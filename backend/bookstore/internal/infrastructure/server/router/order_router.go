@@ -0,0 +1,26 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterOrderRoutes(r *gin.Engine, orderHandler *handlers.OrderHandler) {
+	me := r.Group("/me")
+	me.Use(middleware.AuthMiddleware)
+
+	me.GET("/cart", orderHandler.GetCart)
+	me.POST("/cart/items", orderHandler.AddToCart)
+	me.DELETE("/cart/items/:book_id", orderHandler.RemoveFromCart)
+	me.POST("/cart/checkout", orderHandler.Checkout)
+
+	me.GET("/orders", orderHandler.ListOrders)
+	me.GET("/orders/:id", orderHandler.GetOrder)
+	me.POST("/orders/:id/pay", orderHandler.PayOrder)
+
+	admin := r.Group("/admin/orders")
+	admin.Use(middleware.AuthMiddleware)
+
+	admin.POST("/:id/refund", orderHandler.RefundOrder)
+}
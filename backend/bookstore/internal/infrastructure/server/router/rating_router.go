@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRatingRoutes(r *gin.Engine, ratingHandler *handlers.RatingHandler) {
+	reviews := r.Group("/books/:id/reviews")
+	reviews.Use(middleware.AuthMiddleware)
+
+	reviews.GET("", ratingHandler.ListReviews)
+	reviews.PUT("/me", ratingHandler.SubmitReview)
+	reviews.DELETE("/me", ratingHandler.DeleteReview)
+	reviews.POST("/:review_id/report", ratingHandler.ReportReview)
+
+	admin := r.Group("/admin/reviews")
+	admin.Use(middleware.AuthMiddleware)
+
+	admin.GET("/reported", ratingHandler.ListReportedReviews)
+	admin.PUT("/:review_id/moderation", ratingHandler.ModerateReview)
+}
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterReadingRoutes(r *gin.Engine, readingHandler *handlers.ReadingHandler) {
+	r.PUT("/books/:id/progress", middleware.AuthMiddleware, readingHandler.UpdateProgress)
+
+	me := r.Group("/me")
+	me.Use(middleware.AuthMiddleware)
+
+	me.GET("/reading", readingHandler.GetMyReading)
+}
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRecommendationRoutes(r *gin.Engine, recommendationHandler *handlers.RecommendationHandler) {
+	me := r.Group("/me")
+	me.Use(middleware.AuthMiddleware)
+
+	me.GET("/recommendations", recommendationHandler.GetMyRecommendations)
+}
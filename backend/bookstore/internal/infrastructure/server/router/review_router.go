@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterReviewRoutes(r *gin.Engine, reviewHandler *handlers.ReviewHandler) {
+	review := r.Group("/review")
+	review.Use(middleware.AuthMiddleware)
+
+	review.GET("/due", reviewHandler.GetDueCards)
+	review.GET("/decks", reviewHandler.GetDecks)
+	review.POST("/cards/quiz", reviewHandler.CreateCardsFromQuiz)
+	review.POST("/cards/notes", reviewHandler.CreateCardsFromNotes)
+	review.POST("/:card_id", reviewHandler.ReviewCard)
+}
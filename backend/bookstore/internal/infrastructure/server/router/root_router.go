@@ -5,13 +5,26 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine, bookHandler *handlers.BookHandler, userHandler *handlers.UserHandler, chatRouter *handlers.ChatHandler, noteHandler *handlers.NoteHandler) {
-	// Serve static files from uploads directory
-	r.Static("/uploads", "./uploads")
+func SetupRoutes(r *gin.Engine, bookHandler *handlers.BookHandler, userHandler *handlers.UserHandler, chatRouter *handlers.ChatHandler, noteHandler *handlers.NoteHandler, reviewHandler *handlers.ReviewHandler, readingHandler *handlers.ReadingHandler, activityHandler *handlers.ActivityHandler, achievementHandler *handlers.AchievementHandler, leaderboardHandler *handlers.LeaderboardHandler, goalHandler *handlers.GoalHandler, shelfHandler *handlers.ShelfHandler, importHandler *handlers.ImportHandler, ratingHandler *handlers.RatingHandler, recommendationHandler *handlers.RecommendationHandler, opdsHandler *handlers.OPDSHandler, orderHandler *handlers.OrderHandler, licenseHandler *handlers.LicenseHandler) {
+	// Serve cover images from the uploads directory; book files are only
+	// served by the download routes
+	r.GET("/uploads/:name", handlers.ServeCover)
 
 	RegisterBookRoutes(r, bookHandler)
 	RegisterUserRoutes(r, userHandler)
 	RegisterAuthRoutes(r, userHandler)
 	RegisterChatRoutes(r, chatRouter)
 	RegisterNoteRoutes(r, noteHandler)
+	RegisterReviewRoutes(r, reviewHandler)
+	RegisterReadingRoutes(r, readingHandler)
+	RegisterActivityRoutes(r, activityHandler, achievementHandler)
+	RegisterLeaderboardRoutes(r, leaderboardHandler)
+	RegisterGoalRoutes(r, goalHandler)
+	RegisterShelfRoutes(r, shelfHandler)
+	RegisterImportRoutes(r, importHandler)
+	RegisterRatingRoutes(r, ratingHandler)
+	RegisterRecommendationRoutes(r, recommendationHandler)
+	RegisterOPDSRoutes(r, opdsHandler, bookHandler)
+	RegisterOrderRoutes(r, orderHandler)
+	RegisterLicenseRoutes(r, licenseHandler)
 }
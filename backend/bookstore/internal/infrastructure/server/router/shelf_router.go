@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/bereke1t2/bookstore/internal/infrastructure/middleware"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/server/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterShelfRoutes(r *gin.Engine, shelfHandler *handlers.ShelfHandler) {
+	shelves := r.Group("/me/shelves")
+	shelves.Use(middleware.AuthMiddleware)
+
+	shelves.GET("", shelfHandler.ListShelves)
+	shelves.POST("", shelfHandler.CreateShelf)
+	shelves.PUT("/order", shelfHandler.ReorderShelves)
+	shelves.GET("/export", shelfHandler.ExportShelves)
+	shelves.GET("/:shelf", shelfHandler.GetShelf)
+	shelves.PATCH("/:shelf", shelfHandler.RenameShelf)
+	shelves.DELETE("/:shelf", shelfHandler.DeleteShelf)
+	shelves.POST("/:shelf/items", shelfHandler.AddToShelf)
+	shelves.DELETE("/:shelf/items/:item_id", shelfHandler.RemoveFromShelf)
+	shelves.PUT("/:shelf/order", shelfHandler.ReorderShelf)
+}
@@ -15,6 +15,7 @@ func RegisterUserRoutes(r *gin.Engine, userHandler *handlers.UserHandler) {
 		users.GET("", userHandler.GetAllUsers)
 		users.GET("/:id", userHandler.GetUserByID)
 		users.PUT("/:id", userHandler.UpdateUser)
+		users.PUT("/:id/role", userHandler.SetUserRole)
 		users.DELETE("/:id", userHandler.DeleteUser)
 	}
 }
\ No newline at end of file
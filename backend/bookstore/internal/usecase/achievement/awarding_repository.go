@@ -0,0 +1,45 @@
+package achievement
+
+import (
+	"log"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+)
+
+// AwardingActivityRepository is an activity.Repository that evaluates the
+// achievement rules after every newly recorded event, so badges follow the
+// same domain events as points and streaks.
+type AwardingActivityRepository struct {
+	next     activity.Repository
+	evaluate *EvaluateAchievementsUseCase
+}
+
+var _ activity.Repository = (*AwardingActivityRepository)(nil)
+
+func NewAwardingActivityRepository(next activity.Repository, evaluate *EvaluateAchievementsUseCase) *AwardingActivityRepository {
+	return &AwardingActivityRepository{next: next, evaluate: evaluate}
+}
+
+func (r *AwardingActivityRepository) Record(e *activity.Event) (bool, error) {
+	recorded, err := r.next.Record(e)
+	if err != nil || !recorded {
+		return recorded, err
+	}
+	awards, err := r.evaluate.Execute(e.UserID)
+	if err != nil {
+		log.Printf("achievements: evaluate for user %d: %v", e.UserID, err)
+	}
+	for _, a := range awards {
+		log.Printf("achievements: user %d earned %s", a.UserID, a.BadgeID)
+	}
+	return true, nil
+}
+
+func (r *AwardingActivityRepository) GetStats(userID int) (*activity.Stats, error) {
+	return r.next.GetStats(userID)
+}
+
+func (r *AwardingActivityRepository) GetStreakDays(userID int, limit int) ([]time.Time, error) {
+	return r.next.GetStreakDays(userID, limit)
+}
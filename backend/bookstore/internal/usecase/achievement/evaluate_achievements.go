@@ -0,0 +1,41 @@
+package achievement
+
+import (
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/achievement"
+)
+
+// EvaluateAchievementsUseCase awards every badge whose rule a user now meets.
+type EvaluateAchievementsUseCase struct {
+	repo   achievement.Repository
+	badges []achievement.Badge
+}
+
+func NewEvaluateAchievementsUseCase(repo achievement.Repository, badges []achievement.Badge) *EvaluateAchievementsUseCase {
+	return &EvaluateAchievementsUseCase{repo: repo, badges: badges}
+}
+
+// Execute returns the badges newly awarded to the user.
+func (uc *EvaluateAchievementsUseCase) Execute(userID int) ([]*achievement.Award, error) {
+	metrics, err := uc.repo.GetMetrics(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var awarded []*achievement.Award
+	now := time.Now()
+	for _, b := range uc.badges {
+		if !b.Earned(metrics) {
+			continue
+		}
+		ok, err := uc.repo.Award(userID, b.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			awarded = append(awarded, &achievement.Award{UserID: userID, BadgeID: b.ID, AwardedAt: now})
+		}
+	}
+	return awarded, nil
+}
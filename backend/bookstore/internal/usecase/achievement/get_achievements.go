@@ -0,0 +1,61 @@
+package achievement
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/achievement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type GetAchievementsUseCase struct {
+	repo   achievement.Repository
+	badges []achievement.Badge
+}
+
+func NewGetAchievementsUseCase(repo achievement.Repository, badges []achievement.Badge) *GetAchievementsUseCase {
+	return &GetAchievementsUseCase{repo: repo, badges: badges}
+}
+
+// Execute lists every badge with whether the authenticated user has earned
+// it and their progress towards it. Badges removed from the config but
+// already awarded are still listed.
+func (uc *GetAchievementsUseCase) Execute(ctx context.Context) ([]*achievement.Achievement, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := uc.repo.GetMetrics(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	awards, err := uc.repo.ListAwards(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	awarded := make(map[string]*achievement.Award, len(awards))
+	for _, a := range awards {
+		awarded[a.BadgeID] = a
+	}
+
+	achievements := make([]*achievement.Achievement, 0, len(uc.badges))
+	for _, b := range uc.badges {
+		a := &achievement.Achievement{Badge: b, Progress: min(metrics[b.Metric], b.Threshold)}
+		if award, ok := awarded[b.ID]; ok {
+			a.Earned = true
+			a.AwardedAt = &award.AwardedAt
+			delete(awarded, b.ID)
+		}
+		achievements = append(achievements, a)
+	}
+	for _, award := range awards {
+		if _, retired := awarded[award.BadgeID]; retired {
+			achievements = append(achievements, &achievement.Achievement{
+				Badge:     achievement.Badge{ID: award.BadgeID, Name: award.BadgeID},
+				Earned:    true,
+				AwardedAt: &award.AwardedAt,
+			})
+		}
+	}
+	return achievements, nil
+}
@@ -0,0 +1,38 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type GetStatsUseCase struct {
+	repo activity.Repository
+}
+
+func NewGetStatsUseCase(repo activity.Repository) *GetStatsUseCase {
+	return &GetStatsUseCase{repo: repo}
+}
+
+// Execute returns the authenticated user's counters. The stored streak is
+// only updated when the user reads, so it is recomputed for today here.
+func (uc *GetStatsUseCase) Execute(ctx context.Context) (*activity.Stats, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.repo.GetStats(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	days, err := uc.repo.GetStreakDays(p.UserID, stats.ReadingStreak+activity.GraceDays+2)
+	if err != nil {
+		return nil, err
+	}
+	today := activity.LocalDate(time.Now(), activity.Location(stats.Timezone))
+	stats.ReadingStreak = activity.Streak(days, today, activity.GraceDays)
+	return stats, nil
+}
@@ -0,0 +1,19 @@
+package activity
+
+import (
+	"log"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+)
+
+// Record awards the points in e. Usecases call it once their own work is
+// saved, and the reader keeps that work even if the award fails, so the
+// error is only logged. A nil repo awards nothing.
+func Record(repo activity.Repository, e *activity.Event) {
+	if repo == nil {
+		return
+	}
+	if _, err := repo.Record(e); err != nil {
+		log.Printf("activity: record %s for user %d: %v", e.Type, e.UserID, err)
+	}
+}
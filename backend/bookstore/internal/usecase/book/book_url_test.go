@@ -0,0 +1,55 @@
+package book
+
+import (
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
+)
+
+func price(p float32) *float32 { return &p }
+
+func TestBookURLOnlyShownToReaders(t *testing.T) {
+	repo := newFakeBookRepository(
+		&book.Book{ID: "dune", Title: "Dune", Price: price(5), UploadedBy: 1, BookURL: "/uploads/books/dune.pdf"},
+		&book.Book{ID: "emma", Title: "Emma", UploadedBy: 1, BookURL: "/uploads/books/emma.pdf"},
+	)
+	access := entitlementuc.NewCheckAccessUseCase(&fakeEntitlementRepository{purchased: map[int][]string{2: {"dune"}}}, repo)
+	list := NewGetAllBooksUseCase(repo, access)
+	get := NewGetBookByIDUseCase(repo, access)
+
+	tests := []struct {
+		name     string
+		userID   int
+		roles    []string
+		readDune bool
+	}{
+		{"uploader", 1, nil, true},
+		{"buyer", 2, nil, true},
+		{"admin", 9, []string{identity.RoleUser, identity.RoleAdmin}, true},
+		{"other user", 3, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := asUser(tt.userID, tt.roles...)
+			books, err := list.Execute(ctx, book.SortDefault)
+			if err != nil {
+				t.Fatal(err)
+			}
+			detail, err := get.Execute(ctx, "dune")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, b := range append(books, detail) {
+				readable := b.ID == "emma" || tt.readDune
+				if b.Owned == nil || *b.Owned != readable {
+					t.Errorf("%s: owned = %v, want %v", b.ID, b.Owned, readable)
+				}
+				if shown := b.BookURL != ""; shown != readable {
+					t.Errorf("%s: book_url %q shown = %v, want %v", b.ID, b.BookURL, shown, readable)
+				}
+			}
+		})
+	}
+}
@@ -2,19 +2,24 @@ package book
 
 import (
 	"context"
+	"time"
 
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
 	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
 	"github.com/bereke1t2/bookstore/internal/infrastructure/database/supabase"
+	activityuc "github.com/bereke1t2/bookstore/internal/usecase/activity"
 	"github.com/google/uuid"
 )
 
 type CreateBook struct {
 	repo     book.BookRepository
 	supabase *supabase.SupabaseClient
+	activity activity.Repository
 }
 
-func NewCreateBookUseCase(repo book.BookRepository, supabase *supabase.SupabaseClient) *CreateBook {
-	return &CreateBook{repo: repo, supabase: supabase}
+func NewCreateBookUseCase(repo book.BookRepository, supabase *supabase.SupabaseClient, activity activity.Repository) *CreateBook {
+	return &CreateBook{repo: repo, supabase: supabase, activity: activity}
 }
 
 func (uc *CreateBook) Execute(ctx context.Context, b *book.Book) (*book.Book, error) {
@@ -23,6 +28,9 @@ func (uc *CreateBook) Execute(ctx context.Context, b *book.Book) (*book.Book, er
 		b.ID = UUIDGenerator()
 	}
 
+	// Ratings come from user reviews only
+	b.Rating, b.RatingCount = 0, 0
+
 	// Local storage fallback: Supabase DNS (aeavumvbwryxbcfksepd.supabase.co) is failing.
 	// We keep the files in the 'uploads' folder and return web-relative paths.
 
@@ -32,15 +40,25 @@ func (uc *CreateBook) Execute(ctx context.Context, b *book.Book) (*book.Book, er
 	}
 
 	if b.BookURL != "" && !isValidURL(b.BookURL) {
-		// Convert "uploads/xxx.pdf" to "/uploads/xxx.pdf"
+		// Convert "uploads/books/xxx.pdf" to "/uploads/books/xxx.pdf"
 		b.BookURL = "/" + b.BookURL
 	}
 
+	p, hasUploader := identity.FromContext(ctx)
+	if hasUploader {
+		b.UploadedBy = p.UserID
+	}
+
 	// Persist book to database
 	createdBook, err := uc.repo.CreateBook(b)
 	if err != nil {
 		return nil, err
 	}
+
+	// Credit the uploader
+	if hasUploader {
+		activityuc.Record(uc.activity, activity.BookShared(p.UserID, createdBook.ID, time.Now()))
+	}
 	return createdBook, nil
 }
 
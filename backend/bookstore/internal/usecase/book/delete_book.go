@@ -1,7 +1,10 @@
 package book
 
 import (
+	"context"
+
 	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
 )
 
 type DeleteBook struct {
@@ -11,11 +14,31 @@ type DeleteBook struct {
 func NewDeleteBookUsecase(repo book.BookRepository) *DeleteBook {
 	return &DeleteBook{repo: repo}
 }
-func (uc *DeleteBook) Execute(id string) error {
-	err := uc.repo.DeleteBook(id)
-	if err != nil {
+
+// Execute deletes a book. Only its uploader or an admin may delete it.
+func (uc *DeleteBook) Execute(ctx context.Context, id string) error {
+	if _, err := authorizeBookOwner(ctx, uc.repo, id); err != nil {
 		return err
 	}
-	
-	return nil
-}
\ No newline at end of file
+	return uc.repo.DeleteBook(id)
+}
+
+// authorizeBookOwner returns the book if the caller uploaded it or is an
+// admin. Books without a recorded uploader can only be changed by admins.
+func authorizeBookOwner(ctx context.Context, repo book.BookRepository, id string) (*book.Book, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := repo.GetBookByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, book.ErrBookNotFound
+	}
+	if !p.IsAdmin() && (b.UploadedBy == 0 || b.UploadedBy != p.UserID) {
+		return nil, identity.ErrForbidden
+	}
+	return b, nil
+}
@@ -0,0 +1,54 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+func asUser(userID int, roles ...string) context.Context {
+	if len(roles) == 0 {
+		roles = []string{identity.RoleUser}
+	}
+	return identity.WithPrincipal(context.Background(), identity.Principal{UserID: userID, Roles: roles})
+}
+
+func TestOnlyUploaderOrAdminChangesABook(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		book *book.Book
+		want error
+	}{
+		{"uploader", asUser(1), &book.Book{ID: "b", UploadedBy: 1}, nil},
+		{"admin", asUser(9, identity.RoleUser, identity.RoleAdmin), &book.Book{ID: "b", UploadedBy: 1}, nil},
+		{"other user", asUser(2), &book.Book{ID: "b", UploadedBy: 1}, identity.ErrForbidden},
+		{"no uploader recorded", asUser(2), &book.Book{ID: "b"}, identity.ErrForbidden},
+		{"anonymous", context.Background(), &book.Book{ID: "b", UploadedBy: 1}, identity.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeBookRepository(tt.book)
+			if _, err := NewUpdateBookUseCase(repo).Execute(tt.ctx, "b", &book.Book{Title: "x"}); !errors.Is(err, tt.want) {
+				t.Errorf("update: got %v, want %v", err, tt.want)
+			}
+			err := NewDeleteBookUsecase(repo).Execute(tt.ctx, "b")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("delete: got %v, want %v", err, tt.want)
+			}
+			if _, kept := repo.books["b"]; kept != (tt.want != nil) {
+				t.Errorf("book kept = %v after delete returned %v", kept, err)
+			}
+		})
+	}
+}
+
+func TestDeleteMissingBook(t *testing.T) {
+	err := NewDeleteBookUsecase(newFakeBookRepository()).Execute(asUser(1), "missing")
+	if !errors.Is(err, book.ErrBookNotFound) {
+		t.Errorf("got %v, want ErrBookNotFound", err)
+	}
+}
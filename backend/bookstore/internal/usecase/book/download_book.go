@@ -0,0 +1,50 @@
+package book
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/trending"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
+	trendinguc "github.com/bereke1t2/bookstore/internal/usecase/trending"
+)
+
+type DownloadBook struct {
+	repo     book.BookRepository
+	trending trending.Repository
+	access   *entitlementuc.CheckAccessUseCase
+}
+
+func NewDownloadBookUseCase(repo book.BookRepository, trending trending.Repository, access *entitlementuc.CheckAccessUseCase) *DownloadBook {
+	return &DownloadBook{repo: repo, trending: trending, access: access}
+}
+
+// Execute returns the book the authenticated user is downloading and counts
+// the download towards the book's trending score. It returns
+// ErrNotEntitled for paid books the user may not read.
+func (uc *DownloadBook) Execute(ctx context.Context, id string) (*book.Book, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := uc.repo.GetBookByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil || b.BookURL == "" {
+		return nil, book.ErrBookNotFound
+	}
+	if err := uc.access.Authorize(ctx, b); err != nil {
+		return nil, err
+	}
+
+	trendinguc.Record(uc.trending, &trending.Event{
+		BookID:     b.ID,
+		UserID:     p.UserID,
+		Kind:       trending.KindDownload,
+		OccurredAt: time.Now(),
+	})
+	return b, nil
+}
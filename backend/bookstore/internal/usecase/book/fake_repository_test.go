@@ -0,0 +1,133 @@
+package book
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+)
+
+// fakeBookRepository keeps books in memory, listed by ID.
+type fakeBookRepository struct {
+	books map[string]*book.Book
+}
+
+func newFakeBookRepository(books ...*book.Book) *fakeBookRepository {
+	f := &fakeBookRepository{books: map[string]*book.Book{}}
+	for _, b := range books {
+		f.books[b.ID] = b
+	}
+	return f
+}
+
+func (f *fakeBookRepository) CreateBook(b *book.Book) (*book.Book, error) {
+	f.books[b.ID] = b
+	return b, nil
+}
+
+func (f *fakeBookRepository) GetBookByID(id string) (*book.Book, error) {
+	return f.find(func(b *book.Book) bool { return b.ID == id }), nil
+}
+
+func (f *fakeBookRepository) UpdateBook(b *book.Book) (*book.Book, error) {
+	f.books[b.ID] = b
+	return b, nil
+}
+
+func (f *fakeBookRepository) DeleteBook(id string) error {
+	delete(f.books, id)
+	return nil
+}
+
+func (f *fakeBookRepository) GetAllBooks() ([]*book.Book, error) {
+	return f.ListBooks(book.SortDefault)
+}
+
+func (f *fakeBookRepository) ListBooks(sortBy string) ([]*book.Book, error) {
+	return f.FindBooks(book.Filter{Sort: sortBy})
+}
+
+func (f *fakeBookRepository) FindBooks(filter book.Filter) ([]*book.Book, error) {
+	books := []*book.Book{}
+	for _, b := range f.books {
+		if filter.Title != "" && book.NormalizeTitle(b.Title) != book.NormalizeTitle(filter.Title) {
+			continue
+		}
+		copied := *b
+		books = append(books, &copied)
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	return books, nil
+}
+
+func (f *fakeBookRepository) ListCategories() ([]book.Category, error) {
+	return nil, nil
+}
+
+func (f *fakeBookRepository) GetBookByISBN(isbn13 string) (*book.Book, error) {
+	return f.find(func(b *book.Book) bool { return b.ISBN13 == isbn13 }), nil
+}
+
+func (f *fakeBookRepository) GetBookByTitle(title string) (*book.Book, error) {
+	return f.find(func(b *book.Book) bool { return strings.EqualFold(b.Title, title) }), nil
+}
+
+func (f *fakeBookRepository) GetBookBySource(source, sourceID string) (*book.Book, error) {
+	return f.find(func(b *book.Book) bool { return b.Source == source && b.SourceID == sourceID }), nil
+}
+
+func (f *fakeBookRepository) find(match func(*book.Book) bool) *book.Book {
+	for _, b := range f.books {
+		if match(b) {
+			copied := *b
+			return &copied
+		}
+	}
+	return nil
+}
+
+// fakeEntitlementRepository records which users bought which books. It has
+// no licenses.
+type fakeEntitlementRepository struct {
+	purchased map[int][]string
+}
+
+func (f *fakeEntitlementRepository) Purchased(userID int, bookIDs []string) (map[string]bool, error) {
+	bought := map[string]bool{}
+	for _, id := range bookIDs {
+		if slices.Contains(f.purchased[userID], id) {
+			bought[id] = true
+		}
+	}
+	return bought, nil
+}
+
+func (f *fakeEntitlementRepository) Licensed(userID int, bookIDs []string) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
+
+func (f *fakeEntitlementRepository) CreateLicense(l *entitlement.License) (*entitlement.License, error) {
+	return l, nil
+}
+
+func (f *fakeEntitlementRepository) ListLicenses() ([]*entitlement.License, error) {
+	return nil, nil
+}
+
+func (f *fakeEntitlementRepository) DeleteLicense(id string) error {
+	return entitlement.ErrLicenseNotFound
+}
+
+func (f *fakeEntitlementRepository) AddLicenseMember(licenseID string, userID int) (*entitlement.LicenseMember, error) {
+	return nil, entitlement.ErrLicenseNotFound
+}
+
+func (f *fakeEntitlementRepository) RemoveLicenseMember(licenseID string, userID int) error {
+	return entitlement.ErrMemberNotFound
+}
+
+func (f *fakeEntitlementRepository) ListLicenseMembers(licenseID string) ([]*entitlement.LicenseMember, error) {
+	return nil, entitlement.ErrLicenseNotFound
+}
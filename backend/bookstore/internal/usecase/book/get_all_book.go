@@ -2,21 +2,32 @@ package book
 
 
 import (
+	"context"
+
 	"github.com/bereke1t2/bookstore/internal/domain/book"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
 )
 type GetAllBooks struct {
-	repo book.BookRepository
+	repo   book.BookRepository
+	access *entitlementuc.CheckAccessUseCase
 }
-func NewGetAllBooksUseCase(repo book.BookRepository) *GetAllBooks {
-	return &GetAllBooks{repo: repo}
+func NewGetAllBooksUseCase(repo book.BookRepository, access *entitlementuc.CheckAccessUseCase) *GetAllBooks {
+	return &GetAllBooks{repo: repo, access: access}
 }
 
-func (uc *GetAllBooks) Execute() ([]*book.Book, error) {
+// Execute lists the catalog, marking the books the caller owns.
+func (uc *GetAllBooks) Execute(ctx context.Context, sort string) ([]*book.Book, error) {
 	print("Executing Get All Books Use Case")
-	books, err := uc.repo.GetAllBooks()
+	if !book.ValidSort(sort) {
+		return nil, book.ErrInvalidBookInput
+	}
+	books, err := uc.repo.ListBooks(sort)
 	if  err != nil {
 		return nil, err
 	}
+	if err := uc.access.MarkOwned(ctx, books); err != nil {
+		return nil, err
+	}
 	print("Get AAll books Use case executed")
 	return books, nil
 }
\ No newline at end of file
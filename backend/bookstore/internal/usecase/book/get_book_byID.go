@@ -1,20 +1,29 @@
 package book
 
 import (
+	"context"
+
 	"github.com/bereke1t2/bookstore/internal/domain/book"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
 )
 
 type GetBookByID struct {
-	repo book.BookRepository
+	repo   book.BookRepository
+	access *entitlementuc.CheckAccessUseCase
 }
 
-func NewGetBookByIDUseCase(repo book.BookRepository) *GetBookByID {
-	return &GetBookByID{repo: repo}
+func NewGetBookByIDUseCase(repo book.BookRepository, access *entitlementuc.CheckAccessUseCase) *GetBookByID {
+	return &GetBookByID{repo: repo, access: access}
 }
 
-func (uc *GetBookByID) Execute(id string) (*book.Book, error) {
+// Execute returns the book, marked as owned or not by the caller, or nil if
+// there is no such book.
+func (uc *GetBookByID) Execute(ctx context.Context, id string) (*book.Book, error) {
 	foundBook, err := uc.repo.GetBookByID(id)
-	if err != nil {
+	if err != nil || foundBook == nil {
+		return nil, err
+	}
+	if err := uc.access.MarkOwned(ctx, []*book.Book{foundBook}); err != nil {
 		return nil, err
 	}
 	return foundBook, nil
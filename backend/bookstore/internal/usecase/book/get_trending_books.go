@@ -1,94 +1,148 @@
 package book
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"strings"
+	"context"
+	"log"
+	"sort"
 
 	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+	"github.com/bereke1t2/bookstore/internal/domain/trending"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
 )
 
-type GetTrendingBooks struct{}
-
-func NewGetTrendingBooks() *GetTrendingBooks {
-	return &GetTrendingBooks{}
+type GetTrendingBooks struct {
+	repo     book.BookRepository
+	trending trending.Repository
+	provider catalog.ExternalProvider
+	cfg      trending.Config
+	access   *entitlementuc.CheckAccessUseCase
 }
 
-// Google Books API Response Structures
-type googleBooksResponse struct {
-	Items []struct {
-		ID         string `json:"id"`
-		VolumeInfo struct {
-			Title         string   `json:"title"`
-			Authors       []string `json:"authors"`
-			Description   string   `json:"description"`
-			AverageRating float32  `json:"averageRating"`
-			ImageLinks    struct {
-				Thumbnail string `json:"thumbnail"`
-			} `json:"imageLinks"`
-			PreviewLink string `json:"previewLink"`
-		} `json:"volumeInfo"`
-		SaleInfo struct {
-			ListPrice struct {
-				Amount float32 `json:"amount"`
-			} `json:"listPrice"`
-		} `json:"saleInfo"`
-	} `json:"items"`
+func NewGetTrendingBooks(repo book.BookRepository, trending trending.Repository, provider catalog.ExternalProvider, cfg trending.Config, access *entitlementuc.CheckAccessUseCase) *GetTrendingBooks {
+	return &GetTrendingBooks{repo: repo, trending: trending, provider: provider, cfg: cfg, access: access}
 }
 
-func (uc *GetTrendingBooks) Execute() ([]book.Book, error) {
-	// Query for new fiction books
-	url := "https://www.googleapis.com/books/v1/volumes?q=subject:fiction&orderBy=newest&maxResults=10&langRestrict=en"
-
-	resp, err := http.Get(url)
+// Execute blends the books trending in our own catalog with the external
+// provider's suggestions. Each list is scored from 1 down to 0 by position,
+// weighed by the configured blend weights, and the two are merged. If the
+// provider is down, only local books are returned. Catalog books are marked
+// as owned or not by the caller.
+func (uc *GetTrendingBooks) Execute(ctx context.Context) ([]book.Book, error) {
+	local, err := uc.local()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch from Google Books: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("google Books API returned status: %d", resp.StatusCode)
+	var external []book.Book
+	if uc.provider != nil && uc.cfg.ExternalWeight > 0 {
+		external, err = uc.external(ctx)
+		if err != nil {
+			if len(local) == 0 {
+				return nil, err
+			}
+			log.Printf("trending: external suggestions unavailable: %v", err)
+		}
 	}
-
-	var gResp googleBooksResponse
-	if err := json.NewDecoder(resp.Body).Decode(&gResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	books := blendTrending(local, external, uc.cfg)
+	ptrs := make([]*book.Book, len(books))
+	for i := range books {
+		ptrs[i] = &books[i]
+	}
+	if err := uc.access.MarkOwned(ctx, ptrs); err != nil {
+		return nil, err
 	}
+	return books, nil
+}
 
-	var books []book.Book
-	for _, item := range gResp.Items {
-		author := "Unknown Author"
-		if len(item.VolumeInfo.Authors) > 0 {
-			author = strings.Join(item.VolumeInfo.Authors, ", ")
-		}
+// scoredBook is a trending book with its blended score.
+type scoredBook struct {
+	book  book.Book
+	score float64
+}
 
-		price := item.SaleInfo.ListPrice.Amount
-		if price == 0 {
-			// Mock price if not available, just for display
-			price = 9.99
+func blendTrending(local, external []book.Book, cfg trending.Config) []book.Book {
+	var scored []scoredBook
+	add := func(books []book.Book, weight float64) {
+		for i, b := range books {
+			scored = append(scored, scoredBook{b, weight * float64(len(books)-i) / float64(len(books))})
 		}
+	}
+	if cfg.LocalWeight > 0 {
+		add(local, cfg.LocalWeight)
+	}
+	add(external, cfg.ExternalWeight)
 
-		// Ensure thumbnail uses HTTPS
-		coverUrl := item.VolumeInfo.ImageLinks.Thumbnail
-		if strings.HasPrefix(coverUrl, "http://") {
-			coverUrl = strings.Replace(coverUrl, "http://", "https://", 1)
+	// Stable, so on equal scores local books stay ahead
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+
+	books := []book.Book{}
+	for _, s := range scored {
+		if len(books) == cfg.Limit {
+			break
 		}
+		books = append(books, s.book)
+	}
+	return books
+}
 
-		b := book.Book{
-			ID:         item.ID,
-			Title:      item.VolumeInfo.Title,
-			Author:     author,
-			Category:   "Trending",
-			Price:      price,
-			Rating:     item.VolumeInfo.AverageRating,
-			CoverUrl:   coverUrl,
-			BookURL:    item.VolumeInfo.PreviewLink, // Use preview link as book URL
-			IsFeatured: false,
-			IsExternal: true,
+// local returns catalog books by their trending score from the last refresh.
+func (uc *GetTrendingBooks) local() ([]book.Book, error) {
+	scores, err := uc.trending.Top(uc.cfg.Limit)
+	if err != nil || len(scores) == 0 {
+		return nil, err
+	}
+	all, err := uc.repo.GetAllBooks()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*book.Book, len(all))
+	for _, b := range all {
+		byID[b.ID] = b
+	}
+
+	books := []book.Book{}
+	for _, s := range scores {
+		if b := byID[s.BookID]; b != nil {
+			books = append(books, *b)
 		}
-		books = append(books, b)
 	}
+	return books, nil
+}
 
+// external fetches suggestions from the external provider.
+func (uc *GetTrendingBooks) external(ctx context.Context) ([]book.Book, error) {
+	results, err := uc.provider.Trending(ctx, uc.cfg.ExternalSubject, uc.cfg.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make([]book.Book, 0, len(results))
+	for _, ext := range results {
+		books = append(books, ExternalBook(ext))
+	}
 	return books, nil
 }
+
+// ExternalBook presents a provider's book like a catalog book. Its price is
+// left unknown unless the provider sells it.
+func ExternalBook(ext *catalog.ExternalBook) book.Book {
+	author := ext.Author()
+	if author == "" {
+		author = "Unknown Author"
+	}
+	b := book.Book{
+		ID:         ext.ID,
+		Title:      ext.Title,
+		Author:     author,
+		Category:   "Trending",
+		Rating:     ext.Rating,
+		CoverUrl:   ext.CoverUrl,
+		BookURL:    ext.PreviewURL, // Use preview link as book URL
+		IsExternal: true,
+	}
+	if ext.Price != nil {
+		price := float32(ext.Price.Amount)
+		b.Price = &price
+	}
+	return b
+}
@@ -0,0 +1,99 @@
+package book
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type ImportBook struct {
+	repo     book.BookRepository
+	provider catalog.ExternalProvider
+}
+
+func NewImportBookUseCase(repo book.BookRepository, provider catalog.ExternalProvider) *ImportBook {
+	return &ImportBook{repo: repo, provider: provider}
+}
+
+// Execute adds a book from the external provider to the local catalog,
+// looked up by ISBN or by the provider's volume ID. If the book is already
+// in the catalog, matched by ISBN-13 or by its provider ID, that book is
+// returned instead and created is false.
+func (uc *ImportBook) Execute(ctx context.Context, isbn, volumeID string) (b *book.Book, created bool, err error) {
+	if _, err := identity.Require(ctx); err != nil {
+		return nil, false, err
+	}
+	isbn, volumeID = strings.TrimSpace(isbn), strings.TrimSpace(volumeID)
+	if (isbn == "") == (volumeID == "") {
+		return nil, false, fmt.Errorf("%w: give either an isbn or a volume_id", book.ErrInvalidBookInput)
+	}
+	if uc.provider == nil {
+		return nil, false, catalog.ErrExternalUnavailable
+	}
+
+	var ext *catalog.ExternalBook
+	if isbn != "" {
+		_, isbn13, err := book.ParseISBN(isbn)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing, err := uc.repo.GetBookByISBN(isbn13); err != nil || existing != nil {
+			return existing, false, err
+		}
+		if ext, err = uc.provider.LookupISBN(ctx, isbn13); err != nil {
+			return nil, false, err
+		}
+	} else if ext, err = uc.provider.Lookup(ctx, volumeID); err != nil {
+		return nil, false, err
+	}
+
+	nb := importedBook(ext)
+	if existing, err := uc.existing(nb); err != nil || existing != nil {
+		return existing, false, err
+	}
+
+	createdBook, err := uc.repo.CreateBook(nb)
+	if err != nil {
+		// Another request may have imported the same book meanwhile
+		if existing, lookupErr := uc.existing(nb); lookupErr == nil && existing != nil {
+			return existing, false, nil
+		}
+		return nil, false, err
+	}
+	return createdBook, true, nil
+}
+
+// existing returns the catalog's copy of an imported book, if any.
+func (uc *ImportBook) existing(b *book.Book) (*book.Book, error) {
+	if b.ISBN13 != "" {
+		existing, err := uc.repo.GetBookByISBN(b.ISBN13)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+	return uc.repo.GetBookBySource(b.Source, b.SourceID)
+}
+
+// importedBook maps a provider book to a catalog entry. ISBNs the provider
+// reports that fail checksum validation are dropped.
+func importedBook(ext *catalog.ExternalBook) *book.Book {
+	b := ExternalBook(ext)
+	b.ID = ""
+	b.Rating = 0
+	b.IsExternal = false
+	b.Category = ""
+	if len(ext.Categories) > 0 {
+		b.Category = ext.Categories[0]
+	}
+	b.Source, b.SourceID = ext.Source, ext.ID
+	if isbn := ext.ISBN(); isbn != "" {
+		if isbn10, isbn13, err := book.ParseISBN(isbn); err == nil {
+			b.ISBN10, b.ISBN13 = isbn10, isbn13
+		}
+	}
+	return &b
+}
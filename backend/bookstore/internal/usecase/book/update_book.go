@@ -1,8 +1,11 @@
 package book
 
 import (
+	"context"
+
 	"github.com/bereke1t2/bookstore/internal/domain/book"
 )
+
 type UpdateBook struct {
 	repo book.BookRepository
 }
@@ -11,10 +14,7 @@ func NewUpdateBookUseCase(repo book.BookRepository) *UpdateBook {
 	return &UpdateBook{repo: repo}
 }
 
-func (uc *UpdateBook) Execute(id string, updatedBook *book.Book) (*book.Book, error) {
-	foundBook, err := uc.repo.GetBookByID(id)
-	if err != nil {
-		return nil, err
-	}
-	return foundBook, nil
-}
\ No newline at end of file
+// Execute updates a book. Only its uploader or an admin may update it.
+func (uc *UpdateBook) Execute(ctx context.Context, id string, updatedBook *book.Book) (*book.Book, error) {
+	return authorizeBookOwner(ctx, uc.repo, id)
+}
@@ -16,10 +16,10 @@ func NewGetMultipleChoiceQuestionUseCase(chatRepo chat.ChatRepository) *GetMulti
 	}
 }
 
-func (uc *GetMultipleChoiceQuestionUseCase) Execute(id string, bookName string) ([]*chat.MultipleQuiz, error) {
-	// REQUEST 10 QUESTIONS (20 often causes the JSON to break due to size)
-	numQuestions := 10
-	difficulty := "medium"
+func (uc *GetMultipleChoiceQuestionUseCase) Execute(id string, bookName string, opts chat.QuizOptions) ([]*chat.MultipleQuiz, error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
 
 	prompt := fmt.Sprintf(`
 Generate %d multiple-choice questions for the book "%s".
@@ -29,6 +29,7 @@ Requirements:
 - 4 options per question.
 - 1 correct answer.
 - Keep explanations very concise (1 sentence).
+%s
 
 Format:
 {
@@ -44,7 +45,7 @@ Format:
     }
   ]
 }
-`, numQuestions, bookName, bookName, difficulty)
+`, opts.Count, bookName, quizScopeRules(opts), bookName, opts.Difficulty)
 
 	return uc.chatRepo.GetMultipleChoiceQuestion(id, prompt)
 }
@@ -1,40 +1,56 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/bereke1t2/bookstore/internal/domain/chat"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
 )
 
 type GetShortAnswerUseCase struct {
-	chatRepo chat.ChatRepository
+	chatRepo  chat.ChatRepository
+	questions chat.QuestionRepository
 }
 
-func NewGetShortAnswerUseCase(chatRepo chat.ChatRepository) *GetShortAnswerUseCase {
+func NewGetShortAnswerUseCase(chatRepo chat.ChatRepository, questions chat.QuestionRepository) *GetShortAnswerUseCase {
 	return &GetShortAnswerUseCase{
-		chatRepo: chatRepo,
+		chatRepo:  chatRepo,
+		questions: questions,
 	}
 }
 
-func (uc *GetShortAnswerUseCase) Execute(id string, bookName string) ([]*chat.ShortAnswer, error) {
+// Execute generates short-answer questions and issues them to the
+// authenticated user, so their answers can be graded against the stored
+// reference answers. The answers and explanations stay on the server until
+// the user's answer is graded.
+func (uc *GetShortAnswerUseCase) Execute(ctx context.Context, id string, bookName string, opts chat.QuizOptions) ([]*chat.ShortAnswer, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
+
 	// Refined prompt for better JSON adherence and mandatory field population
 	prompt := fmt.Sprintf(`
 Act as an expert literary professor. Generate a comprehensive short-answer quiz for the book titled "%s".
 
 Task:
-Generate 10 challenging short-answer questions.
+Generate %d challenging short-answer questions.
 For EVERY question, you MUST provide the correct answer and a detailed explanation.
 
 Rules:
 1. ABSOLUTELY NO EMPTY FIELDS. Every "answer" and "explanation" field must be filled with high-quality content.
 2. Focus on themes, character motivations, and key concepts.
-3. Difficulty: Medium.
-4. Do not include markdown formatting (like `+"```json"+`) in the response, return raw text only.
+3. Do not include markdown formatting (like `+"```json"+`) in the response, return raw text only.
+%s
 
 Strict JSON Structure:
 {
   "book_title": "%s",
-  "difficulty": "Medium",
+  "difficulty": "%s",
   "quizzes": [
     {
       "id": 1,
@@ -44,7 +60,32 @@ Strict JSON Structure:
     }
   ]
 }
-`, bookName, bookName)
+`, bookName, opts.Count, quizScopeRules(opts), bookName, opts.Difficulty)
 
-	return uc.chatRepo.GetShortAnswerQuestion(id, prompt)
+	generated, err := uc.chatRepo.GetShortAnswerQuestion(id, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generated quizzes may be shared through the response cache, so each
+	// user gets copies carrying their own question IDs.
+	quizzes := make([]*chat.ShortAnswer, 0, len(generated))
+	for _, q := range generated {
+		issued, err := uc.questions.Issue(&chat.IssuedQuestion{
+			UserID:          p.UserID,
+			BookName:        bookName,
+			Question:        q.Question,
+			ReferenceAnswer: q.CorrectAnswer,
+			Explanation:     q.Explanation,
+		})
+		if err != nil {
+			return nil, err
+		}
+		quiz := *q
+		quiz.QuestionID = issued.ID
+		quiz.CorrectAnswer = ""
+		quiz.Explanation = ""
+		quizzes = append(quizzes, &quiz)
+	}
+	return quizzes, nil
 }
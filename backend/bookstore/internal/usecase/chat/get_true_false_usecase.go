@@ -15,25 +15,29 @@ func NewGetTrueFalseQuestionUseCase(chatRepo chat.ChatRepository) *GetTrueFalseQ
 		chatRepo: chatRepo,
 	}
 }
-func (uc *GetTrueFalseQuestionUseCase) Execute(id string, bookName string) ([]*chat.TrueFalse, error) {
+func (uc *GetTrueFalseQuestionUseCase) Execute(id string, bookName string, opts chat.QuizOptions) ([]*chat.TrueFalse, error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
+
 	prompt := fmt.Sprintf(`
 Act as a literary quiz creator. Generate a True/False quiz for the book "%s".
 
 Task:
-Generate 10 True/False questions. 
+Generate %d True/False questions. 
 For every question, you MUST provide the correct boolean answer ("true" or "false") and a short explanation.
 
 Rules:
 - DO NOT leave any "answer" or "explanation" fields blank.
 - The "answer" must be a string: either "true" or "false".
 - Focus on themes, motifs, and major concepts.
-- Difficulty: Medium.
+%s
 - Response must be raw JSON only (no markdown, no backticks).
 
 JSON Structure:
 {
   "book_title": "%s",
-  "difficulty": "Medium",
+  "difficulty": "%s",
   "quizzes": [
     {
       "id": 1,
@@ -43,7 +47,7 @@ JSON Structure:
     }
   ]
 }
-`, bookName, bookName)
+`, bookName, opts.Count, quizScopeRules(opts), bookName, opts.Difficulty)
 
 	return uc.chatRepo.GetTrueFalseQuestion(id, prompt)
 }
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/activity/activitytest"
+	"github.com/bereke1t2/bookstore/internal/domain/chat"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/google/uuid"
+)
+
+// fakeQuestionRepository keeps issued questions in memory.
+type fakeQuestionRepository struct {
+	questions map[string]chat.IssuedQuestion
+}
+
+func (f *fakeQuestionRepository) Issue(q *chat.IssuedQuestion) (*chat.IssuedQuestion, error) {
+	q.ID = uuid.New().String()
+	f.questions[q.ID] = *q
+	return q, nil
+}
+
+func (f *fakeQuestionRepository) GetIssued(id string, userID int) (*chat.IssuedQuestion, error) {
+	q, ok := f.questions[id]
+	if !ok || q.UserID != userID {
+		return nil, chat.ErrQuestionNotFound
+	}
+	return &q, nil
+}
+
+// quizChatRepository generates one fixed short-answer question.
+type quizChatRepository struct {
+	fakeChatRepository
+	quiz chat.ShortAnswer
+}
+
+func (f *quizChatRepository) GetShortAnswerQuestion(id, prompt string) ([]*chat.ShortAnswer, error) {
+	return []*chat.ShortAnswer{&f.quiz}, nil
+}
+
+func asUser(userID int) context.Context {
+	return identity.WithPrincipal(context.Background(), identity.Principal{UserID: userID, Roles: []string{identity.RoleUser}})
+}
+
+func newQuizFixture() (*quizChatRepository, *fakeQuestionRepository, *activitytest.Recorder) {
+	chatRepo := &quizChatRepository{quiz: chat.ShortAnswer{ID: 1, Question: "Who narrates the story?", CorrectAnswer: "Nelly Dean", Explanation: "She tells Lockwood the story."}}
+	return chatRepo, &fakeQuestionRepository{questions: map[string]chat.IssuedQuestion{}}, activitytest.NewRecorder()
+}
+
+func TestIssuedQuestionEarnsPointsOnce(t *testing.T) {
+	chatRepo, questions, activityRepo := newQuizFixture()
+	ctx := asUser(1)
+
+	quizzes, err := NewGetShortAnswerUseCase(chatRepo, questions).Execute(ctx, "1", "Wuthering Heights", chat.QuizOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quizzes) != 1 || quizzes[0].QuestionID == "" {
+		t.Fatalf("got %+v, want one issued question", quizzes)
+	}
+	if chatRepo.quiz.QuestionID != "" {
+		t.Error("issuing changed the generated quiz, which the cache may share")
+	}
+
+	grade := NewGradeShortAnswerUseCase(chatRepo, questions, activityRepo)
+	for range 2 {
+		// The client's question and reference answer are ignored for issued questions.
+		g, err := grade.Execute(ctx, "1", quizzes[0].QuestionID, "Wuthering Heights", "Made up?", "anything", "Nelly Dean")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.Verdict != chat.VerdictCorrect {
+			t.Fatalf("got %s, want correct against the stored answer", g.Verdict)
+		}
+	}
+	if got := activityRepo.Points(1); got != activity.QuizAnswerMaxPoints {
+		t.Errorf("got %d points, want %d for one correct answer", got, activity.QuizAnswerMaxPoints)
+	}
+
+	g, err := grade.Execute(ctx, "1", quizzes[0].QuestionID, "Wuthering Heights", "", "Nelly Dean", "Lockwood")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Verdict == chat.VerdictCorrect {
+		t.Error("a client reference answer was used for an issued question")
+	}
+}
+
+func TestClientSuppliedQuestionEarnsNothing(t *testing.T) {
+	chatRepo, questions, activityRepo := newQuizFixture()
+	grade := NewGradeShortAnswerUseCase(chatRepo, questions, activityRepo)
+
+	for i := range 20 {
+		question := "Invented question " + uuid.New().String()
+		g, err := grade.Execute(asUser(1), "1", "", "Any Book", question, "same", "same")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.Verdict != chat.VerdictCorrect {
+			t.Fatalf("attempt %d: got %s, want practice grading to still work", i, g.Verdict)
+		}
+	}
+	if got := activityRepo.Points(1); got != 0 {
+		t.Errorf("got %d points for client-supplied questions, want 0", got)
+	}
+}
+
+func TestAnotherUsersQuestionIsNotFound(t *testing.T) {
+	chatRepo, questions, activityRepo := newQuizFixture()
+
+	quizzes, err := NewGetShortAnswerUseCase(chatRepo, questions).Execute(asUser(1), "1", "Wuthering Heights", chat.QuizOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	grade := NewGradeShortAnswerUseCase(chatRepo, questions, activityRepo)
+	_, err = grade.Execute(asUser(2), "1", quizzes[0].QuestionID, "Wuthering Heights", "", "", "Nelly Dean")
+	if !errors.Is(err, chat.ErrQuestionNotFound) {
+		t.Errorf("got %v, want ErrQuestionNotFound", err)
+	}
+	if got := activityRepo.Points(2); got != 0 {
+		t.Errorf("got %d points, want 0", got)
+	}
+}
+
+func TestIssuedQuestionHidesTheAnswerUntilGraded(t *testing.T) {
+	chatRepo, questions, activityRepo := newQuizFixture()
+	ctx := asUser(1)
+
+	quizzes, err := NewGetShortAnswerUseCase(chatRepo, questions).Execute(ctx, "1", "Wuthering Heights", chat.QuizOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quizzes[0].CorrectAnswer != "" || quizzes[0].Explanation != "" {
+		t.Errorf("issued question carries answer %q and explanation %q", quizzes[0].CorrectAnswer, quizzes[0].Explanation)
+	}
+	body, err := json.Marshal(quizzes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), `"answer"`) || strings.Contains(string(body), "Nelly Dean") {
+		t.Errorf("issue response %s reveals the answer", body)
+	}
+	if chatRepo.quiz.CorrectAnswer != "Nelly Dean" {
+		t.Error("issuing cleared the answer of the generated quiz, which the cache may share")
+	}
+
+	g, err := NewGradeShortAnswerUseCase(chatRepo, questions, activityRepo).Execute(ctx, "1", quizzes[0].QuestionID, "Wuthering Heights", "", "", "Lockwood")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Answer != "Nelly Dean" || g.Explanation != "She tells Lockwood the story." {
+		t.Errorf("grade answer %q, explanation %q; want the stored ones", g.Answer, g.Explanation)
+	}
+}
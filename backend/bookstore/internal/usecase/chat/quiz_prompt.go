@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/chat"
+)
+
+// quizScopeRules turns the scope-related quiz options into prompt rules so
+// that every quiz type applies them the same way.
+func quizScopeRules(opts chat.QuizOptions) string {
+	var rules []string
+
+	switch opts.Difficulty {
+	case chat.DifficultyAdaptive:
+		rules = append(rules, "- Difficulty: Adaptive. Start with easy questions and make each one progressively harder, ending with hard questions.")
+	default:
+		rules = append(rules, fmt.Sprintf("- Difficulty: %s.", strings.ToUpper(opts.Difficulty[:1])+opts.Difficulty[1:]))
+	}
+
+	switch {
+	case opts.ChapterStart > 0 && opts.ChapterEnd > opts.ChapterStart:
+		rules = append(rules, fmt.Sprintf("- Only ask about chapters %d to %d.", opts.ChapterStart, opts.ChapterEnd))
+	case opts.ChapterStart > 0:
+		rules = append(rules, fmt.Sprintf("- Only ask about chapter %d.", opts.ChapterStart))
+	}
+	switch {
+	case opts.PageStart > 0 && opts.PageEnd > opts.PageStart:
+		rules = append(rules, fmt.Sprintf("- Only ask about pages %d to %d.", opts.PageStart, opts.PageEnd))
+	case opts.PageStart > 0:
+		rules = append(rules, fmt.Sprintf("- Only ask about page %d.", opts.PageStart))
+	}
+
+	if opts.Topic != "" {
+		rules = append(rules, fmt.Sprintf("- Focus the questions on this topic: %q.", opts.Topic))
+	}
+	if !strings.EqualFold(opts.Language, chat.DefaultQuizLanguage) {
+		rules = append(rules, fmt.Sprintf("- Write every question, option, answer and explanation in %s. Keep the JSON keys in English.", opts.Language))
+	}
+
+	return strings.Join(rules, "\n")
+}
@@ -0,0 +1,40 @@
+package entitlement
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+)
+
+type AddLicenseMemberUseCase struct {
+	repo  entitlement.Repository
+	users user.UserRepository
+}
+
+func NewAddLicenseMemberUseCase(repo entitlement.Repository, users user.UserRepository) *AddLicenseMemberUseCase {
+	return &AddLicenseMemberUseCase{repo: repo, users: users}
+}
+
+// Execute adds a user to an institutional license, giving them its books.
+// It is up to the admin to check the user belongs to the institution, as
+// the email on their account proves nothing. Admin only.
+func (uc *AddLicenseMemberUseCase) Execute(ctx context.Context, licenseID string, userID int) (*entitlement.LicenseMember, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsAdmin() {
+		return nil, identity.ErrForbidden
+	}
+	u, err := uc.users.GetUserByID(strconv.Itoa(userID))
+	if err != nil {
+		return nil, err
+	}
+	if u.ID == 0 {
+		return nil, user.ErrNotFound
+	}
+	return uc.repo.AddLicenseMember(licenseID, userID)
+}
@@ -0,0 +1,157 @@
+package entitlement
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+// CheckAccessUseCase answers whether the authenticated user may read and
+// download books. Admins may read everything, anyone may read free books,
+// and paid books are open to their uploader, to buyers and to users under
+// an institutional license.
+type CheckAccessUseCase struct {
+	repo  entitlement.Repository
+	books book.BookRepository
+}
+
+func NewCheckAccessUseCase(repo entitlement.Repository, books book.BookRepository) *CheckAccessUseCase {
+	return &CheckAccessUseCase{repo: repo, books: books}
+}
+
+// Execute decides access to each book, keyed by book ID.
+func (uc *CheckAccessUseCase) Execute(ctx context.Context, books ...*book.Book) (map[string]entitlement.Decision, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := make(map[string]entitlement.Decision, len(books))
+	var paid []string
+	for _, b := range books {
+		switch {
+		case p.IsAdmin():
+			decisions[b.ID] = entitlement.Allow(entitlement.ReasonAdmin)
+		case !order.ForSale(b):
+			decisions[b.ID] = entitlement.Allow(entitlement.ReasonFree)
+		case b.UploadedBy != 0 && b.UploadedBy == p.UserID:
+			decisions[b.ID] = entitlement.Allow(entitlement.ReasonUploader)
+		default:
+			decisions[b.ID] = entitlement.Decision{}
+			paid = append(paid, b.ID)
+		}
+	}
+	if len(paid) == 0 {
+		return decisions, nil
+	}
+
+	purchased, err := uc.repo.Purchased(p.UserID, paid)
+	if err != nil {
+		return nil, err
+	}
+	var rest []string
+	for _, id := range paid {
+		if purchased[id] {
+			decisions[id] = entitlement.Allow(entitlement.ReasonPurchased)
+		} else {
+			rest = append(rest, id)
+		}
+	}
+	if len(rest) == 0 {
+		return decisions, nil
+	}
+
+	licensed, err := uc.repo.Licensed(p.UserID, rest)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range rest {
+		if licensed[id] {
+			decisions[id] = entitlement.Allow(entitlement.ReasonLicense)
+		}
+	}
+	return decisions, nil
+}
+
+// Authorize returns ErrNotEntitled if the user may not read b.
+func (uc *CheckAccessUseCase) Authorize(ctx context.Context, b *book.Book) error {
+	decisions, err := uc.Execute(ctx, b)
+	if err != nil {
+		return err
+	}
+	if !decisions[b.ID].Allowed {
+		return entitlement.ErrNotEntitled
+	}
+	return nil
+}
+
+// AuthorizeID is Authorize for a book ID. Books outside the catalog, such
+// as external books, are not ours to restrict.
+func (uc *CheckAccessUseCase) AuthorizeID(ctx context.Context, bookID string) error {
+	b, err := uc.books.GetBookByID(bookID)
+	if err != nil || b == nil {
+		return err
+	}
+	return uc.Authorize(ctx, b)
+}
+
+// AuthorizeTitle is Authorize for a book named by title, as AI quizzes
+// name them. It returns ErrBookNotFound for titles outside the catalog, and
+// ErrNotEntitled unless the user may read every book with the title, as
+// the title alone cannot tell them apart.
+func (uc *CheckAccessUseCase) AuthorizeTitle(ctx context.Context, title string) error {
+	if _, err := identity.Require(ctx); err != nil {
+		return err
+	}
+	if book.NormalizeTitle(title) == "" {
+		return book.ErrBookNotFound
+	}
+	books, err := uc.books.FindBooks(book.Filter{Title: title})
+	if err != nil {
+		return err
+	}
+	if len(books) == 0 {
+		return book.ErrBookNotFound
+	}
+	decisions, err := uc.Execute(ctx, books...)
+	if err != nil {
+		return err
+	}
+	for _, b := range books {
+		if !decisions[b.ID].Allowed {
+			return entitlement.ErrNotEntitled
+		}
+	}
+	return nil
+}
+
+// MarkOwned sets Owned on each catalog book for the authenticated user and
+// clears BookURL on the books they may not read, so the file is only
+// reachable through the download route. External books are left as they
+// are, as they cannot be downloaded here.
+func (uc *CheckAccessUseCase) MarkOwned(ctx context.Context, books []*book.Book) error {
+	local := make([]*book.Book, 0, len(books))
+	for _, b := range books {
+		if !b.IsExternal {
+			local = append(local, b)
+		}
+	}
+	if len(local) == 0 {
+		return nil
+	}
+	decisions, err := uc.Execute(ctx, local...)
+	if err != nil {
+		return err
+	}
+	for _, b := range local {
+		owned := decisions[b.ID].Allowed
+		b.Owned = &owned
+		if !owned {
+			b.BookURL = ""
+		}
+	}
+	return nil
+}
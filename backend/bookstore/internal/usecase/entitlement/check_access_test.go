@@ -0,0 +1,168 @@
+package entitlement
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+const (
+	uploader = 1
+	buyer    = 2
+	student  = 3
+	stranger = 4
+	admin    = 5
+)
+
+func asUser(userID int, roles ...string) context.Context {
+	if len(roles) == 0 {
+		roles = []string{identity.RoleUser}
+	}
+	return identity.WithPrincipal(context.Background(), identity.Principal{UserID: userID, Roles: roles})
+}
+
+func price(p float32) *float32 { return &p }
+
+// newAccessFixture sells "dune", which the buyer bought and the student's
+// institution licenses, and gives "emma" away.
+func newAccessFixture() (*CheckAccessUseCase, *fakeBookRepository) {
+	books := newFakeBookRepository(
+		&book.Book{ID: "dune", Title: "Dune", Price: price(5), Category: "Science Fiction", UploadedBy: uploader, BookURL: "/uploads/books/dune.pdf"},
+		&book.Book{ID: "emma", Title: "Emma", Price: price(0), Category: "Classics", UploadedBy: uploader, BookURL: "/uploads/books/emma.pdf"},
+	)
+	repo := newFakeEntitlementRepository(books)
+	repo.purchased[buyer] = []string{"dune"}
+	l, _ := repo.CreateLicense(&entitlement.License{Name: "Uni", EmailDomain: "uni.edu"})
+	repo.AddLicenseMember(l.ID, student)
+	return NewCheckAccessUseCase(repo, books), books
+}
+
+func TestCheckAccess(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		bookID string
+		want   entitlement.Decision
+	}{
+		{"owner", asUser(buyer), "dune", entitlement.Allow(entitlement.ReasonPurchased)},
+		{"uploader", asUser(uploader), "dune", entitlement.Allow(entitlement.ReasonUploader)},
+		{"admin", asUser(admin, identity.RoleUser, identity.RoleAdmin), "dune", entitlement.Allow(entitlement.ReasonAdmin)},
+		{"institutional license", asUser(student), "dune", entitlement.Allow(entitlement.ReasonLicense)},
+		{"free book", asUser(stranger), "emma", entitlement.Allow(entitlement.ReasonFree)},
+		{"paid book not bought", asUser(stranger), "dune", entitlement.Decision{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, books := newAccessFixture()
+			b, _ := books.GetBookByID(tt.bookID)
+			decisions, err := uc.Execute(tt.ctx, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := decisions[tt.bookID]; got != tt.want {
+				t.Errorf("decision = %+v, want %+v", got, tt.want)
+			}
+
+			err = uc.AuthorizeID(tt.ctx, tt.bookID)
+			if tt.want.Allowed && err != nil {
+				t.Errorf("AuthorizeID: got %v, want nil", err)
+			}
+			if !tt.want.Allowed && !errors.Is(err, entitlement.ErrNotEntitled) {
+				t.Errorf("AuthorizeID: got %v, want ErrNotEntitled", err)
+			}
+		})
+	}
+}
+
+func TestCheckAccessNeedsAUser(t *testing.T) {
+	uc, _ := newAccessFixture()
+	if err := uc.AuthorizeID(context.Background(), "emma"); !errors.Is(err, identity.ErrUnauthenticated) {
+		t.Errorf("AuthorizeID: got %v, want ErrUnauthenticated", err)
+	}
+	if err := uc.AuthorizeTitle(context.Background(), "Emma"); !errors.Is(err, identity.ErrUnauthenticated) {
+		t.Errorf("AuthorizeTitle: got %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestMarkOwnedHidesFilesFromNonReaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		readDune bool
+	}{
+		{"owner", asUser(buyer), true},
+		{"uploader", asUser(uploader), true},
+		{"admin", asUser(admin, identity.RoleUser, identity.RoleAdmin), true},
+		{"institutional license", asUser(student), true},
+		{"stranger", asUser(stranger), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, books := newAccessFixture()
+			listed, _ := books.FindBooks(book.Filter{})
+			external := &book.Book{ID: "ext", Title: "Middlemarch", IsExternal: true, BookURL: "https://books.google.com/x"}
+			listed = append(listed, external)
+			if err := uc.MarkOwned(tt.ctx, listed); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, b := range listed[:2] {
+				readable := b.ID == "emma" || tt.readDune
+				if b.Owned == nil || *b.Owned != readable {
+					t.Errorf("%s: owned = %v, want %v", b.ID, b.Owned, readable)
+				}
+				if shown := b.BookURL != ""; shown != readable {
+					t.Errorf("%s: book_url %q shown = %v, want %v", b.ID, b.BookURL, shown, readable)
+				}
+			}
+			if external.Owned != nil || external.BookURL == "" {
+				t.Errorf("external book changed: owned %v, book_url %q", external.Owned, external.BookURL)
+			}
+		})
+	}
+}
+
+func TestAuthorizeTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		title string
+		want  error
+	}{
+		{"owner", asUser(buyer), "Dune", nil},
+		{"uploader", asUser(uploader), "Dune", nil},
+		{"admin", asUser(admin, identity.RoleUser, identity.RoleAdmin), "Dune", nil},
+		{"institutional license", asUser(student), "Dune", nil},
+		{"free book", asUser(stranger), "Emma", nil},
+		{"paid book not bought", asUser(stranger), "Dune", entitlement.ErrNotEntitled},
+		{"title typed differently", asUser(stranger), "  dUNE ", entitlement.ErrNotEntitled},
+		{"unknown title", asUser(stranger), "Dune Messiah", book.ErrBookNotFound},
+		{"unknown title for an admin", asUser(admin, identity.RoleUser, identity.RoleAdmin), "Dune Messiah", book.ErrBookNotFound},
+		{"blank title", asUser(stranger), "   ", book.ErrBookNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newAccessFixture()
+			if err := uc.AuthorizeTitle(tt.ctx, tt.title); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeTitleSharedByAFreeAndAPaidBook(t *testing.T) {
+	uc, books := newAccessFixture()
+	books.books["dune-free"] = &book.Book{ID: "dune-free", Title: "dune", UploadedBy: stranger}
+
+	// the free copy must not open the paid one
+	if err := uc.AuthorizeTitle(asUser(stranger), "Dune"); !errors.Is(err, entitlement.ErrNotEntitled) {
+		t.Errorf("stranger: got %v, want ErrNotEntitled", err)
+	}
+	if err := uc.AuthorizeTitle(asUser(buyer), "Dune"); err != nil {
+		t.Errorf("buyer: got %v, want nil", err)
+	}
+}
@@ -0,0 +1,33 @@
+package entitlement
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type CreateLicenseUseCase struct {
+	repo entitlement.Repository
+}
+
+func NewCreateLicenseUseCase(repo entitlement.Repository) *CreateLicenseUseCase {
+	return &CreateLicenseUseCase{repo: repo}
+}
+
+// Execute creates an institutional license. Its books go to the members
+// added to it afterwards. Only admins may manage licenses.
+func (uc *CreateLicenseUseCase) Execute(ctx context.Context, l *entitlement.License) (*entitlement.License, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsAdmin() {
+		return nil, identity.ErrForbidden
+	}
+	if err := l.Normalize(time.Now()); err != nil {
+		return nil, err
+	}
+	return uc.repo.CreateLicense(l)
+}
@@ -0,0 +1,28 @@
+package entitlement
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type DeleteLicenseUseCase struct {
+	repo entitlement.Repository
+}
+
+func NewDeleteLicenseUseCase(repo entitlement.Repository) *DeleteLicenseUseCase {
+	return &DeleteLicenseUseCase{repo: repo}
+}
+
+// Execute revokes an institutional license. Admin only.
+func (uc *DeleteLicenseUseCase) Execute(ctx context.Context, id string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	if !p.IsAdmin() {
+		return identity.ErrForbidden
+	}
+	return uc.repo.DeleteLicense(id)
+}
@@ -0,0 +1,169 @@
+package entitlement
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+)
+
+// fakeBookRepository keeps books in memory. Only the lookups
+// CheckAccessUseCase makes are implemented.
+type fakeBookRepository struct {
+	book.BookRepository
+	books map[string]*book.Book
+}
+
+func newFakeBookRepository(books ...*book.Book) *fakeBookRepository {
+	f := &fakeBookRepository{books: map[string]*book.Book{}}
+	for _, b := range books {
+		f.books[b.ID] = b
+	}
+	return f
+}
+
+func (f *fakeBookRepository) GetBookByID(id string) (*book.Book, error) {
+	b, ok := f.books[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *b
+	return &copied, nil
+}
+
+func (f *fakeBookRepository) FindBooks(filter book.Filter) ([]*book.Book, error) {
+	var books []*book.Book
+	for _, b := range f.books {
+		if filter.Title != "" && book.NormalizeTitle(b.Title) != book.NormalizeTitle(filter.Title) {
+			continue
+		}
+		copied := *b
+		books = append(books, &copied)
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	return books, nil
+}
+
+// fakeEntitlementRepository records the books each user bought and keeps
+// licenses and their members. Like the Postgres store it grants a license's
+// books to its members only, whatever their email.
+type fakeEntitlementRepository struct {
+	books     *fakeBookRepository
+	purchased map[int][]string
+	licenses  map[string]*entitlement.License
+	members   map[string][]int
+	next      int
+}
+
+func newFakeEntitlementRepository(books *fakeBookRepository) *fakeEntitlementRepository {
+	return &fakeEntitlementRepository{
+		books:     books,
+		purchased: map[int][]string{},
+		licenses:  map[string]*entitlement.License{},
+		members:   map[string][]int{},
+	}
+}
+
+func (f *fakeEntitlementRepository) Purchased(userID int, bookIDs []string) (map[string]bool, error) {
+	found := map[string]bool{}
+	for _, id := range bookIDs {
+		if slices.Contains(f.purchased[userID], id) {
+			found[id] = true
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeEntitlementRepository) Licensed(userID int, bookIDs []string) (map[string]bool, error) {
+	found := map[string]bool{}
+	for _, id := range bookIDs {
+		b, ok := f.books.books[id]
+		if !ok {
+			continue
+		}
+		for licenseID, l := range f.licenses {
+			expired := l.ExpiresAt != nil && !l.ExpiresAt.After(time.Now())
+			if slices.Contains(f.members[licenseID], userID) && !expired && (l.Category == "" || l.Category == b.Category) {
+				found[id] = true
+			}
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeEntitlementRepository) CreateLicense(l *entitlement.License) (*entitlement.License, error) {
+	f.next++
+	saved := *l
+	saved.ID = fmt.Sprintf("l%d", f.next)
+	f.licenses[saved.ID] = &saved
+	copied := saved
+	return &copied, nil
+}
+
+func (f *fakeEntitlementRepository) ListLicenses() ([]*entitlement.License, error) {
+	licenses := []*entitlement.License{}
+	for _, l := range f.licenses {
+		copied := *l
+		licenses = append(licenses, &copied)
+	}
+	return licenses, nil
+}
+
+func (f *fakeEntitlementRepository) DeleteLicense(id string) error {
+	if _, ok := f.licenses[id]; !ok {
+		return entitlement.ErrLicenseNotFound
+	}
+	delete(f.licenses, id)
+	delete(f.members, id)
+	return nil
+}
+
+func (f *fakeEntitlementRepository) AddLicenseMember(licenseID string, userID int) (*entitlement.LicenseMember, error) {
+	if _, ok := f.licenses[licenseID]; !ok {
+		return nil, entitlement.ErrLicenseNotFound
+	}
+	if !slices.Contains(f.members[licenseID], userID) {
+		f.members[licenseID] = append(f.members[licenseID], userID)
+	}
+	return &entitlement.LicenseMember{LicenseID: licenseID, UserID: userID, AddedAt: time.Now()}, nil
+}
+
+func (f *fakeEntitlementRepository) RemoveLicenseMember(licenseID string, userID int) error {
+	i := slices.Index(f.members[licenseID], userID)
+	if i < 0 {
+		return entitlement.ErrMemberNotFound
+	}
+	f.members[licenseID] = slices.Delete(f.members[licenseID], i, i+1)
+	return nil
+}
+
+func (f *fakeEntitlementRepository) ListLicenseMembers(licenseID string) ([]*entitlement.LicenseMember, error) {
+	if _, ok := f.licenses[licenseID]; !ok {
+		return nil, entitlement.ErrLicenseNotFound
+	}
+	members := []*entitlement.LicenseMember{}
+	for _, id := range f.members[licenseID] {
+		members = append(members, &entitlement.LicenseMember{LicenseID: licenseID, UserID: id})
+	}
+	return members, nil
+}
+
+// fakeUserRepository holds users by ID. Only the lookup the license
+// usecases make is implemented.
+type fakeUserRepository struct {
+	user.UserRepository
+	users map[int]user.User
+}
+
+func (f *fakeUserRepository) GetUserByID(id string) (user.User, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return user.User{}, nil
+	}
+	return f.users[n], nil
+}
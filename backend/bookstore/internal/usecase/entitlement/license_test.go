@@ -0,0 +1,132 @@
+package entitlement
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+)
+
+type licenseFixture struct {
+	repo   *fakeEntitlementRepository
+	users  *fakeUserRepository
+	access *CheckAccessUseCase
+	create *CreateLicenseUseCase
+	add    *AddLicenseMemberUseCase
+	remove *RemoveLicenseMemberUseCase
+	list   *ListLicenseMembersUseCase
+}
+
+// newLicenseFixture sells "dune", a science fiction book, and "emma", a
+// classic. The student signed up with an address at uni.edu and the
+// stranger later changed theirs to one; nothing has verified either.
+func newLicenseFixture() *licenseFixture {
+	books := newFakeBookRepository(
+		&book.Book{ID: "dune", Title: "Dune", Price: price(5), Category: "Science Fiction", UploadedBy: uploader},
+		&book.Book{ID: "emma", Title: "Emma", Price: price(5), Category: "Classics", UploadedBy: uploader},
+	)
+	repo := newFakeEntitlementRepository(books)
+	users := &fakeUserRepository{users: map[int]user.User{
+		student:  {ID: student, Email: "student@uni.edu"},
+		stranger: {ID: stranger, Email: "stranger@example.com"},
+	}}
+	return &licenseFixture{
+		repo:   repo,
+		users:  users,
+		access: NewCheckAccessUseCase(repo, books),
+		create: NewCreateLicenseUseCase(repo),
+		add:    NewAddLicenseMemberUseCase(repo, users),
+		remove: NewRemoveLicenseMemberUseCase(repo),
+		list:   NewListLicenseMembersUseCase(repo),
+	}
+}
+
+func (f *licenseFixture) license(t *testing.T, l *entitlement.License) *entitlement.License {
+	t.Helper()
+	created, err := f.create.Execute(asUser(admin, identity.RoleUser, identity.RoleAdmin), l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func (f *licenseFixture) mayRead(t *testing.T, userID int, bookID string) bool {
+	t.Helper()
+	err := f.access.AuthorizeID(asUser(userID), bookID)
+	if err != nil && !errors.Is(err, entitlement.ErrNotEntitled) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func TestLicenseEmailDomainGrantsNothing(t *testing.T) {
+	f := newLicenseFixture()
+	f.license(t, &entitlement.License{Name: "Uni", EmailDomain: "uni.edu"})
+
+	if f.mayRead(t, student, "dune") {
+		t.Error("an unverified email at the licensed domain opened a paid book")
+	}
+	u := f.users.users[stranger]
+	u.Email = "x@uni.edu"
+	f.users.users[stranger] = u
+	if f.mayRead(t, stranger, "dune") {
+		t.Error("changing email to the licensed domain opened a paid book")
+	}
+}
+
+func TestLicenseGoesToMembers(t *testing.T) {
+	f := newLicenseFixture()
+	adminCtx := asUser(admin, identity.RoleUser, identity.RoleAdmin)
+	l := f.license(t, &entitlement.License{Name: "Uni", EmailDomain: "uni.edu", Category: "Science Fiction"})
+
+	if _, err := f.add.Execute(asUser(student), l.ID, student); !errors.Is(err, identity.ErrForbidden) {
+		t.Errorf("user adding themselves: got %v, want ErrForbidden", err)
+	}
+	if _, err := f.add.Execute(adminCtx, l.ID, 99); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("adding a missing user: got %v, want user.ErrNotFound", err)
+	}
+	if _, err := f.add.Execute(adminCtx, "missing", student); !errors.Is(err, entitlement.ErrLicenseNotFound) {
+		t.Errorf("adding to a missing license: got %v, want ErrLicenseNotFound", err)
+	}
+
+	if _, err := f.add.Execute(adminCtx, l.ID, student); err != nil {
+		t.Fatal(err)
+	}
+	if !f.mayRead(t, student, "dune") {
+		t.Error("member may not read a book in the licensed category")
+	}
+	if f.mayRead(t, student, "emma") {
+		t.Error("member may read a book outside the licensed category")
+	}
+	if members, err := f.list.Execute(adminCtx, l.ID); err != nil || len(members) != 1 || members[0].UserID != student {
+		t.Errorf("members = %v, %v; want the student", members, err)
+	}
+
+	if err := f.remove.Execute(adminCtx, l.ID, student); err != nil {
+		t.Fatal(err)
+	}
+	if f.mayRead(t, student, "dune") {
+		t.Error("removed member may still read the book")
+	}
+	if err := f.remove.Execute(adminCtx, l.ID, student); !errors.Is(err, entitlement.ErrMemberNotFound) {
+		t.Errorf("removing twice: got %v, want ErrMemberNotFound", err)
+	}
+}
+
+func TestExpiredLicenseGrantsNothing(t *testing.T) {
+	f := newLicenseFixture()
+	l := f.license(t, &entitlement.License{Name: "Uni", EmailDomain: "uni.edu"})
+	if _, err := f.add.Execute(asUser(admin, identity.RoleUser, identity.RoleAdmin), l.ID, student); err != nil {
+		t.Fatal(err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	f.repo.licenses[l.ID].ExpiresAt = &past
+	if f.mayRead(t, student, "dune") {
+		t.Error("member of an expired license may read the book")
+	}
+}
@@ -0,0 +1,28 @@
+package entitlement
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type ListLicenseMembersUseCase struct {
+	repo entitlement.Repository
+}
+
+func NewListLicenseMembersUseCase(repo entitlement.Repository) *ListLicenseMembersUseCase {
+	return &ListLicenseMembersUseCase{repo: repo}
+}
+
+// Execute lists the members of an institutional license. Admin only.
+func (uc *ListLicenseMembersUseCase) Execute(ctx context.Context, licenseID string) ([]*entitlement.LicenseMember, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsAdmin() {
+		return nil, identity.ErrForbidden
+	}
+	return uc.repo.ListLicenseMembers(licenseID)
+}
@@ -0,0 +1,29 @@
+package entitlement
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type ListLicensesUseCase struct {
+	repo entitlement.Repository
+}
+
+func NewListLicensesUseCase(repo entitlement.Repository) *ListLicensesUseCase {
+	return &ListLicensesUseCase{repo: repo}
+}
+
+// Execute lists every institutional license, including expired ones.
+// Admin only.
+func (uc *ListLicensesUseCase) Execute(ctx context.Context) ([]*entitlement.License, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsAdmin() {
+		return nil, identity.ErrForbidden
+	}
+	return uc.repo.ListLicenses()
+}
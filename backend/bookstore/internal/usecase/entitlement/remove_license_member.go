@@ -0,0 +1,28 @@
+package entitlement
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type RemoveLicenseMemberUseCase struct {
+	repo entitlement.Repository
+}
+
+func NewRemoveLicenseMemberUseCase(repo entitlement.Repository) *RemoveLicenseMemberUseCase {
+	return &RemoveLicenseMemberUseCase{repo: repo}
+}
+
+// Execute takes a user off an institutional license. Admin only.
+func (uc *RemoveLicenseMemberUseCase) Execute(ctx context.Context, licenseID string, userID int) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	if !p.IsAdmin() {
+		return identity.ErrForbidden
+	}
+	return uc.repo.RemoveLicenseMember(licenseID, userID)
+}
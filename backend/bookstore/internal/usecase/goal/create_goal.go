@@ -0,0 +1,50 @@
+package goal
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/goal"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type CreateGoalUseCase struct {
+	repo     goal.Repository
+	activity activity.Repository
+	evaluate *EvaluateGoalsUseCase
+}
+
+func NewCreateGoalUseCase(repo goal.Repository, activity activity.Repository, evaluate *EvaluateGoalsUseCase) *CreateGoalUseCase {
+	return &CreateGoalUseCase{repo: repo, activity: activity, evaluate: evaluate}
+}
+
+// Execute creates a goal for the authenticated user and returns its
+// progress so far. A goal that is already reached is completed straight
+// away but earns nothing: only progress made towards a goal is rewarded.
+func (uc *CreateGoalUseCase) Execute(ctx context.Context, g *goal.Goal) (*goal.Progress, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	today, err := localToday(uc.activity, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	g.UserID = p.UserID
+	if err := g.Normalize(today); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.repo.Create(g)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := measure(uc.repo, created, today)
+	if err != nil {
+		return nil, err
+	}
+	uc.evaluate.markCompleted(progress, time.Now())
+	return progress, nil
+}
@@ -0,0 +1,26 @@
+package goal
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/goal"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type DeleteGoalUseCase struct {
+	repo goal.Repository
+}
+
+func NewDeleteGoalUseCase(repo goal.Repository) *DeleteGoalUseCase {
+	return &DeleteGoalUseCase{repo: repo}
+}
+
+// Execute deletes one of the authenticated user's goals. Points already
+// earned for it are kept.
+func (uc *DeleteGoalUseCase) Execute(ctx context.Context, id string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return uc.repo.Delete(p.UserID, id)
+}
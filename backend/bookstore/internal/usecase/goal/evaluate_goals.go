@@ -0,0 +1,71 @@
+package goal
+
+import (
+	"log"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/goal"
+	activityuc "github.com/bereke1t2/bookstore/internal/usecase/activity"
+)
+
+// EvaluateGoalsUseCase completes the goals a user has just reached and
+// records the events that reward them.
+type EvaluateGoalsUseCase struct {
+	repo     goal.Repository
+	activity activity.Repository
+}
+
+func NewEvaluateGoalsUseCase(repo goal.Repository, activity activity.Repository) *EvaluateGoalsUseCase {
+	return &EvaluateGoalsUseCase{repo: repo, activity: activity}
+}
+
+// Execute checks the user's goals that are running today.
+func (uc *EvaluateGoalsUseCase) Execute(userID int) error {
+	today, err := localToday(uc.activity, userID)
+	if err != nil {
+		return err
+	}
+	goals, err := uc.repo.GetByUserID(userID)
+	if err != nil {
+		return err
+	}
+	for _, g := range goals {
+		if g.CompletedAt != nil || !g.Contains(today) {
+			continue
+		}
+		progress, err := measure(uc.repo, g, today)
+		if err != nil {
+			return err
+		}
+		uc.complete(progress, today)
+	}
+	return nil
+}
+
+// complete rewards a books goal the first time its target is reached, and
+// a met daily goal once per day.
+func (uc *EvaluateGoalsUseCase) complete(p *goal.Progress, today time.Time) {
+	g, now := p.Goal, time.Now()
+	switch {
+	case uc.markCompleted(p, now):
+		activityuc.Record(uc.activity, activity.GoalCompleted(g.UserID, g.StartDate, g.EndDate, now))
+	case g.Type == goal.TypeDailyMinutes && p.TodayMinutes != nil && *p.TodayMinutes >= g.Target:
+		activityuc.Record(uc.activity, activity.DailyGoalMet(g.UserID, today, now))
+	}
+}
+
+// markCompleted marks a books goal completed once its target is reached and
+// reports whether it did so now.
+func (uc *EvaluateGoalsUseCase) markCompleted(p *goal.Progress, now time.Time) bool {
+	g := p.Goal
+	if g.Type != goal.TypeBooks || g.CompletedAt != nil || p.Current < g.Target {
+		return false
+	}
+	if err := uc.repo.MarkCompleted(g.ID, now); err != nil {
+		log.Printf("goals: complete %s for user %d: %v", g.ID, g.UserID, err)
+		return false
+	}
+	g.CompletedAt = &now
+	return true
+}
@@ -0,0 +1,67 @@
+package goal
+
+import (
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/goal"
+	"github.com/google/uuid"
+)
+
+// fakeGoalRepository keeps goals in memory and measures them against fixed
+// progress: every finished book and every day of reading counts, whatever
+// the period.
+type fakeGoalRepository struct {
+	goals    map[string]*goal.Goal
+	finished int
+	seconds  map[time.Time]int
+}
+
+func newFakeGoalRepository() *fakeGoalRepository {
+	return &fakeGoalRepository{goals: map[string]*goal.Goal{}, seconds: map[time.Time]int{}}
+}
+
+func (f *fakeGoalRepository) Create(g *goal.Goal) (*goal.Goal, error) {
+	stored := *g
+	stored.ID = uuid.New().String()
+	stored.CreatedAt = time.Now()
+	f.goals[stored.ID] = &stored
+	created := stored
+	return &created, nil
+}
+
+func (f *fakeGoalRepository) GetByUserID(userID int) ([]*goal.Goal, error) {
+	var goals []*goal.Goal
+	for _, g := range f.goals {
+		if g.UserID == userID {
+			found := *g
+			goals = append(goals, &found)
+		}
+	}
+	return goals, nil
+}
+
+func (f *fakeGoalRepository) Delete(userID int, id string) error {
+	g, ok := f.goals[id]
+	if !ok || g.UserID != userID {
+		return goal.ErrGoalNotFound
+	}
+	delete(f.goals, id)
+	return nil
+}
+
+func (f *fakeGoalRepository) MarkCompleted(id string, at time.Time) error {
+	g, ok := f.goals[id]
+	if !ok {
+		return goal.ErrGoalNotFound
+	}
+	g.CompletedAt = &at
+	return nil
+}
+
+func (f *fakeGoalRepository) CountFinishedBooks(userID int, from, to time.Time) (int, error) {
+	return f.finished, nil
+}
+
+func (f *fakeGoalRepository) ReadingSecondsByDay(userID int, from, to time.Time) (map[time.Time]int, error) {
+	return f.seconds, nil
+}
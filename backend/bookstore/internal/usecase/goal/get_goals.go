@@ -0,0 +1,46 @@
+package goal
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/goal"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+type GetGoalsUseCase struct {
+	repo     goal.Repository
+	activity activity.Repository
+}
+
+func NewGetGoalsUseCase(repo goal.Repository, activity activity.Repository) *GetGoalsUseCase {
+	return &GetGoalsUseCase{repo: repo, activity: activity}
+}
+
+// Execute lists the authenticated user's goals with their progress and
+// pace as of today in the user's timezone.
+func (uc *GetGoalsUseCase) Execute(ctx context.Context) ([]*goal.Progress, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	today, err := localToday(uc.activity, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	goals, err := uc.repo.GetByUserID(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make([]*goal.Progress, 0, len(goals))
+	for _, g := range goals {
+		gp, err := measure(uc.repo, g, today)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, gp)
+	}
+	return progress, nil
+}
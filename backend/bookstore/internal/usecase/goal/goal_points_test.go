@@ -0,0 +1,141 @@
+package goal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/activity/activitytest"
+	"github.com/bereke1t2/bookstore/internal/domain/goal"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+)
+
+func asUser(userID int) context.Context {
+	return identity.WithPrincipal(context.Background(), identity.Principal{UserID: userID, Roles: []string{identity.RoleUser}})
+}
+
+type goalFixture struct {
+	repo     *fakeGoalRepository
+	points   *activitytest.Recorder
+	create   *CreateGoalUseCase
+	delete   *DeleteGoalUseCase
+	evaluate *EvaluateGoalsUseCase
+}
+
+func newGoalFixture() *goalFixture {
+	repo, points := newFakeGoalRepository(), activitytest.NewRecorder()
+	evaluate := NewEvaluateGoalsUseCase(repo, points)
+	return &goalFixture{
+		repo:     repo,
+		points:   points,
+		create:   NewCreateGoalUseCase(repo, points, evaluate),
+		delete:   NewDeleteGoalUseCase(repo),
+		evaluate: evaluate,
+	}
+}
+
+func today() time.Time {
+	return activity.LocalDate(time.Now(), time.UTC)
+}
+
+func TestGoalAlreadyReachedAtCreationEarnsNothing(t *testing.T) {
+	f := newGoalFixture()
+	f.repo.finished = 5
+	ctx := asUser(1)
+
+	for range 5 {
+		progress, err := f.create.Execute(ctx, &goal.Goal{Type: goal.TypeBooks, Target: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if progress.Status != goal.StatusCompleted || progress.Goal.CompletedAt == nil {
+			t.Fatalf("reached goal status %s, completed %v", progress.Status, progress.Goal.CompletedAt)
+		}
+		if err := f.delete.Execute(ctx, progress.Goal.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.evaluate.Execute(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.points.Points(1); got != 0 {
+		t.Errorf("create/delete loop earned %d points, want 0", got)
+	}
+}
+
+func TestGoalCompletionIsRewardedOncePerPeriod(t *testing.T) {
+	f := newGoalFixture()
+	ctx := asUser(1)
+
+	for range 3 {
+		if _, err := f.create.Execute(ctx, &goal.Goal{Type: goal.TypeBooks, Target: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.repo.finished = 1
+	if err := f.evaluate.Execute(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.points.Points(1); got != activity.GoalCompletedPoints {
+		t.Fatalf("three goals for one period earned %d points, want %d", got, activity.GoalCompletedPoints)
+	}
+
+	// A goal set again for the same period, after deleting the old ones,
+	// is completed without paying out again.
+	goals, _ := f.repo.GetByUserID(1)
+	for _, g := range goals {
+		if err := f.delete.Execute(ctx, g.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := f.create.Execute(ctx, &goal.Goal{Type: goal.TypeBooks, Target: 2}); err != nil {
+		t.Fatal(err)
+	}
+	f.repo.finished = 2
+	if err := f.evaluate.Execute(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.points.Points(1); got != activity.GoalCompletedPoints {
+		t.Errorf("re-created goal raised points to %d, want %d", got, activity.GoalCompletedPoints)
+	}
+}
+
+func TestGoalCompletionIsCappedPerDay(t *testing.T) {
+	f := newGoalFixture()
+	ctx := asUser(1)
+
+	for days := range 4 {
+		g := &goal.Goal{Type: goal.TypeBooks, Target: 1, StartDate: today(), EndDate: today().AddDate(0, 0, days+1)}
+		if _, err := f.create.Execute(ctx, g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.repo.finished = 1
+	if err := f.evaluate.Execute(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.points.Points(1); got != activity.DailyPointCap[activity.TypeGoalCompleted] {
+		t.Errorf("goals over different periods earned %d points in a day, want the cap %d", got, activity.DailyPointCap[activity.TypeGoalCompleted])
+	}
+}
+
+func TestDailyGoalIsRewardedOncePerDay(t *testing.T) {
+	f := newGoalFixture()
+	ctx := asUser(1)
+
+	for range 3 {
+		if _, err := f.create.Execute(ctx, &goal.Goal{Type: goal.TypeDailyMinutes, Target: 10}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.repo.seconds[today()] = 20 * 60
+	for range 2 {
+		if err := f.evaluate.Execute(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := f.points.Points(1); got != activity.DailyGoalPoints {
+		t.Errorf("three daily goals met today earned %d points, want %d", got, activity.DailyGoalPoints)
+	}
+}
@@ -0,0 +1,45 @@
+package goal
+
+import (
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/goal"
+)
+
+// localToday returns today's date in the user's timezone.
+func localToday(repo activity.Repository, userID int) (time.Time, error) {
+	stats, err := repo.GetStats(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return activity.LocalDate(time.Now(), activity.Location(stats.Timezone)), nil
+}
+
+// measure computes g's progress from finished books or daily reading time.
+func measure(repo goal.Repository, g *goal.Goal, today time.Time) (*goal.Progress, error) {
+	if g.Type == goal.TypeBooks {
+		finished, err := repo.CountFinishedBooks(g.UserID, g.StartDate, g.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		return g.Compute(finished, today), nil
+	}
+
+	seconds, err := repo.ReadingSecondsByDay(g.UserID, g.StartDate, g.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	met := 0
+	for _, s := range seconds {
+		if s >= g.Target*60 {
+			met++
+		}
+	}
+	p := g.Compute(met, today)
+	if g.Contains(today) {
+		minutes := seconds[today] / 60
+		p.TodayMinutes = &minutes
+	}
+	return p, nil
+}
@@ -0,0 +1,45 @@
+package goal
+
+import (
+	"log"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+)
+
+// GoalTrackingActivityRepository is an activity.Repository that evaluates
+// the user's goals after every newly recorded reading session or finished
+// book.
+type GoalTrackingActivityRepository struct {
+	next     activity.Repository
+	evaluate *EvaluateGoalsUseCase
+}
+
+var _ activity.Repository = (*GoalTrackingActivityRepository)(nil)
+
+// NewGoalTrackingActivityRepository wraps next. evaluate should record its
+// own events to next rather than to the returned repository.
+func NewGoalTrackingActivityRepository(next activity.Repository, evaluate *EvaluateGoalsUseCase) *GoalTrackingActivityRepository {
+	return &GoalTrackingActivityRepository{next: next, evaluate: evaluate}
+}
+
+func (r *GoalTrackingActivityRepository) Record(e *activity.Event) (bool, error) {
+	recorded, err := r.next.Record(e)
+	if err != nil || !recorded {
+		return recorded, err
+	}
+	if e.Type == activity.TypeReadingSession || e.Type == activity.TypeBookFinished {
+		if err := r.evaluate.Execute(e.UserID); err != nil {
+			log.Printf("goals: evaluate for user %d: %v", e.UserID, err)
+		}
+	}
+	return true, nil
+}
+
+func (r *GoalTrackingActivityRepository) GetStats(userID int) (*activity.Stats, error) {
+	return r.next.GetStats(userID)
+}
+
+func (r *GoalTrackingActivityRepository) GetStreakDays(userID int, limit int) ([]time.Time, error) {
+	return r.next.GetStreakDays(userID, limit)
+}
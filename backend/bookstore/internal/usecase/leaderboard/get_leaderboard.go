@@ -0,0 +1,61 @@
+package leaderboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/leaderboard"
+)
+
+type GetLeaderboardUseCase struct {
+	repo     leaderboard.Repository
+	activity activity.Repository
+}
+
+func NewGetLeaderboardUseCase(repo leaderboard.Repository, activity activity.Repository) *GetLeaderboardUseCase {
+	return &GetLeaderboardUseCase{repo: repo, activity: activity}
+}
+
+// Execute returns the top of the requested board and the authenticated
+// user's own rank on it. The weekly board starts on Monday in the user's
+// timezone.
+func (uc *GetLeaderboardUseCase) Execute(ctx context.Context, q leaderboard.Query) (*leaderboard.Board, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q.UserID = p.UserID
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+
+	board := &leaderboard.Board{Scope: q.Scope, Metric: q.Metric}
+	since := time.Time{}
+	if q.Scope == leaderboard.ScopeWeekly {
+		stats, err := uc.activity.GetStats(p.UserID)
+		if err != nil {
+			return nil, err
+		}
+		since = leaderboard.WeekStart(time.Now(), activity.Location(stats.Timezone))
+		board.Since = &since
+	}
+
+	board.Entries, err = uc.repo.Top(q, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range board.Entries {
+		if e.UserID == p.UserID {
+			board.Me = e
+			return board, nil
+		}
+	}
+
+	board.Me, err = uc.repo.RankOf(q, since)
+	if err != nil {
+		return nil, err
+	}
+	return board, nil
+}
@@ -0,0 +1,26 @@
+package library
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/library"
+)
+
+type GetImportUseCase struct {
+	repo library.ImportRepository
+}
+
+func NewGetImportUseCase(repo library.ImportRepository) *GetImportUseCase {
+	return &GetImportUseCase{repo: repo}
+}
+
+// Execute returns one of the authenticated user's import jobs with its
+// progress and unmatched rows.
+func (uc *GetImportUseCase) Execute(ctx context.Context, id string) (*library.Job, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetJob(p.UserID, id)
+}
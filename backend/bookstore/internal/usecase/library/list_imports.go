@@ -0,0 +1,28 @@
+package library
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/library"
+)
+
+// recentImports is how many jobs ListImportsUseCase returns.
+const recentImports = 20
+
+type ListImportsUseCase struct {
+	repo library.ImportRepository
+}
+
+func NewListImportsUseCase(repo library.ImportRepository) *ListImportsUseCase {
+	return &ListImportsUseCase{repo: repo}
+}
+
+// Execute lists the authenticated user's most recent import jobs.
+func (uc *ListImportsUseCase) Execute(ctx context.Context) ([]*library.Job, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.ListJobs(p.UserID, recentImports)
+}
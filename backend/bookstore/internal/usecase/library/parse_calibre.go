@@ -0,0 +1,110 @@
+package library
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/library"
+)
+
+// opfPackage is the part of an OPF package document the import reads.
+type opfPackage struct {
+	Metadata struct {
+		Titles      []string `xml:"http://purl.org/dc/elements/1.1/ title"`
+		Creators    []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		Identifiers []struct {
+			Scheme string `xml:"scheme,attr"`
+			Value  string `xml:",chardata"`
+		} `xml:"http://purl.org/dc/elements/1.1/ identifier"`
+		Metas []struct {
+			Name    string `xml:"name,attr"`
+			Content string `xml:"content,attr"`
+		} `xml:"meta"`
+	} `xml:"metadata"`
+}
+
+// ParseCalibreOPF reads one Calibre metadata.opf file. The returned error
+// wraps ErrInvalidImport.
+func ParseCalibreOPF(r io.Reader) (*library.Record, error) {
+	var pkg opfPackage
+	if err := xml.NewDecoder(r).Decode(&pkg); err != nil {
+		return nil, fmt.Errorf("%w: cannot read OPF: %v", library.ErrInvalidImport, err)
+	}
+	m := pkg.Metadata
+	if len(m.Titles) == 0 || strings.TrimSpace(m.Titles[0]) == "" {
+		return nil, fmt.Errorf("%w: OPF has no title", library.ErrInvalidImport)
+	}
+
+	rec := &library.Record{
+		Title:   strings.TrimSpace(m.Titles[0]),
+		Author:  strings.Join(trimAll(m.Creators), ", "),
+		Shelves: []string{library.CalibreShelf},
+	}
+	for _, id := range m.Identifiers {
+		value := strings.TrimSpace(id.Value)
+		if strings.EqualFold(id.Scheme, "isbn") || strings.HasPrefix(strings.ToLower(value), "urn:isbn:") {
+			rec.ISBN = library.NormalizeISBN(strings.TrimPrefix(strings.ToLower(value), "urn:isbn:"))
+		}
+	}
+	for _, meta := range m.Metas {
+		// Calibre stores ratings out of 10, in half stars
+		if meta.Name == "calibre:rating" {
+			if v, err := strconv.ParseFloat(meta.Content, 64); err == nil && v > 0 {
+				rec.Rating = int(math.Min(5, math.Round(v/2)))
+			}
+		}
+	}
+	return rec, nil
+}
+
+// ParseCalibreZip reads every metadata.opf in a zip of a Calibre library
+// folder. The library's metadata.db is ignored. The returned error wraps
+// ErrInvalidImport.
+func ParseCalibreZip(data []byte) ([]*library.Record, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a zip file: %v", library.ErrInvalidImport, err)
+	}
+
+	var records []*library.Record
+	for _, f := range zr.File {
+		if path.Base(f.Name) != "metadata.opf" {
+			continue
+		}
+		if len(records) == library.MaxImportRows {
+			return nil, fmt.Errorf("%w: at most %d books can be imported at once", library.ErrInvalidImport, library.MaxImportRows)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", library.ErrInvalidImport, f.Name, err)
+		}
+		rec, err := ParseCalibreOPF(io.LimitReader(rc, 1<<20))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		rec.Row = len(records) + 1
+		records = append(records, rec)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the zip has no metadata.opf files; zip the whole Calibre library folder", library.ErrInvalidImport)
+	}
+	return records, nil
+}
+
+func trimAll(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,144 @@
+package library
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/library"
+)
+
+// ParseCalibreDB reads the books in a Calibre library's metadata.db, with
+// their authors, ISBNs and ratings. The returned error wraps
+// ErrInvalidImport.
+func ParseCalibreDB(data []byte) ([]*library.Record, error) {
+	db, err := openSQLite(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read metadata.db: %v", library.ErrInvalidImport, err)
+	}
+	for _, table := range []string{"books", "authors", "books_authors_link"} {
+		if !db.hasTable(table) {
+			return nil, fmt.Errorf("%w: not a Calibre metadata.db, it has no %s table", library.ErrInvalidImport, table)
+		}
+	}
+
+	c := &calibreDB{
+		db:      db,
+		authors: map[int64][]string{},
+		isbns:   map[int64]string{},
+		ratings: map[int64]int{},
+	}
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("%w: cannot read metadata.db: %v", library.ErrInvalidImport, err)
+	}
+
+	var records []*library.Record
+	err = db.rows("books", func(row sqliteRow) error {
+		id, _ := row["id"].(int64)
+		title, _ := row["title"].(string)
+		if title = strings.TrimSpace(title); title == "" {
+			return nil
+		}
+		if len(records) == library.MaxImportRows {
+			return fmt.Errorf("at most %d books can be imported at once", library.MaxImportRows)
+		}
+
+		rec := &library.Record{
+			Row:     len(records) + 1,
+			Title:   title,
+			Author:  strings.Join(c.authors[id], ", "),
+			ISBN:    c.isbns[id],
+			Rating:  c.ratings[id],
+			Shelves: []string{library.CalibreShelf},
+		}
+		if rec.ISBN == "" {
+			// older libraries keep the ISBN on the book itself
+			isbn, _ := row["isbn"].(string)
+			rec.ISBN = library.NormalizeISBN(isbn)
+		}
+		records = append(records, rec)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", library.ErrInvalidImport, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the metadata.db has no books", library.ErrInvalidImport)
+	}
+	return records, nil
+}
+
+// calibreDB holds the per-book metadata Calibre keeps in link tables,
+// keyed by book ID.
+type calibreDB struct {
+	db      *sqliteFile
+	authors map[int64][]string
+	isbns   map[int64]string
+	ratings map[int64]int
+}
+
+func (c *calibreDB) load() error {
+	names := map[int64]string{}
+	err := c.db.rows("authors", func(row sqliteRow) error {
+		id, _ := row["id"].(int64)
+		name, _ := row["name"].(string)
+		// Calibre stores commas in author names as pipes
+		names[id] = strings.TrimSpace(strings.ReplaceAll(name, "|", ","))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// links are in rowid order, which is the order Calibre lists authors in
+	err = c.db.rows("books_authors_link", func(row sqliteRow) error {
+		book, _ := row["book"].(int64)
+		author, _ := row["author"].(int64)
+		if name := names[author]; name != "" {
+			c.authors[book] = append(c.authors[book], name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.db.hasTable("identifiers") {
+		err := c.db.rows("identifiers", func(row sqliteRow) error {
+			book, _ := row["book"].(int64)
+			kind, _ := row["type"].(string)
+			val, _ := row["val"].(string)
+			if strings.EqualFold(kind, "isbn") && c.isbns[book] == "" {
+				c.isbns[book] = library.NormalizeISBN(val)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.db.hasTable("ratings") && c.db.hasTable("books_ratings_link") {
+		values := map[int64]int64{}
+		err := c.db.rows("ratings", func(row sqliteRow) error {
+			id, _ := row["id"].(int64)
+			values[id], _ = row["rating"].(int64)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		err = c.db.rows("books_ratings_link", func(row sqliteRow) error {
+			book, _ := row["book"].(int64)
+			rating, _ := row["rating"].(int64)
+			// Calibre stores ratings out of 10, in half stars
+			if v := values[rating]; v > 0 {
+				c.ratings[book] = int(math.Min(5, math.Round(float64(v)/2)))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package library
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/library"
+)
+
+// testdata/metadata.db is a Calibre schema with 300 books on 512-byte
+// pages, so its tables span several b-tree levels, and one title long
+// enough to spill onto overflow pages.
+func readMetadataDB(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/metadata.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseCalibreDB(t *testing.T) {
+	format, records, err := parseLibrary("metadata.db", readMetadataDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != library.FormatCalibreDB {
+		t.Errorf("format = %q, want %q", format, library.FormatCalibreDB)
+	}
+	if len(records) != 300 {
+		t.Fatalf("got %d records, want 300", len(records))
+	}
+	for i, rec := range records {
+		if rec.Row != i+1 || !slices.Equal(rec.Shelves, []string{library.CalibreShelf}) {
+			t.Fatalf("record %d: row %d, shelves %v", i, rec.Row, rec.Shelves)
+		}
+	}
+
+	want := []library.Record{
+		{Row: 1, Title: "Dune", Author: "Frank Herbert", ISBN: "9780441172719", Rating: 5},
+		{Row: 2, Title: "Good Omens", Author: "Terry Pratchett, Neil Gaiman", ISBN: "0552137030", Rating: 4},
+		{Row: 3, Title: "The " + strings.Repeat("Very ", 300) + "Long Book", Author: "Neil Gaiman"},
+		{Row: 300, Title: "Book 300", Author: "Author 0"},
+	}
+	for _, w := range want {
+		got := records[w.Row-1]
+		if got.Title != w.Title || got.Author != w.Author || got.ISBN != w.ISBN || got.Rating != w.Rating {
+			t.Errorf("row %d = %q by %q, isbn %q, rating %d; want %q by %q, isbn %q, rating %d",
+				w.Row, got.Title, got.Author, got.ISBN, got.Rating, w.Title, w.Author, w.ISBN, w.Rating)
+		}
+	}
+}
+
+func TestParseCalibreDBRejectsOtherFiles(t *testing.T) {
+	data := readMetadataDB(t)
+	tests := map[string][]byte{
+		"not sqlite": []byte("id,title\n1,Dune\n"),
+		"truncated":  data[:len(data)/2],
+	}
+	for name, file := range tests {
+		if _, err := ParseCalibreDB(file); !errors.Is(err, library.ErrInvalidImport) {
+			t.Errorf("%s: got %v, want ErrInvalidImport", name, err)
+		}
+	}
+}
+
+func TestParseCalibreDBSurvivesCorruption(t *testing.T) {
+	data := readMetadataDB(t)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		corrupt := slices.Clone(data)
+		for j := 0; j < 8; j++ {
+			corrupt[r.Intn(len(corrupt))] = byte(r.Intn(256))
+		}
+		// a corrupt file may still parse, but it must not panic
+		if _, err := ParseCalibreDB(corrupt); err != nil && !errors.Is(err, library.ErrInvalidImport) {
+			t.Fatalf("got %v, want ErrInvalidImport", err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package library
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/library"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+// goodreadsKinds maps Goodreads' exclusive shelves to built-in shelves.
+var goodreadsKinds = map[string]string{
+	"to-read":           shelf.KindWantToRead,
+	"currently-reading": shelf.KindReading,
+	"read":              shelf.KindFinished,
+}
+
+// ParseGoodreadsCSV reads a Goodreads library export. Only the Title column
+// is required; books on custom shelves keep those shelves. The returned
+// error wraps ErrInvalidImport.
+func ParseGoodreadsCSV(r io.Reader) ([]*library.Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	// Goodreads writes ISBNs as ="0441013597" without quoting the field
+	cr.LazyQuotes = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read the CSV header: %v", library.ErrInvalidImport, err)
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	if _, ok := col["Title"]; !ok {
+		return nil, fmt.Errorf("%w: not a Goodreads export, the Title column is missing", library.ErrInvalidImport)
+	}
+	field := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var records []*library.Record
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", library.ErrInvalidImport, err)
+		}
+		if len(records) == library.MaxImportRows {
+			return nil, fmt.Errorf("%w: at most %d books can be imported at once", library.ErrInvalidImport, library.MaxImportRows)
+		}
+
+		rec := &library.Record{
+			Row:    line,
+			Title:  field(row, "Title"),
+			Author: field(row, "Author"),
+			ISBN:   library.NormalizeISBN(field(row, "ISBN13")),
+			Shelf:  goodreadsKinds[field(row, "Exclusive Shelf")],
+		}
+		if rec.Title == "" {
+			continue
+		}
+		if rec.ISBN == "" {
+			rec.ISBN = library.NormalizeISBN(field(row, "ISBN"))
+		}
+		if rating, err := strconv.Atoi(field(row, "My Rating")); err == nil && rating > 0 && rating <= shelf.MaxRating {
+			rec.Rating = rating
+		}
+		for _, name := range strings.Split(field(row, "Bookshelves"), ",") {
+			name = strings.TrimSpace(name)
+			if _, exclusive := goodreadsKinds[name]; name != "" && !exclusive {
+				rec.Shelves = append(rec.Shelves, name)
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
@@ -0,0 +1,406 @@
+package library
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// sqliteFile reads tables out of an SQLite 3 database file held in memory.
+// It understands just enough of the file format to scan table b-trees:
+// there is no SQL, no index use and no journal or WAL recovery, which is
+// all an uploaded Calibre metadata.db needs.
+type sqliteFile struct {
+	data []byte
+	// pageSize and usable are the page size and the bytes of each page
+	// not reserved for extensions.
+	pageSize, usable int
+	tables           map[string]*sqliteTable
+}
+
+// sqliteTable is a table's root page and its column names in order. alias
+// is the index of the INTEGER PRIMARY KEY column, which is stored as the
+// rowid, or -1.
+type sqliteTable struct {
+	root    int
+	columns []string
+	alias   int
+}
+
+// sqliteRow maps column names to values: int64, float64, string, []byte or
+// nil.
+type sqliteRow map[string]any
+
+const (
+	sqliteHeader = "SQLite format 3\x00"
+	// sqliteMaxDepth bounds b-tree descent; real trees are a few levels
+	// deep.
+	sqliteMaxDepth = 32
+
+	pageInteriorTable = 0x05
+	pageLeafTable     = 0x0d
+)
+
+var errCorruptSQLite = errors.New("corrupt SQLite file")
+
+// openSQLite checks the file header and loads the schema.
+func openSQLite(data []byte) (*sqliteFile, error) {
+	if len(data) < 100 || string(data[:16]) != sqliteHeader {
+		return nil, errors.New("not an SQLite database")
+	}
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, errCorruptSQLite
+	}
+	if encoding := binary.BigEndian.Uint32(data[56:60]); encoding > 1 {
+		return nil, errors.New("only UTF-8 SQLite databases are supported")
+	}
+
+	db := &sqliteFile{
+		data:     data,
+		pageSize: pageSize,
+		usable:   pageSize - int(data[20]),
+		tables:   map[string]*sqliteTable{},
+	}
+	master := &sqliteTable{root: 1, columns: []string{"type", "name", "tbl_name", "rootpage", "sql"}, alias: -1}
+	err := db.scan(master, func(row sqliteRow) error {
+		if row["type"] != "table" {
+			return nil
+		}
+		name, _ := row["name"].(string)
+		root, _ := row["rootpage"].(int64)
+		sql, _ := row["sql"].(string)
+		if root < 1 || sql == "" {
+			return nil
+		}
+		columns, alias := parseColumns(sql)
+		db.tables[strings.ToLower(name)] = &sqliteTable{root: int(root), columns: columns, alias: alias}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// hasTable reports whether the database has a table called name.
+func (db *sqliteFile) hasTable(name string) bool {
+	_, ok := db.tables[strings.ToLower(name)]
+	return ok
+}
+
+// rows calls fn for each row of table, in rowid order.
+func (db *sqliteFile) rows(table string, fn func(sqliteRow) error) error {
+	t, ok := db.tables[strings.ToLower(table)]
+	if !ok {
+		return fmt.Errorf("no %s table", table)
+	}
+	return db.scan(t, fn)
+}
+
+func (db *sqliteFile) scan(t *sqliteTable, fn func(sqliteRow) error) error {
+	return db.walk(t, t.root, 0, map[int]bool{}, fn)
+}
+
+// page returns page n, counting from 1, and the offset of its b-tree
+// header, which follows the file header on page 1.
+func (db *sqliteFile) page(n int) ([]byte, int, error) {
+	start := (n - 1) * db.pageSize
+	if n < 1 || start+db.pageSize > len(db.data) {
+		return nil, 0, errCorruptSQLite
+	}
+	header := 0
+	if n == 1 {
+		header = 100
+	}
+	return db.data[start : start+db.pageSize], header, nil
+}
+
+// walk visits the b-tree rooted at page n. seen holds the tree and overflow
+// pages already visited: each belongs to one place in a table, so a page
+// reached twice means the file is corrupt.
+func (db *sqliteFile) walk(t *sqliteTable, n, depth int, seen map[int]bool, fn func(sqliteRow) error) error {
+	if depth > sqliteMaxDepth || seen[n] {
+		return errCorruptSQLite
+	}
+	seen[n] = true
+	page, h, err := db.page(n)
+	if err != nil {
+		return err
+	}
+	if h+12 > len(page) {
+		return errCorruptSQLite
+	}
+	kind := page[h]
+	cells := int(binary.BigEndian.Uint16(page[h+3 : h+5]))
+	pointers := h + 8
+	if kind == pageInteriorTable {
+		pointers = h + 12
+	}
+	if pointers+2*cells > len(page) {
+		return errCorruptSQLite
+	}
+
+	for i := 0; i < cells; i++ {
+		off := int(binary.BigEndian.Uint16(page[pointers+2*i:]))
+		if off >= len(page) {
+			return errCorruptSQLite
+		}
+		switch kind {
+		case pageInteriorTable:
+			if off+4 > len(page) {
+				return errCorruptSQLite
+			}
+			child := int(binary.BigEndian.Uint32(page[off:]))
+			if err := db.walk(t, child, depth+1, seen, fn); err != nil {
+				return err
+			}
+		case pageLeafTable:
+			row, err := db.leafCell(t, page, off, seen)
+			if err != nil {
+				return err
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		default:
+			return errCorruptSQLite
+		}
+	}
+	if kind == pageInteriorTable {
+		right := int(binary.BigEndian.Uint32(page[h+8 : h+12]))
+		return db.walk(t, right, depth+1, seen, fn)
+	}
+	return nil
+}
+
+// leafCell decodes the table leaf cell at off, following overflow pages for
+// a payload too big for the page.
+func (db *sqliteFile) leafCell(t *sqliteTable, page []byte, off int, seen map[int]bool) (sqliteRow, error) {
+	size, n := readVarint(page[off:])
+	if n == 0 || size < 0 || size > int64(len(db.data)) {
+		return nil, errCorruptSQLite
+	}
+	off += n
+	rowid, n := readVarint(page[off:])
+	if n == 0 {
+		return nil, errCorruptSQLite
+	}
+	off += n
+
+	total := int(size)
+	local := db.localPayload(total)
+	if off+local > len(page) {
+		return nil, errCorruptSQLite
+	}
+	payload := page[off : off+local]
+	if local < total {
+		if off+local+4 > len(page) {
+			return nil, errCorruptSQLite
+		}
+		full := make([]byte, 0, total)
+		full = append(full, payload...)
+		next := int(binary.BigEndian.Uint32(page[off+local:]))
+		for len(full) < total {
+			if seen[next] {
+				return nil, errCorruptSQLite
+			}
+			seen[next] = true
+			overflow, _, err := db.page(next)
+			if err != nil {
+				return nil, err
+			}
+			chunk := overflow[4:db.usable]
+			if rest := total - len(full); len(chunk) > rest {
+				chunk = chunk[:rest]
+			}
+			full = append(full, chunk...)
+			next = int(binary.BigEndian.Uint32(overflow))
+		}
+		payload = full
+	}
+
+	values, err := decodeRecord(payload)
+	if err != nil {
+		return nil, err
+	}
+	row := sqliteRow{}
+	for i, name := range t.columns {
+		var v any
+		if i < len(values) {
+			v = values[i]
+		}
+		if i == t.alias && v == nil {
+			v = rowid
+		}
+		row[name] = v
+	}
+	return row, nil
+}
+
+// localPayload returns how much of a table leaf payload of size total is
+// stored on the page itself, per the SQLite file format.
+func (db *sqliteFile) localPayload(total int) int {
+	maxLocal := db.usable - 35
+	if total <= maxLocal {
+		return total
+	}
+	minLocal := (db.usable-12)*32/255 - 23
+	local := minLocal + (total-minLocal)%(db.usable-4)
+	if local > maxLocal {
+		return minLocal
+	}
+	return local
+}
+
+// decodeRecord decodes a record: a header of serial types followed by the
+// values they describe.
+func decodeRecord(payload []byte) ([]any, error) {
+	headerSize, n := readVarint(payload)
+	if n == 0 || headerSize < int64(n) || headerSize > int64(len(payload)) {
+		return nil, errCorruptSQLite
+	}
+	header, body := payload[n:headerSize], payload[headerSize:]
+
+	var values []any
+	for len(header) > 0 {
+		serial, n := readVarint(header)
+		if n == 0 {
+			return nil, errCorruptSQLite
+		}
+		header = header[n:]
+
+		size := serialSize(serial)
+		if size < 0 || size > len(body) {
+			return nil, errCorruptSQLite
+		}
+		field := body[:size]
+		body = body[size:]
+
+		switch {
+		case serial == 0:
+			values = append(values, nil)
+		case serial >= 1 && serial <= 6:
+			values = append(values, readInt(field))
+		case serial == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(field)))
+		case serial == 8 || serial == 9:
+			values = append(values, serial-8)
+		case serial >= 12 && serial%2 == 0:
+			values = append(values, bytes.Clone(field))
+		case serial >= 13:
+			values = append(values, string(field))
+		default:
+			return nil, errCorruptSQLite
+		}
+	}
+	return values, nil
+}
+
+// serialSize returns the size in bytes of a value with the given serial
+// type, or -1 for a reserved type.
+func serialSize(serial int64) int {
+	switch {
+	case serial >= 0 && serial <= 4:
+		return int(serial)
+	case serial == 5:
+		return 6
+	case serial == 6 || serial == 7:
+		return 8
+	case serial == 8 || serial == 9:
+		return 0
+	case serial >= 12:
+		return int((serial - 12) / 2)
+	default:
+		return -1
+	}
+}
+
+// readInt reads a big-endian two's complement integer of 1 to 8 bytes.
+func readInt(b []byte) int64 {
+	v := int64(int8(b[0]))
+	for _, c := range b[1:] {
+		v = v<<8 | int64(c)
+	}
+	return v
+}
+
+// readVarint reads an SQLite varint and returns it with its length, or a
+// length of 0 if b is too short.
+func readVarint(b []byte) (int64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(b) {
+			return 0, 0
+		}
+		if i == 8 {
+			return int64(v<<8 | uint64(b[i])), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i] < 0x80 {
+			return int64(v), i + 1
+		}
+	}
+	return 0, 0
+}
+
+// parseColumns extracts the column names from a CREATE TABLE statement and
+// the index of its INTEGER PRIMARY KEY column, or -1.
+func parseColumns(sql string) ([]string, int) {
+	open, end := strings.Index(sql, "("), strings.LastIndex(sql, ")")
+	if open < 0 || end <= open {
+		return nil, -1
+	}
+
+	var columns []string
+	alias := -1
+	for _, def := range splitTopLevel(sql[open+1 : end]) {
+		fields := strings.Fields(def)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+			continue
+		}
+		upper := strings.ToUpper(strings.Join(fields[1:], " "))
+		if strings.HasPrefix(upper, "INTEGER PRIMARY KEY") {
+			alias = len(columns)
+		}
+		columns = append(columns, strings.Trim(fields[0], "\"'`[]"))
+	}
+	return columns, alias
+}
+
+// splitTopLevel splits s on commas outside parentheses and quotes.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '[':
+			quote = ']'
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
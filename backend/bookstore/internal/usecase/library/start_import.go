@@ -0,0 +1,291 @@
+package library
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/catalog"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/library"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+// progressEvery is how many records are processed between progress saves.
+const progressEvery = 20
+
+// StartImportUseCase parses an uploaded library and imports it onto the
+// user's shelves in the background.
+type StartImportUseCase struct {
+	repo     library.ImportRepository
+	shelves  shelf.Repository
+	books    book.BookRepository
+	provider catalog.ExternalProvider
+}
+
+// NewStartImportUseCase creates the usecase. provider may be nil, in which
+// case only books in the local catalog are matched.
+func NewStartImportUseCase(repo library.ImportRepository, shelves shelf.Repository, books book.BookRepository, provider catalog.ExternalProvider) *StartImportUseCase {
+	return &StartImportUseCase{repo: repo, shelves: shelves, books: books, provider: provider}
+}
+
+// Execute parses the file straight away, so a bad upload fails the request,
+// then starts the import job and returns it for polling.
+func (uc *StartImportUseCase) Execute(ctx context.Context, filename string, data []byte) (*library.Job, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	format, records, err := parseLibrary(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file has no books", library.ErrInvalidImport)
+	}
+
+	job, err := uc.repo.CreateJob(&library.Job{
+		UserID:   p.UserID,
+		Format:   format,
+		Filename: filepath.Base(filename),
+		Status:   library.StatusPending,
+		Total:    len(records),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go uc.run(*job, records)
+	return job, nil
+}
+
+// parseLibrary picks a parser by file extension.
+func parseLibrary(filename string, data []byte) (string, []*library.Record, error) {
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".csv":
+		records, err := ParseGoodreadsCSV(bytes.NewReader(data))
+		return library.FormatGoodreadsCSV, records, err
+	case ".opf":
+		rec, err := ParseCalibreOPF(bytes.NewReader(data))
+		if err != nil {
+			return "", nil, err
+		}
+		rec.Row = 1
+		return library.FormatCalibreOPF, []*library.Record{rec}, nil
+	case ".zip":
+		records, err := ParseCalibreZip(data)
+		return library.FormatCalibreOPF, records, err
+	case ".db":
+		records, err := ParseCalibreDB(data)
+		return library.FormatCalibreDB, records, err
+	default:
+		return "", nil, fmt.Errorf("%w: upload a Goodreads .csv export, a Calibre .opf or metadata.db, or a zipped Calibre library", library.ErrUnsupportedFile)
+	}
+}
+
+// run imports records, saving the job's progress as it goes. job is a copy
+// owned by this goroutine.
+func (uc *StartImportUseCase) run(job library.Job, records []*library.Record) {
+	ctx, cancel := context.WithTimeout(context.Background(), library.MaxJobDuration)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("import %s: panic: %v", job.ID, r)
+			uc.finish(&job, fmt.Errorf("internal error"))
+		}
+	}()
+
+	job.Status = library.StatusRunning
+	uc.save(&job)
+
+	imp, err := uc.newImporter(job.UserID)
+	if err != nil {
+		uc.finish(&job, err)
+		return
+	}
+	for _, rec := range records {
+		if ctx.Err() != nil {
+			uc.finish(&job, fmt.Errorf("import timed out after %d of %d books", job.Processed, job.Total))
+			return
+		}
+		if reason := imp.importRecord(ctx, rec); reason != "" {
+			job.Unmatched = append(job.Unmatched, &library.Unmatched{Record: *rec, Reason: reason})
+		} else {
+			job.Matched++
+		}
+		job.Processed++
+		if job.Processed%progressEvery == 0 {
+			uc.save(&job)
+		}
+	}
+	uc.finish(&job, nil)
+}
+
+func (uc *StartImportUseCase) finish(job *library.Job, err error) {
+	now := time.Now()
+	job.Status, job.FinishedAt = library.StatusCompleted, &now
+	if err != nil {
+		job.Status, job.Error = library.StatusFailed, err.Error()
+	}
+	uc.save(job)
+}
+
+func (uc *StartImportUseCase) save(job *library.Job) {
+	if err := uc.repo.UpdateJob(job); err != nil {
+		log.Printf("import %s: save progress: %v", job.ID, err)
+	}
+}
+
+// importer matches records and shelves them for one user.
+type importer struct {
+	uc     *StartImportUseCase
+	userID int
+	// local indexes the catalog by library.MatchKey.
+	local map[string]*book.Book
+	// shelfIDs maps built-in kinds and lower-cased custom shelf names to
+	// shelf IDs.
+	shelfIDs map[string]string
+}
+
+func (uc *StartImportUseCase) newImporter(userID int) (*importer, error) {
+	books, err := uc.books.GetAllBooks()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.shelves.EnsureBuiltins(userID); err != nil {
+		return nil, err
+	}
+	shelves, err := uc.shelves.ListShelves(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &importer{uc: uc, userID: userID, local: map[string]*book.Book{}, shelfIDs: map[string]string{}}
+	for _, b := range books {
+		imp.local[library.MatchKey(b.Title, b.Author)] = b
+	}
+	for _, s := range shelves {
+		key := s.Kind
+		if key == shelf.KindCustom {
+			key = strings.ToLower(s.Name)
+		}
+		imp.shelfIDs[key] = s.ID
+	}
+	return imp, nil
+}
+
+// importRecord shelves one record and returns why it was not imported, or
+// "" on success.
+func (imp *importer) importRecord(ctx context.Context, rec *library.Record) string {
+	item, reason := imp.match(ctx, rec)
+	if item == nil {
+		return reason
+	}
+	item.Rating = rec.Rating
+	if err := item.Normalize(); err != nil {
+		return err.Error()
+	}
+
+	targets := append([]string{}, rec.Shelves...)
+	if rec.Shelf != "" || len(targets) == 0 {
+		kind := rec.Shelf
+		if kind == "" {
+			kind = shelf.KindWantToRead
+		}
+		targets = append([]string{kind}, targets...)
+	}
+
+	shelved := 0
+	for _, name := range targets {
+		shelfID, err := imp.shelfID(name)
+		if err != nil {
+			log.Printf("import: shelf %q for user %d: %v", name, imp.userID, err)
+			continue
+		}
+		it := *item
+		it.ShelfID = shelfID
+		if _, err := imp.uc.shelves.AddItem(imp.userID, &it); err != nil {
+			return "could not shelve: " + err.Error()
+		}
+		shelved++
+	}
+	if shelved == 0 {
+		return "none of its shelves could be created"
+	}
+	return ""
+}
+
+// match finds rec in the local catalog, then by ISBN and by title and
+// author at the external provider.
+func (imp *importer) match(ctx context.Context, rec *library.Record) (*shelf.Item, string) {
+	key := library.MatchKey(rec.Title, rec.Author)
+	if b, ok := imp.local[key]; ok {
+		return &shelf.Item{Source: shelf.SourceLocal, ExternalID: b.ID, Title: b.Title, Author: b.Author, CoverUrl: b.CoverUrl}, ""
+	}
+	if imp.uc.provider == nil {
+		return nil, "not found in the catalog"
+	}
+
+	if rec.ISBN != "" {
+		ext, err := imp.uc.provider.LookupISBN(ctx, rec.ISBN)
+		if err == nil {
+			return externalItem(ext), ""
+		}
+		if !errors.Is(err, catalog.ErrExternalNotFound) {
+			return nil, "lookup failed: " + err.Error()
+		}
+	}
+
+	results, err := imp.uc.provider.Search(ctx, strings.TrimSpace(rec.Title+" "+rec.Author), 5)
+	if err != nil {
+		return nil, "search failed: " + err.Error()
+	}
+	for _, ext := range results {
+		if library.MatchKey(ext.Title, ext.Author()) == key {
+			return externalItem(ext), ""
+		}
+	}
+	return nil, "not found in the catalog or at the external provider"
+}
+
+// shelfID returns the ID of a built-in shelf kind or custom shelf name,
+// creating the custom shelf if the user does not have it yet.
+func (imp *importer) shelfID(name string) (string, error) {
+	key := name
+	if !shelf.IsBuiltin(name) {
+		key = strings.ToLower(name)
+	}
+	if id, ok := imp.shelfIDs[key]; ok {
+		return id, nil
+	}
+
+	name, err := shelf.NormalizeName(name)
+	if err != nil {
+		return "", err
+	}
+	s, err := imp.uc.shelves.CreateShelf(&shelf.Shelf{UserID: imp.userID, Name: name})
+	if err != nil {
+		return "", err
+	}
+	imp.shelfIDs[key] = s.ID
+	return s.ID, nil
+}
+
+func externalItem(ext *catalog.ExternalBook) *shelf.Item {
+	return &shelf.Item{
+		Source:     ext.Source,
+		ExternalID: ext.ID,
+		Title:      ext.Title,
+		Author:     ext.Author(),
+		CoverUrl:   ext.CoverUrl,
+		ISBN:       ext.ISBN(),
+	}
+}
@@ -1,17 +1,62 @@
 package note
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
 	"github.com/bereke1t2/bookstore/internal/domain/note"
+	activityuc "github.com/bereke1t2/bookstore/internal/usecase/activity"
+	"github.com/google/uuid"
 )
 
 type CreateNoteUseCase struct {
-	repo note.NoteRepository
+	repo     note.NoteRepository
+	activity activity.Repository
+}
+
+func NewCreateNoteUseCase(repo note.NoteRepository, activity activity.Repository) *CreateNoteUseCase {
+	return &CreateNoteUseCase{repo: repo, activity: activity}
 }
 
-func NewCreateNoteUseCase(repo note.NoteRepository) *CreateNoteUseCase {
-	return &CreateNoteUseCase{repo: repo}
+// Execute stores n as a note of the authenticated user, whatever UserID it
+// carries. A client-generated UUID in n.ID is kept, and creating the same
+// note again returns the stored one so offline clients can safely retry.
+func (uc *CreateNoteUseCase) Execute(ctx context.Context, n *note.Note) (*note.Note, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n.UserID = p.UserID
+	if err := validateClientID(n.ID); err != nil {
+		return nil, err
+	}
+	if err := n.Normalize(); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.repo.Create(n)
+	if errors.Is(err, note.ErrNoteExists) {
+		if existing, getErr := uc.repo.GetByID(n.ID, p.UserID); getErr == nil {
+			return existing, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	activityuc.Record(uc.activity, activity.NoteCreated(p.UserID, created.ID, false, created.CreatedAt))
+	return created, nil
 }
 
-func (uc *CreateNoteUseCase) Execute(n *note.Note) (*note.Note, error) {
-	return uc.repo.Create(n)
+// validateClientID accepts an empty ID, which the repository fills in, or a UUID.
+func validateClientID(id string) error {
+	if id == "" {
+		return nil
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("%w: id must be a UUID", note.ErrInvalidNote)
+	}
+	return nil
 }
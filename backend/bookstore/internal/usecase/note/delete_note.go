@@ -1,6 +1,9 @@
 package note
 
 import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
 	"github.com/bereke1t2/bookstore/internal/domain/note"
 )
 
@@ -12,6 +15,10 @@ func NewDeleteNoteUseCase(repo note.NoteRepository) *DeleteNoteUseCase {
 	return &DeleteNoteUseCase{repo: repo}
 }
 
-func (uc *DeleteNoteUseCase) Execute(noteID string, userID int) error {
-	return uc.repo.Delete(noteID, userID)
+func (uc *DeleteNoteUseCase) Execute(ctx context.Context, noteID string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return uc.repo.Delete(noteID, p.UserID)
 }
@@ -0,0 +1,282 @@
+package note
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/note"
+)
+
+// aiTag marks AI-generated notes in exports that have no dedicated field for it.
+const aiTag = "ai-generated"
+
+type ExportNotesUseCase struct {
+	repo  note.NoteRepository
+	books book.BookRepository
+}
+
+func NewExportNotesUseCase(repo note.NoteRepository, books book.BookRepository) *ExportNotesUseCase {
+	return &ExportNotesUseCase{repo: repo, books: books}
+}
+
+type jsonExport struct {
+	ExportedAt time.Time    `json:"exported_at"`
+	Books      []*bookNotes `json:"books"`
+}
+
+// bookNotes is one book's notes in reading order.
+type bookNotes struct {
+	Book  note.BookInfo `json:"book"`
+	Notes []*note.Note  `json:"notes"`
+}
+
+// Execute renders the authenticated user's notes for one book, or for every
+// book when bookID is empty.
+func (uc *ExportNotesUseCase) Execute(ctx context.Context, bookID string, format note.ExportFormat) (*note.Export, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []*note.Note
+	if bookID != "" {
+		notes, err = uc.repo.GetByBookID(bookID, p.UserID, note.Filter{})
+	} else {
+		notes, err = uc.repo.GetByUserID(p.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	groups, err := uc.groupByBook(notes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	name := "all-books"
+	if bookID != "" {
+		name = bookID
+		if len(groups) > 0 && groups[0].Book.Title != "" {
+			name = groups[0].Book.Title
+		}
+	}
+	filename := fmt.Sprintf("notes-%s-%s", slugify(name), now.Format("2006-01-02"))
+
+	switch format {
+	case note.ExportJSON:
+		body, err := json.MarshalIndent(jsonExport{ExportedAt: now, Books: groups}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &note.Export{Filename: filename + ".json", ContentType: "application/json", Body: body}, nil
+	case note.ExportAnki:
+		body, err := renderAnki(groups)
+		if err != nil {
+			return nil, err
+		}
+		return &note.Export{Filename: filename + ".csv", ContentType: "text/csv; charset=utf-8", Body: body}, nil
+	default:
+		body := renderMarkdown(groups, bookID == "")
+		return &note.Export{Filename: filename + ".md", ContentType: "text/markdown; charset=utf-8", Body: body}, nil
+	}
+}
+
+// groupByBook splits notes per book, looks up each book's metadata and sorts
+// every book's notes by page, then by creation time.
+func (uc *ExportNotesUseCase) groupByBook(notes []*note.Note) ([]*bookNotes, error) {
+	var groups []*bookNotes
+	byID := make(map[string]*bookNotes)
+	for _, n := range notes {
+		g, ok := byID[n.BookID]
+		if !ok {
+			info := note.BookInfo{ID: n.BookID}
+			b, err := uc.books.GetBookByID(n.BookID)
+			if err != nil {
+				return nil, err
+			}
+			if b != nil {
+				info.Title, info.Author, info.CoverUrl = b.Title, b.Author, b.CoverUrl
+			}
+			g = &bookNotes{Book: info}
+			byID[n.BookID] = g
+			groups = append(groups, g)
+		}
+		g.Notes = append(g.Notes, n)
+	}
+
+	for _, g := range groups {
+		sort.SliceStable(g.Notes, func(i, j int) bool {
+			a, b := g.Notes[i], g.Notes[j]
+			if pa, pb := pageOf(a), pageOf(b); pa != pb {
+				return pa < pb
+			}
+			return a.CreatedAt.Before(b.CreatedAt)
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return strings.ToLower(bookTitle(groups[i].Book)) < strings.ToLower(bookTitle(groups[j].Book))
+	})
+	return groups, nil
+}
+
+// pageOf orders notes without a page after every paged note.
+func pageOf(n *note.Note) int {
+	if n.PageNumber == nil {
+		return int(^uint(0) >> 1)
+	}
+	return *n.PageNumber
+}
+
+func bookTitle(b note.BookInfo) string {
+	if b.Title != "" {
+		return b.Title
+	}
+	return "Book " + b.ID
+}
+
+// renderMarkdown writes one section per page, with a top-level heading per
+// book when several books are exported.
+func renderMarkdown(groups []*bookNotes, allBooks bool) []byte {
+	var buf bytes.Buffer
+	level := "#"
+	if allBooks {
+		buf.WriteString("# My notes\n\n")
+		level = "##"
+	}
+
+	for _, g := range groups {
+		fmt.Fprintf(&buf, "%s %s\n\n", level, bookTitle(g.Book))
+		if g.Book.Author != "" {
+			fmt.Fprintf(&buf, "_by %s_\n\n", g.Book.Author)
+		}
+
+		section := ""
+		for _, n := range g.Notes {
+			heading := "Other notes"
+			if n.PageNumber != nil {
+				heading = fmt.Sprintf("Page %d", *n.PageNumber)
+			}
+			if heading != section {
+				fmt.Fprintf(&buf, "%s# %s\n\n", level, heading)
+				section = heading
+			}
+			writeMarkdownNote(&buf, n)
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeMarkdownNote(buf *bytes.Buffer, n *note.Note) {
+	if n.Quote != "" {
+		for _, line := range strings.Split(n.Quote, "\n") {
+			fmt.Fprintf(buf, "> %s\n", line)
+		}
+		buf.WriteString("\n")
+	}
+	if n.Content != "" {
+		if n.IsAIGenerated {
+			buf.WriteString("**AI note:** ")
+		}
+		buf.WriteString(n.Content + "\n\n")
+	}
+	if n.Comment != "" {
+		fmt.Fprintf(buf, "_Comment:_ %s\n\n", n.Comment)
+	}
+
+	var meta []string
+	if n.Color != "" {
+		meta = append(meta, "color: "+n.Color)
+	}
+	for _, tag := range n.Tags {
+		meta = append(meta, "#"+ankiTag(tag))
+	}
+	if n.IsAIGenerated {
+		meta = append(meta, "#"+aiTag)
+	}
+	if len(meta) > 0 {
+		fmt.Fprintf(buf, "<sub>%s</sub>\n\n", strings.Join(meta, " · "))
+	}
+	buf.WriteString("---\n\n")
+}
+
+// renderAnki writes a CSV that Anki imports directly: the header lines set
+// the separator, enable HTML fields and mark the tags column.
+func renderAnki(groups []*bookNotes) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("#separator:Comma\n#html:true\n#tags column:3\n")
+
+	w := csv.NewWriter(&buf)
+	for _, g := range groups {
+		title := html.EscapeString(bookTitle(g.Book))
+		for _, n := range g.Notes {
+			location := title
+			if n.PageNumber != nil {
+				location = fmt.Sprintf("%s, p. %d", title, *n.PageNumber)
+			}
+
+			front := "<i>" + location + "</i>"
+			if n.Quote != "" {
+				front = htmlText(n.Quote) + "<br><br>" + front
+			}
+			back := []string{htmlText(n.Content)}
+			if n.Comment != "" {
+				back = append(back, "<i>"+htmlText(n.Comment)+"</i>")
+			}
+
+			tags := []string{slugify(bookTitle(g.Book))}
+			for _, tag := range n.Tags {
+				tags = append(tags, ankiTag(tag))
+			}
+			if n.IsAIGenerated {
+				tags = append(tags, aiTag)
+			}
+
+			record := []string{front, strings.Join(nonEmpty(back), "<br><br>"), strings.Join(tags, " ")}
+			if err := w.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func htmlText(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\n", "<br>")
+}
+
+func nonEmpty(parts []string) []string {
+	out := parts[:0]
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ankiTag makes a tag safe for Anki and Markdown, which both split tags on
+// whitespace.
+func ankiTag(tag string) string {
+	return strings.Join(strings.Fields(tag), "_")
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slugify(s string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if slug == "" {
+		return "notes"
+	}
+	return slug
+}
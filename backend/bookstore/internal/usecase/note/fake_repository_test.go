@@ -0,0 +1,149 @@
+package note
+
+import (
+	"sort"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/note"
+	"github.com/google/uuid"
+)
+
+// fakeNoteRepository keeps notes in memory with the same ownership and
+// versioning rules as the Postgres store.
+type fakeNoteRepository struct {
+	notes map[string]*note.Note
+	seq   map[string]int64
+	next  int64
+}
+
+func newFakeNoteRepository() *fakeNoteRepository {
+	return &fakeNoteRepository{notes: map[string]*note.Note{}, seq: map[string]int64{}}
+}
+
+func (f *fakeNoteRepository) touch(id string) {
+	f.next++
+	f.seq[id] = f.next
+}
+
+func (f *fakeNoteRepository) Create(n *note.Note) (*note.Note, error) {
+	if n.ID == "" {
+		n.ID = uuid.New().String()
+	}
+	if _, ok := f.notes[n.ID]; ok {
+		return nil, note.ErrNoteExists
+	}
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	if n.UpdatedAt.IsZero() {
+		n.UpdatedAt = n.CreatedAt
+	}
+	if n.Version < 1 {
+		n.Version = 1
+	}
+	stored := *n
+	f.notes[n.ID] = &stored
+	f.touch(n.ID)
+	created := stored
+	return &created, nil
+}
+
+func (f *fakeNoteRepository) GetByID(noteID string, userID int) (*note.Note, error) {
+	n, err := f.GetByIDIncludingDeleted(noteID, userID)
+	if err != nil || n.DeletedAt != nil {
+		return nil, note.ErrNoteNotFound
+	}
+	return n, nil
+}
+
+func (f *fakeNoteRepository) GetByIDIncludingDeleted(noteID string, userID int) (*note.Note, error) {
+	n, ok := f.notes[noteID]
+	if !ok || n.UserID != userID {
+		return nil, note.ErrNoteNotFound
+	}
+	found := *n
+	return &found, nil
+}
+
+func (f *fakeNoteRepository) GetByBookID(bookID string, userID int, filter note.Filter) ([]*note.Note, error) {
+	var notes []*note.Note
+	for _, n := range f.live(userID) {
+		if n.BookID == bookID {
+			notes = append(notes, n)
+		}
+	}
+	return notes, nil
+}
+
+func (f *fakeNoteRepository) GetByUserID(userID int) ([]*note.Note, error) {
+	return f.live(userID), nil
+}
+
+func (f *fakeNoteRepository) live(userID int) []*note.Note {
+	var notes []*note.Note
+	for _, n := range f.notes {
+		if n.UserID == userID && n.DeletedAt == nil {
+			found := *n
+			notes = append(notes, &found)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].ID < notes[j].ID })
+	return notes
+}
+
+func (f *fakeNoteRepository) Update(n *note.Note) (*note.Note, error) {
+	stored, ok := f.notes[n.ID]
+	if !ok || stored.UserID != n.UserID {
+		return nil, note.ErrNoteNotFound
+	}
+	if stored.Version != n.Version {
+		return nil, note.ErrNoteVersionConflict
+	}
+	updated := *n
+	updated.CreatedAt = stored.CreatedAt
+	updated.Version++
+	if updated.UpdatedAt.IsZero() {
+		updated.UpdatedAt = time.Now()
+	}
+	f.notes[n.ID] = &updated
+	f.touch(n.ID)
+	result := updated
+	return &result, nil
+}
+
+func (f *fakeNoteRepository) Delete(noteID string, userID int) error {
+	n, ok := f.notes[noteID]
+	if !ok || n.UserID != userID || n.DeletedAt != nil {
+		return note.ErrNoteNotFound
+	}
+	now := time.Now()
+	n.DeletedAt, n.UpdatedAt = &now, now
+	n.Version++
+	f.touch(noteID)
+	return nil
+}
+
+func (f *fakeNoteRepository) Search(userID int, query note.SearchQuery) ([]*note.SearchResult, error) {
+	return []*note.SearchResult{}, nil
+}
+
+func (f *fakeNoteRepository) GetChanges(userID int, since note.Cursor, limit int) (*note.ChangeSet, error) {
+	changes := &note.ChangeSet{Notes: []*note.Note{}, Cursor: since}
+	var ids []string
+	for id, n := range f.notes {
+		if n.UserID == userID && f.seq[id] > since.Seq {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return f.seq[ids[i]] < f.seq[ids[j]] })
+	for _, id := range ids {
+		if len(changes.Notes) == limit {
+			changes.HasMore = true
+			break
+		}
+		n := *f.notes[id]
+		changes.Notes = append(changes.Notes, &n)
+		changes.Cursor = note.Cursor{Seq: f.seq[id]}
+	}
+	return changes, nil
+}
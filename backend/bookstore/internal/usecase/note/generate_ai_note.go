@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
 	"github.com/bereke1t2/bookstore/internal/domain/note"
+	activityuc "github.com/bereke1t2/bookstore/internal/usecase/activity"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
 	"github.com/google/generative-ai-go/genai"
 )
 
@@ -14,7 +18,9 @@ type AINoteSummarizer interface {
 	Summarize(ctx context.Context, text string) (string, error)
 }
 
-// GeminiSummarizer wraps a genai.GenerativeModel to summarize text.
+// GeminiSummarizer wraps a genai.GenerativeModel to summarize text. The
+// model may be shared with other features, so it is never changed; each
+// call generates with a copy.
 type GeminiSummarizer struct {
 	model *genai.GenerativeModel
 }
@@ -24,9 +30,12 @@ func NewGeminiSummarizer(model *genai.GenerativeModel) *GeminiSummarizer {
 }
 
 func (s *GeminiSummarizer) Summarize(ctx context.Context, text string) (string, error) {
-	s.model.SetMaxOutputTokens(1024)
-	s.model.SetTemperature(0.3)
-	s.model.ResponseMIMEType = "text/plain"
+	model := *s.model
+	model.GenerationConfig = genai.GenerationConfig{
+		MaxOutputTokens:  genai.Ptr[int32](1024),
+		Temperature:      genai.Ptr[float32](0.3),
+		ResponseMIMEType: "text/plain",
+	}
 
 	prompt := fmt.Sprintf(`You are a helpful reading assistant. The user selected the following text from a book:
 "%s"
@@ -39,7 +48,7 @@ CRITICAL INSTRUCTIONS:
 - Use plain text only.
 - Max 150 words.`, text)
 
-	resp, err := s.model.GenerateContent(ctx, genai.Text(prompt))
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
 		if strings.Contains(err.Error(), "429") {
 			return "", fmt.Errorf("AI rate limit reached. Please wait a moment and try again")
@@ -64,26 +73,49 @@ CRITICAL INSTRUCTIONS:
 type GenerateAINoteUseCase struct {
 	repo       note.NoteRepository
 	summarizer AINoteSummarizer
+	activity   activity.Repository
+	access     *entitlementuc.CheckAccessUseCase
 }
 
-func NewGenerateAINoteUseCase(repo note.NoteRepository, summarizer AINoteSummarizer) *GenerateAINoteUseCase {
-	return &GenerateAINoteUseCase{repo: repo, summarizer: summarizer}
+func NewGenerateAINoteUseCase(repo note.NoteRepository, summarizer AINoteSummarizer, activity activity.Repository, access *entitlementuc.CheckAccessUseCase) *GenerateAINoteUseCase {
+	return &GenerateAINoteUseCase{repo: repo, summarizer: summarizer, activity: activity, access: access}
 }
 
-func (uc *GenerateAINoteUseCase) Execute(ctx context.Context, userID int, bookID string, selectedText string) (*note.Note, error) {
+// Execute explains selectedText with the AI and stores the explanation as a
+// highlight of selectedText at anchor. Only readers entitled to the book
+// may ask, so paid text is not sent to the AI on behalf of anyone else.
+func (uc *GenerateAINoteUseCase) Execute(ctx context.Context, bookID string, selectedText string, anchor note.Anchor) (*note.Note, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.AuthorizeID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
 	// Generate AI summary
 	summary, err := uc.summarizer.Summarize(ctx, selectedText)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create note with AI-generated content
+	// Create note with AI-generated content, keeping the source quote alongside it
 	n := &note.Note{
-		UserID:        userID,
+		UserID:        p.UserID,
 		BookID:        bookID,
+		Anchor:        anchor,
+		Quote:         selectedText,
 		Content:       summary,
 		IsAIGenerated: true,
 	}
+	if err := n.Normalize(); err != nil {
+		return nil, err
+	}
 
-	return uc.repo.Create(n)
+	created, err := uc.repo.Create(n)
+	if err != nil {
+		return nil, err
+	}
+	activityuc.Record(uc.activity, activity.NoteCreated(p.UserID, created.ID, true, created.CreatedAt))
+	return created, nil
 }
@@ -1,6 +1,9 @@
 package note
 
 import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
 	"github.com/bereke1t2/bookstore/internal/domain/note"
 )
 
@@ -12,6 +15,10 @@ func NewGetNotesUseCase(repo note.NoteRepository) *GetNotesUseCase {
 	return &GetNotesUseCase{repo: repo}
 }
 
-func (uc *GetNotesUseCase) Execute(bookID string, userID int) ([]*note.Note, error) {
-	return uc.repo.GetByBookID(bookID, userID)
+func (uc *GetNotesUseCase) Execute(ctx context.Context, bookID string, filter note.Filter) ([]*note.Note, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetByBookID(bookID, p.UserID, filter)
 }
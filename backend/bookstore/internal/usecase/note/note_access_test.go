@@ -0,0 +1,68 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/note"
+)
+
+func asUser(userID int, roles ...string) context.Context {
+	if len(roles) == 0 {
+		roles = []string{identity.RoleUser}
+	}
+	return identity.WithPrincipal(context.Background(), identity.Principal{UserID: userID, Roles: roles})
+}
+
+func TestNotesAreScopedToThePrincipal(t *testing.T) {
+	repo := newFakeNoteRepository()
+	alice, bob := asUser(1), asUser(2)
+
+	created, err := NewCreateNoteUseCase(repo, nil).Execute(alice, &note.Note{UserID: 2, BookID: "book-1", Content: "mine"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.UserID != 1 {
+		t.Fatalf("note stored for user %d, want the caller 1", created.UserID)
+	}
+
+	notes, err := NewGetNotesUseCase(repo).Execute(bob, "book-1", note.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("another user listed %d of alice's notes", len(notes))
+	}
+
+	content := "edited"
+	if _, err := NewUpdateNoteUseCase(repo).Execute(bob, created.ID, note.Patch{Content: &content}); !errors.Is(err, note.ErrNoteNotFound) {
+		t.Errorf("update by another user: got %v, want ErrNoteNotFound", err)
+	}
+	if err := NewDeleteNoteUseCase(repo).Execute(bob, created.ID); !errors.Is(err, note.ErrNoteNotFound) {
+		t.Errorf("delete by another user: got %v, want ErrNoteNotFound", err)
+	}
+	if _, err := repo.GetByID(created.ID, 1); err != nil {
+		t.Errorf("alice's note is gone after bob's attempts: %v", err)
+	}
+
+	if err := NewDeleteNoteUseCase(repo).Execute(alice, created.ID); err != nil {
+		t.Errorf("delete by the owner: %v", err)
+	}
+}
+
+func TestNotesRequireAPrincipal(t *testing.T) {
+	repo := newFakeNoteRepository()
+	ctx := context.Background()
+
+	if _, err := NewCreateNoteUseCase(repo, nil).Execute(ctx, &note.Note{BookID: "book-1", Content: "x"}); !errors.Is(err, identity.ErrUnauthenticated) {
+		t.Errorf("create: got %v, want ErrUnauthenticated", err)
+	}
+	if _, err := NewGetNotesUseCase(repo).Execute(ctx, "book-1", note.Filter{}); !errors.Is(err, identity.ErrUnauthenticated) {
+		t.Errorf("list: got %v, want ErrUnauthenticated", err)
+	}
+	if err := NewDeleteNoteUseCase(repo).Execute(ctx, "id"); !errors.Is(err, identity.ErrUnauthenticated) {
+		t.Errorf("delete: got %v, want ErrUnauthenticated", err)
+	}
+}
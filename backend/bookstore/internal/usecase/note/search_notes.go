@@ -0,0 +1,28 @@
+package note
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/note"
+)
+
+type SearchNotesUseCase struct {
+	repo note.NoteRepository
+}
+
+func NewSearchNotesUseCase(repo note.NoteRepository) *SearchNotesUseCase {
+	return &SearchNotesUseCase{repo: repo}
+}
+
+// Execute searches the authenticated user's notes across all books.
+func (uc *SearchNotesUseCase) Execute(ctx context.Context, query note.SearchQuery) ([]*note.SearchResult, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+	return uc.repo.Search(p.UserID, query)
+}
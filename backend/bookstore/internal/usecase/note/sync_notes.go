@@ -0,0 +1,181 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/note"
+	"github.com/google/uuid"
+)
+
+type SyncNotesUseCase struct {
+	repo note.NoteRepository
+}
+
+func NewSyncNotesUseCase(repo note.NoteRepository) *SyncNotesUseCase {
+	return &SyncNotesUseCase{repo: repo}
+}
+
+// Execute applies the client's changes and returns every change made since
+// syncToken.
+//
+// Each change is the client's copy of a note: Version is the server version
+// the client last saw (0 for a note created offline), DeletedAt marks a
+// deletion and UpdatedAt is when the client made the change. A change made
+// against the current version is applied as is. Otherwise the most recently
+// updated side wins and the other side is kept as a new note tagged
+// note.ConflictTag, so no edit is lost. A change that matches the stored
+// note, such as a retried push of a note created offline, is already
+// applied and is not a conflict.
+func (uc *SyncNotesUseCase) Execute(ctx context.Context, syncToken string, changes []*note.Note) (*note.SyncResult, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	since, err := note.ParseSyncToken(syncToken)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) > note.MaxSyncChanges {
+		return nil, fmt.Errorf("%w: at most %d changes per sync", note.ErrInvalidNote, note.MaxSyncChanges)
+	}
+
+	result := &note.SyncResult{Conflicts: []note.SyncConflict{}, Rejected: []note.SyncRejection{}}
+	now := time.Now()
+	for _, change := range changes {
+		if change == nil {
+			continue
+		}
+		conflict, err := uc.apply(p.UserID, change, now)
+		if err != nil {
+			if !errors.Is(err, note.ErrInvalidNote) && !errors.Is(err, note.ErrNoteExists) &&
+				!errors.Is(err, note.ErrNoteVersionConflict) {
+				return nil, err
+			}
+			result.Rejected = append(result.Rejected, note.SyncRejection{NoteID: change.ID, Error: err.Error()})
+			continue
+		}
+		if conflict != nil {
+			result.Conflicts = append(result.Conflicts, *conflict)
+		}
+	}
+
+	set, err := uc.repo.GetChanges(p.UserID, since, note.SyncPageSize)
+	if err != nil {
+		return nil, err
+	}
+	result.Changes = set.Notes
+	result.HasMore = set.HasMore
+	result.SyncToken = note.EncodeSyncToken(set.Cursor)
+	return result, nil
+}
+
+// apply merges one client change into the stored note.
+func (uc *SyncNotesUseCase) apply(userID int, change *note.Note, now time.Time) (*note.SyncConflict, error) {
+	if change.ID == "" {
+		return nil, fmt.Errorf("%w: id is required", note.ErrInvalidNote)
+	}
+	if err := validateClientID(change.ID); err != nil {
+		return nil, err
+	}
+	change.UserID = userID
+	// A client clock running ahead must not win every future conflict.
+	if change.UpdatedAt.IsZero() || change.UpdatedAt.After(now) {
+		change.UpdatedAt = now
+	}
+	deleted := change.DeletedAt != nil
+	if deleted {
+		change.DeletedAt = &change.UpdatedAt
+	} else if err := change.Normalize(); err != nil {
+		return nil, err
+	}
+
+	stored, err := uc.repo.GetByIDIncludingDeleted(change.ID, userID)
+	if errors.Is(err, note.ErrNoteNotFound) {
+		if deleted {
+			// Created and deleted offline: nothing to sync.
+			return nil, nil
+		}
+		change.Version = 1
+		change.CreatedAt = time.Time{}
+		_, err = uc.repo.Create(change)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	storedDeleted := stored.DeletedAt != nil
+
+	if change.Version == stored.Version {
+		return nil, uc.overwrite(stored, change)
+	}
+	if deleted && storedDeleted {
+		return nil, nil
+	}
+	if !deleted && !storedDeleted && sameContent(change, stored) {
+		return nil, nil
+	}
+
+	conflict := &note.SyncConflict{NoteID: stored.ID, Winner: note.WinnerServer}
+	loser := change
+	if change.UpdatedAt.After(stored.UpdatedAt) {
+		conflict.Winner = note.WinnerClient
+		loser = stored
+	}
+	if loser.DeletedAt == nil {
+		copied, err := uc.createConflictCopy(loser, now)
+		if err != nil {
+			return nil, err
+		}
+		conflict.CopyID = copied.ID
+	}
+	if conflict.Winner == note.WinnerClient {
+		if err := uc.overwrite(stored, change); err != nil {
+			return nil, err
+		}
+	}
+	return conflict, nil
+}
+
+// overwrite replaces the stored note with the client's copy.
+func (uc *SyncNotesUseCase) overwrite(stored, change *note.Note) error {
+	updated := *change
+	updated.Version = stored.Version
+	updated.BookID = stored.BookID
+	updated.IsAIGenerated = stored.IsAIGenerated
+	if updated.DeletedAt != nil {
+		// Tombstones keep their last content.
+		updated.Content, updated.Quote, updated.Comment = stored.Content, stored.Quote, stored.Comment
+		updated.Anchor, updated.Color, updated.Tags = stored.Anchor, stored.Color, stored.Tags
+	}
+	_, err := uc.repo.Update(&updated)
+	return err
+}
+
+// sameContent reports whether two copies of a note have the same
+// user-editable fields.
+func sameContent(a, b *note.Note) bool {
+	samePage := (a.PageNumber == nil) == (b.PageNumber == nil) &&
+		(a.PageNumber == nil || *a.PageNumber == *b.PageNumber)
+	return a.BookID == b.BookID && a.Content == b.Content && a.Quote == b.Quote &&
+		a.Comment == b.Comment && a.Color == b.Color && a.CFI == b.CFI && samePage &&
+		slices.Equal(a.Tags, b.Tags)
+}
+
+func (uc *SyncNotesUseCase) createConflictCopy(n *note.Note, now time.Time) (*note.Note, error) {
+	copied := *n
+	copied.ID = uuid.New().String()
+	copied.Version = 1
+	copied.DeletedAt = nil
+	copied.CreatedAt = now
+	copied.UpdatedAt = now
+	copied.Tags = append(append([]string{}, n.Tags...), note.ConflictTag)
+	if err := copied.Normalize(); err != nil {
+		return nil, err
+	}
+	return uc.repo.Create(&copied)
+}
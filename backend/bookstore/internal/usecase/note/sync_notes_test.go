@@ -0,0 +1,74 @@
+package note
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/note"
+	"github.com/google/uuid"
+)
+
+func offlineNote(id, content string) *note.Note {
+	return &note.Note{
+		ID:        id,
+		BookID:    "book-1",
+		Content:   content,
+		Tags:      []string{"later"},
+		UpdatedAt: time.Now().Add(-time.Minute),
+	}
+}
+
+func TestSyncRetriedCreateIsApplied(t *testing.T) {
+	repo := newFakeNoteRepository()
+	uc := NewSyncNotesUseCase(repo)
+	ctx := asUser(1)
+	id := uuid.New().String()
+
+	first, err := uc.Execute(ctx, "", []*note.Note{offlineNote(id, "written offline")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Conflicts) != 0 || len(first.Rejected) != 0 {
+		t.Fatalf("first sync: conflicts %v, rejected %v", first.Conflicts, first.Rejected)
+	}
+
+	// The response was lost, so the client pushes the same create again.
+	for range 3 {
+		retry, err := uc.Execute(ctx, "", []*note.Note{offlineNote(id, "written offline")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(retry.Conflicts) != 0 || len(retry.Rejected) != 0 {
+			t.Fatalf("retry: conflicts %v, rejected %v", retry.Conflicts, retry.Rejected)
+		}
+	}
+
+	notes, _ := repo.GetByUserID(1)
+	if len(notes) != 1 {
+		t.Fatalf("got %d notes after retries, want 1", len(notes))
+	}
+	if notes[0].Version != 1 {
+		t.Errorf("retries bumped the version to %d", notes[0].Version)
+	}
+}
+
+func TestSyncDivergedCreateKeepsBothSides(t *testing.T) {
+	repo := newFakeNoteRepository()
+	uc := NewSyncNotesUseCase(repo)
+	ctx := asUser(1)
+	id := uuid.New().String()
+
+	if _, err := uc.Execute(ctx, "", []*note.Note{offlineNote(id, "first draft")}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := uc.Execute(ctx, "", []*note.Note{offlineNote(id, "second draft")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].CopyID == "" {
+		t.Fatalf("got conflicts %v, want one with a copy", result.Conflicts)
+	}
+	if notes, _ := repo.GetByUserID(1); len(notes) != 2 {
+		t.Errorf("got %d notes, want the note and its conflict copy", len(notes))
+	}
+}
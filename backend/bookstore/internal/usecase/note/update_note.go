@@ -0,0 +1,36 @@
+package note
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/note"
+)
+
+type UpdateNoteUseCase struct {
+	repo note.NoteRepository
+}
+
+func NewUpdateNoteUseCase(repo note.NoteRepository) *UpdateNoteUseCase {
+	return &UpdateNoteUseCase{repo: repo}
+}
+
+// Execute applies a partial update to one of the authenticated user's notes.
+func (uc *UpdateNoteUseCase) Execute(ctx context.Context, noteID string, patch note.Patch) (*note.Note, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := uc.repo.GetByID(noteID, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	n.Apply(patch)
+	if err := n.Normalize(); err != nil {
+		return nil, err
+	}
+	n.UpdatedAt = time.Now()
+	return uc.repo.Update(n)
+}
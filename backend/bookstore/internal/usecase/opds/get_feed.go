@@ -0,0 +1,138 @@
+package opds
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/opds"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
+)
+
+type GetFeedUseCase struct {
+	books  book.BookRepository
+	access *entitlementuc.CheckAccessUseCase
+}
+
+func NewGetFeedUseCase(books book.BookRepository, access *entitlementuc.CheckAccessUseCase) *GetFeedUseCase {
+	return &GetFeedUseCase{books: books, access: access}
+}
+
+// Execute builds the catalog feed at req.Path: the root and the category
+// list are navigation feeds, the rest list pages of books.
+func (uc *GetFeedUseCase) Execute(ctx context.Context, req opds.Request) (*opds.Feed, error) {
+	if _, err := identity.Require(ctx); err != nil {
+		return nil, err
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = opds.DefaultPageSize
+	}
+	if req.Page < 1 || req.PageSize < 1 || req.PageSize > opds.MaxPageSize {
+		return nil, fmt.Errorf("%w: page must be at least 1 and page size between 1 and %d", opds.ErrInvalidRequest, opds.MaxPageSize)
+	}
+	req.Path = strings.Trim(req.Path, "/")
+
+	switch req.Path {
+	case opds.PathRoot:
+		return uc.root(), nil
+	case opds.PathCategories:
+		return uc.categories()
+	case opds.PathNew:
+		return uc.acquisition(ctx, req, "New arrivals", book.Filter{Sort: book.SortNewest})
+	case opds.PathFeatured:
+		return uc.acquisition(ctx, req, "Featured", book.Filter{Featured: true, Sort: book.SortTitle})
+	case opds.PathAll:
+		return uc.acquisition(ctx, req, "All books", book.Filter{Sort: book.SortTitle})
+	case opds.PathSearch:
+		req.Query = strings.TrimSpace(req.Query)
+		if req.Query == "" {
+			return nil, fmt.Errorf("%w: search needs a query", opds.ErrInvalidRequest)
+		}
+		return uc.acquisition(ctx, req, fmt.Sprintf("Search results for %q", req.Query), book.Filter{Query: req.Query, Sort: book.SortRating})
+	}
+	if category, ok := opds.CategoryFromPath(req.Path); ok {
+		feed, err := uc.acquisition(ctx, req, category, book.Filter{Category: category, Sort: book.SortTitle})
+		if err != nil {
+			return nil, err
+		}
+		feed.Up = opds.PathCategories
+		return feed, nil
+	}
+	return nil, opds.ErrFeedNotFound
+}
+
+func (uc *GetFeedUseCase) root() *opds.Feed {
+	return &opds.Feed{
+		Path:    opds.PathRoot,
+		Title:   "btluBook catalog",
+		Kind:    opds.KindNavigation,
+		Updated: time.Now().UTC(),
+		Navigation: []opds.Navigation{
+			{Path: opds.PathNew, Title: "New arrivals", Summary: "The most recently added books", Kind: opds.KindAcquisition},
+			{Path: opds.PathFeatured, Title: "Featured", Summary: "Books picked by the editors", Kind: opds.KindAcquisition},
+			{Path: opds.PathCategories, Title: "Categories", Summary: "Browse books by category", Kind: opds.KindNavigation},
+			{Path: opds.PathAll, Title: "All books", Summary: "Every book in the catalog by title", Kind: opds.KindAcquisition},
+		},
+	}
+}
+
+func (uc *GetFeedUseCase) categories() (*opds.Feed, error) {
+	categories, err := uc.books.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+	feed := &opds.Feed{
+		Path:    opds.PathCategories,
+		Title:   "Categories",
+		Kind:    opds.KindNavigation,
+		Updated: time.Now().UTC(),
+		Up:      opds.PathRoot,
+	}
+	for _, c := range categories {
+		feed.Navigation = append(feed.Navigation, opds.Navigation{
+			Path:    opds.CategoryPath(c.Name),
+			Title:   c.Name,
+			Summary: fmt.Sprintf("%d books", c.Count),
+			Kind:    opds.KindAcquisition,
+			Count:   c.Count,
+		})
+	}
+	return feed, nil
+}
+
+// acquisition lists one page of the books matching filter, marked as owned
+// or not by the caller. One extra book is fetched to tell whether there is
+// a next page.
+func (uc *GetFeedUseCase) acquisition(ctx context.Context, req opds.Request, title string, filter book.Filter) (*opds.Feed, error) {
+	filter.Limit = req.PageSize + 1
+	filter.Offset = (req.Page - 1) * req.PageSize
+	books, err := uc.books.FindBooks(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &opds.Feed{
+		Path:     req.Path,
+		Title:    title,
+		Kind:     opds.KindAcquisition,
+		Updated:  time.Now().UTC(),
+		Up:       opds.PathRoot,
+		Query:    req.Query,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+	if len(books) > req.PageSize {
+		books, feed.HasNext = books[:req.PageSize], true
+	}
+	if err := uc.access.MarkOwned(ctx, books); err != nil {
+		return nil, err
+	}
+	feed.Books = books
+	return feed, nil
+}
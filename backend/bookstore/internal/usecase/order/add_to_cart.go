@@ -0,0 +1,52 @@
+package order
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+type AddToCartUseCase struct {
+	repo  order.Repository
+	books book.BookRepository
+}
+
+func NewAddToCartUseCase(repo order.Repository, books book.BookRepository) *AddToCartUseCase {
+	return &AddToCartUseCase{repo: repo, books: books}
+}
+
+// Execute puts a priced book the user does not own yet in their cart.
+func (uc *AddToCartUseCase) Execute(ctx context.Context, bookID string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	b, err := uc.books.GetBookByID(bookID)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return book.ErrBookNotFound
+	}
+	if !order.ForSale(b) {
+		return order.ErrNotForSale
+	}
+	owned, err := uc.repo.HasEntitlement(p.UserID, b.ID)
+	if err != nil {
+		return err
+	}
+	if owned {
+		return order.ErrAlreadyOwned
+	}
+
+	items, err := uc.repo.ListCart(p.UserID)
+	if err != nil {
+		return err
+	}
+	if len(items) >= order.MaxCartItems {
+		return order.ErrCartFull
+	}
+	return uc.repo.AddToCart(p.UserID, b.ID)
+}
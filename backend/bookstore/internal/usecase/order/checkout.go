@@ -0,0 +1,66 @@
+package order
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+type CheckoutUseCase struct {
+	repo    order.Repository
+	books   book.BookRepository
+	gateway order.PaymentGateway
+}
+
+// NewCheckoutUseCase creates the usecase. gateway may be nil, in which case
+// orders are created but stay pending.
+func NewCheckoutUseCase(repo order.Repository, books book.BookRepository, gateway order.PaymentGateway) *CheckoutUseCase {
+	return &CheckoutUseCase{repo: repo, books: books, gateway: gateway}
+}
+
+// Execute turns the user's cart into an order, fixing each book's current
+// price, and pays for it. Books the user already owns or that are no
+// longer for sale are left out. If payment fails the pending order is
+// returned with the error so it can be paid later.
+func (uc *CheckoutUseCase) Execute(ctx context.Context) (*order.Order, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := uc.repo.ListCart(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &order.Order{UserID: p.UserID, Currency: order.DefaultCurrency}
+	for _, it := range items {
+		b, err := uc.books.GetBookByID(it.BookID)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil || !order.ForSale(b) {
+			continue
+		}
+		owned, err := uc.repo.HasEntitlement(p.UserID, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		if owned {
+			continue
+		}
+		li := &order.LineItem{BookID: b.ID, Title: b.Title, Author: b.Author, PriceCents: order.PriceCents(b)}
+		o.Items = append(o.Items, li)
+		o.TotalCents += li.PriceCents
+	}
+	if len(o.Items) == 0 {
+		return nil, order.ErrCartEmpty
+	}
+
+	o, err = uc.repo.CreateOrder(o)
+	if err != nil {
+		return nil, err
+	}
+	return pay(ctx, uc.repo, uc.gateway, o)
+}
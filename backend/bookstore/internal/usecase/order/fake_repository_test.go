@@ -0,0 +1,195 @@
+package order
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+// fakeOrderRepository keeps carts, orders and entitlements in memory. Like
+// the Postgres store it grants an order's books when it is paid and revokes
+// them when it is refunded, and doubles as the entitlement repository.
+type fakeOrderRepository struct {
+	books        map[string]*book.Book
+	carts        map[int][]string
+	orders       map[string]*order.Order
+	entitlements map[int]map[string]string // user ID -> book ID -> order ID
+	next         int
+}
+
+func newFakeOrderRepository(books ...*book.Book) *fakeOrderRepository {
+	f := &fakeOrderRepository{
+		books:        map[string]*book.Book{},
+		carts:        map[int][]string{},
+		orders:       map[string]*order.Order{},
+		entitlements: map[int]map[string]string{},
+	}
+	for _, b := range books {
+		f.books[b.ID] = b
+	}
+	return f
+}
+
+func (f *fakeOrderRepository) AddToCart(userID int, bookID string) error {
+	for _, id := range f.carts[userID] {
+		if id == bookID {
+			return nil
+		}
+	}
+	f.carts[userID] = append(f.carts[userID], bookID)
+	return nil
+}
+
+func (f *fakeOrderRepository) RemoveFromCart(userID int, bookID string) error {
+	for i, id := range f.carts[userID] {
+		if id == bookID {
+			f.carts[userID] = append(f.carts[userID][:i], f.carts[userID][i+1:]...)
+			return nil
+		}
+	}
+	return order.ErrItemNotFound
+}
+
+func (f *fakeOrderRepository) ListCart(userID int) ([]*order.CartItem, error) {
+	var items []*order.CartItem
+	for _, id := range f.carts[userID] {
+		b := f.books[id]
+		items = append(items, &order.CartItem{BookID: b.ID, Title: b.Title, Author: b.Author, PriceCents: order.PriceCents(b)})
+	}
+	return items, nil
+}
+
+func (f *fakeOrderRepository) CreateOrder(o *order.Order) (*order.Order, error) {
+	f.next++
+	saved := *o
+	saved.ID = fmt.Sprintf("o%d", f.next)
+	saved.Status = order.StatusPending
+	saved.CreatedAt = time.Now()
+	f.orders[saved.ID] = &saved
+	for _, it := range o.Items {
+		f.RemoveFromCart(o.UserID, it.BookID)
+	}
+	copied := saved
+	return &copied, nil
+}
+
+func (f *fakeOrderRepository) GetOrder(id string) (*order.Order, error) {
+	o, ok := f.orders[id]
+	if !ok {
+		return nil, order.ErrOrderNotFound
+	}
+	copied := *o
+	return &copied, nil
+}
+
+func (f *fakeOrderRepository) ListOrders(userID int) ([]*order.Order, error) {
+	var orders []*order.Order
+	for _, o := range f.orders {
+		if o.UserID == userID {
+			copied := *o
+			orders = append(orders, &copied)
+		}
+	}
+	return orders, nil
+}
+
+func (f *fakeOrderRepository) MarkPaid(orderID, paymentRef string, at time.Time) (*order.Order, error) {
+	o, ok := f.orders[orderID]
+	if !ok {
+		return nil, order.ErrOrderNotFound
+	}
+	if err := order.CheckTransition(o.Status, order.StatusPaid); err != nil {
+		return nil, err
+	}
+	o.Status, o.PaymentRef, o.PaidAt = order.StatusPaid, paymentRef, &at
+	if f.entitlements[o.UserID] == nil {
+		f.entitlements[o.UserID] = map[string]string{}
+	}
+	for _, it := range o.Items {
+		f.entitlements[o.UserID][it.BookID] = o.ID
+	}
+	copied := *o
+	return &copied, nil
+}
+
+func (f *fakeOrderRepository) MarkRefunded(orderID, refundRef string, at time.Time) (*order.Order, error) {
+	o, ok := f.orders[orderID]
+	if !ok {
+		return nil, order.ErrOrderNotFound
+	}
+	if err := order.CheckTransition(o.Status, order.StatusRefunded); err != nil {
+		return nil, err
+	}
+	o.Status, o.RefundRef, o.RefundedAt = order.StatusRefunded, refundRef, &at
+	for _, it := range o.Items {
+		if f.entitlements[o.UserID][it.BookID] == o.ID {
+			delete(f.entitlements[o.UserID], it.BookID)
+		}
+	}
+	copied := *o
+	return &copied, nil
+}
+
+func (f *fakeOrderRepository) HasEntitlement(userID int, bookID string) (bool, error) {
+	_, ok := f.entitlements[userID][bookID]
+	return ok, nil
+}
+
+// Purchased, Licensed and the license methods make the fake an
+// entitlement.Repository without licenses.
+func (f *fakeOrderRepository) Purchased(userID int, bookIDs []string) (map[string]bool, error) {
+	purchased := map[string]bool{}
+	for _, id := range bookIDs {
+		if _, ok := f.entitlements[userID][id]; ok {
+			purchased[id] = true
+		}
+	}
+	return purchased, nil
+}
+
+func (f *fakeOrderRepository) Licensed(userID int, bookIDs []string) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
+
+func (f *fakeOrderRepository) CreateLicense(l *entitlement.License) (*entitlement.License, error) {
+	return l, nil
+}
+
+func (f *fakeOrderRepository) ListLicenses() ([]*entitlement.License, error) {
+	return nil, nil
+}
+
+func (f *fakeOrderRepository) DeleteLicense(id string) error {
+	return entitlement.ErrLicenseNotFound
+}
+
+func (f *fakeOrderRepository) AddLicenseMember(licenseID string, userID int) (*entitlement.LicenseMember, error) {
+	return nil, entitlement.ErrLicenseNotFound
+}
+
+func (f *fakeOrderRepository) RemoveLicenseMember(licenseID string, userID int) error {
+	return entitlement.ErrMemberNotFound
+}
+
+func (f *fakeOrderRepository) ListLicenseMembers(licenseID string) ([]*entitlement.LicenseMember, error) {
+	return nil, entitlement.ErrLicenseNotFound
+}
+
+// fakeBookRepository serves the books of a fakeOrderRepository. Only the
+// lookups the order usecases make are implemented.
+type fakeBookRepository struct {
+	book.BookRepository
+	orders *fakeOrderRepository
+}
+
+func (f fakeBookRepository) GetBookByID(id string) (*book.Book, error) {
+	b, ok := f.orders.books[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *b
+	return &copied, nil
+}
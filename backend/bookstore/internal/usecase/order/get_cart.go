@@ -0,0 +1,34 @@
+package order
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+type GetCartUseCase struct {
+	repo order.Repository
+}
+
+func NewGetCartUseCase(repo order.Repository) *GetCartUseCase {
+	return &GetCartUseCase{repo: repo}
+}
+
+// Execute returns the authenticated user's cart at current prices.
+func (uc *GetCartUseCase) Execute(ctx context.Context) (*order.Cart, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := uc.repo.ListCart(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	cart := &order.Cart{Items: []*order.CartItem{}, Currency: order.DefaultCurrency}
+	for _, it := range items {
+		cart.Items = append(cart.Items, it)
+		cart.TotalCents += it.PriceCents
+	}
+	return cart, nil
+}
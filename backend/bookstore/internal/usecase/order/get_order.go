@@ -0,0 +1,33 @@
+package order
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+type GetOrderUseCase struct {
+	repo order.Repository
+}
+
+func NewGetOrderUseCase(repo order.Repository) *GetOrderUseCase {
+	return &GetOrderUseCase{repo: repo}
+}
+
+// Execute returns one of the user's orders. Admins may read any order.
+func (uc *GetOrderUseCase) Execute(ctx context.Context, orderID string) (*order.Order, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o, err := uc.repo.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	// Other users' orders are reported missing rather than forbidden
+	if !p.CanActAs(o.UserID) {
+		return nil, order.ErrOrderNotFound
+	}
+	return o, nil
+}
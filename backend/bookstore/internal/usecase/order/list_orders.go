@@ -0,0 +1,32 @@
+package order
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+type ListOrdersUseCase struct {
+	repo order.Repository
+}
+
+func NewListOrdersUseCase(repo order.Repository) *ListOrdersUseCase {
+	return &ListOrdersUseCase{repo: repo}
+}
+
+// Execute lists the authenticated user's orders, newest first.
+func (uc *ListOrdersUseCase) Execute(ctx context.Context) ([]*order.Order, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := uc.repo.ListOrders(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if orders == nil {
+		orders = []*order.Order{}
+	}
+	return orders, nil
+}
@@ -0,0 +1,200 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/payment"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
+)
+
+const (
+	uploader = 1
+	buyer    = 2
+	admin    = 3
+)
+
+func asUser(userID int, roles ...string) context.Context {
+	if len(roles) == 0 {
+		roles = []string{identity.RoleUser}
+	}
+	return identity.WithPrincipal(context.Background(), identity.Principal{UserID: userID, Roles: roles})
+}
+
+func price(p float32) *float32 { return &p }
+
+type orderFixture struct {
+	repo     *fakeOrderRepository
+	gateway  *payment.FakeGateway
+	access   *entitlementuc.CheckAccessUseCase
+	add      *AddToCartUseCase
+	checkout *CheckoutUseCase
+	pay      *PayOrderUseCase
+	refund   *RefundOrderUseCase
+}
+
+// newOrderFixture sells "dune" for $5.00; "free" costs nothing.
+func newOrderFixture() *orderFixture {
+	repo := newFakeOrderRepository(
+		&book.Book{ID: "dune", Title: "Dune", Price: price(5), UploadedBy: uploader},
+		&book.Book{ID: "free", Title: "Emma", UploadedBy: uploader},
+	)
+	books := fakeBookRepository{orders: repo}
+	gateway := payment.NewFakeGateway()
+	return &orderFixture{
+		repo:     repo,
+		gateway:  gateway,
+		access:   entitlementuc.NewCheckAccessUseCase(repo, books),
+		add:      NewAddToCartUseCase(repo, books),
+		checkout: NewCheckoutUseCase(repo, books, gateway),
+		pay:      NewPayOrderUseCase(repo, gateway),
+		refund:   NewRefundOrderUseCase(repo, gateway),
+	}
+}
+
+// mayRead reports whether the buyer may download dune.
+func (f *orderFixture) mayRead(t *testing.T) bool {
+	t.Helper()
+	err := f.access.AuthorizeID(asUser(buyer), "dune")
+	if err != nil && !errors.Is(err, entitlement.ErrNotEntitled) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func (f *orderFixture) cartDune(t *testing.T) {
+	t.Helper()
+	if err := f.add.Execute(asUser(buyer), "dune"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOrderIsPaidThenRefunded(t *testing.T) {
+	f := newOrderFixture()
+	if f.mayRead(t) {
+		t.Fatal("buyer may read dune before paying")
+	}
+
+	f.cartDune(t)
+	o, err := f.checkout.Execute(asUser(buyer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Status != order.StatusPaid || o.PaymentRef == "" || o.TotalCents != 500 {
+		t.Fatalf("after checkout: status %s, payment %q, total %d; want paid, a reference and 500", o.Status, o.PaymentRef, o.TotalCents)
+	}
+	if charges := f.gateway.Charges(); len(charges) != 1 || charges[0].AmountCents != 500 || charges[0].OrderID != o.ID {
+		t.Errorf("charges = %+v, want one of 500 for %s", charges, o.ID)
+	}
+	if !f.mayRead(t) {
+		t.Fatal("buyer may not read dune after paying")
+	}
+	if err := f.add.Execute(asUser(buyer), "dune"); !errors.Is(err, order.ErrAlreadyOwned) {
+		t.Errorf("adding an owned book: got %v, want ErrAlreadyOwned", err)
+	}
+
+	if _, err := f.refund.Execute(asUser(buyer), o.ID); !errors.Is(err, identity.ErrForbidden) {
+		t.Errorf("buyer refunding: got %v, want ErrForbidden", err)
+	}
+	o, err = f.refund.Execute(asUser(admin, identity.RoleAdmin), o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Status != order.StatusRefunded || o.RefundRef == "" {
+		t.Errorf("after refund: status %s, refund %q; want refunded and a reference", o.Status, o.RefundRef)
+	}
+	if f.mayRead(t) {
+		t.Error("buyer may still read dune after the refund")
+	}
+
+	// a refunded order is final
+	if _, err := f.refund.Execute(asUser(admin, identity.RoleAdmin), o.ID); !errors.Is(err, order.ErrInvalidTransition) {
+		t.Errorf("refunding twice: got %v, want ErrInvalidTransition", err)
+	}
+	if _, err := f.pay.Execute(asUser(buyer), o.ID); !errors.Is(err, order.ErrInvalidTransition) {
+		t.Errorf("paying a refunded order: got %v, want ErrInvalidTransition", err)
+	}
+	if f.mayRead(t) {
+		t.Error("buyer may read dune again after paying a refunded order")
+	}
+}
+
+func TestDeclinedPaymentCanBeRetried(t *testing.T) {
+	f := newOrderFixture()
+	f.cartDune(t)
+	f.gateway.DeclineNext(1)
+
+	o, err := f.checkout.Execute(asUser(buyer))
+	if !errors.Is(err, order.ErrPaymentDeclined) {
+		t.Fatalf("got %v, want ErrPaymentDeclined", err)
+	}
+	if o == nil || o.Status != order.StatusPending {
+		t.Fatalf("declined order = %+v, want it returned pending", o)
+	}
+	if f.mayRead(t) {
+		t.Fatal("buyer may read dune after a declined payment")
+	}
+	if len(f.gateway.Charges()) != 0 {
+		t.Errorf("charges = %+v, want none", f.gateway.Charges())
+	}
+
+	if _, err := f.pay.Execute(asUser(uploader), o.ID); !errors.Is(err, order.ErrOrderNotFound) {
+		t.Errorf("another user paying: got %v, want ErrOrderNotFound", err)
+	}
+	o, err = f.pay.Execute(asUser(buyer), o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Status != order.StatusPaid {
+		t.Errorf("after retry: status %s, want paid", o.Status)
+	}
+	if !f.mayRead(t) {
+		t.Error("buyer may not read dune after the retry")
+	}
+
+	// paying again must not charge twice
+	if _, err := f.pay.Execute(asUser(buyer), o.ID); !errors.Is(err, order.ErrInvalidTransition) {
+		t.Errorf("paying twice: got %v, want ErrInvalidTransition", err)
+	}
+	if n := len(f.gateway.Charges()); n != 1 {
+		t.Errorf("got %d charges, want 1", n)
+	}
+}
+
+func TestFailedRefundKeepsTheBook(t *testing.T) {
+	f := newOrderFixture()
+	f.cartDune(t)
+	o, err := f.checkout.Execute(asUser(buyer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.gateway.FailNext(1)
+	if _, err := f.refund.Execute(asUser(admin, identity.RoleAdmin), o.ID); !errors.Is(err, order.ErrPaymentUnavailable) {
+		t.Fatalf("got %v, want ErrPaymentUnavailable", err)
+	}
+	if saved, _ := f.repo.GetOrder(o.ID); saved.Status != order.StatusPaid {
+		t.Errorf("status = %s, want paid", saved.Status)
+	}
+	if !f.mayRead(t) {
+		t.Error("buyer lost dune to a failed refund")
+	}
+}
+
+func TestOnlyPaidBooksAreSold(t *testing.T) {
+	f := newOrderFixture()
+	if err := f.add.Execute(asUser(buyer), "free"); !errors.Is(err, order.ErrNotForSale) {
+		t.Errorf("free book: got %v, want ErrNotForSale", err)
+	}
+	if err := f.add.Execute(asUser(buyer), "missing"); !errors.Is(err, book.ErrBookNotFound) {
+		t.Errorf("missing book: got %v, want ErrBookNotFound", err)
+	}
+	if _, err := f.checkout.Execute(asUser(buyer)); !errors.Is(err, order.ErrCartEmpty) {
+		t.Errorf("empty cart: got %v, want ErrCartEmpty", err)
+	}
+}
@@ -0,0 +1,59 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+type PayOrderUseCase struct {
+	repo    order.Repository
+	gateway order.PaymentGateway
+}
+
+// NewPayOrderUseCase creates the usecase. gateway may be nil, in which case
+// payments fail with ErrPaymentUnavailable.
+func NewPayOrderUseCase(repo order.Repository, gateway order.PaymentGateway) *PayOrderUseCase {
+	return &PayOrderUseCase{repo: repo, gateway: gateway}
+}
+
+// Execute retries the payment of one of the user's pending orders, for
+// instance after the card was declined at checkout.
+func (uc *PayOrderUseCase) Execute(ctx context.Context, orderID string) (*order.Order, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o, err := uc.repo.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.CanActAs(o.UserID) {
+		return nil, order.ErrOrderNotFound
+	}
+	return pay(ctx, uc.repo, uc.gateway, o)
+}
+
+// pay charges a pending order and marks it paid, which grants its books.
+// If the charge fails the order is returned still pending along with the
+// error.
+func pay(ctx context.Context, repo order.Repository, gateway order.PaymentGateway, o *order.Order) (*order.Order, error) {
+	if err := order.CheckTransition(o.Status, order.StatusPaid); err != nil {
+		return o, err
+	}
+	if gateway == nil {
+		return o, order.ErrPaymentUnavailable
+	}
+	ref, err := gateway.Charge(ctx, order.Charge{
+		OrderID:     o.ID,
+		UserID:      o.UserID,
+		AmountCents: o.TotalCents,
+		Currency:    o.Currency,
+	})
+	if err != nil {
+		return o, err
+	}
+	return repo.MarkPaid(o.ID, ref, time.Now())
+}
@@ -0,0 +1,45 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+type RefundOrderUseCase struct {
+	repo    order.Repository
+	gateway order.PaymentGateway
+}
+
+func NewRefundOrderUseCase(repo order.Repository, gateway order.PaymentGateway) *RefundOrderUseCase {
+	return &RefundOrderUseCase{repo: repo, gateway: gateway}
+}
+
+// Execute refunds a paid order in full and revokes the books it granted.
+// Only admins may refund.
+func (uc *RefundOrderUseCase) Execute(ctx context.Context, orderID string) (*order.Order, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsAdmin() {
+		return nil, identity.ErrForbidden
+	}
+	o, err := uc.repo.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := order.CheckTransition(o.Status, order.StatusRefunded); err != nil {
+		return nil, err
+	}
+	if uc.gateway == nil {
+		return nil, order.ErrPaymentUnavailable
+	}
+	ref, err := uc.gateway.Refund(ctx, o.PaymentRef, o.TotalCents, o.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.MarkRefunded(o.ID, ref, time.Now())
+}
@@ -0,0 +1,24 @@
+package order
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/order"
+)
+
+type RemoveFromCartUseCase struct {
+	repo order.Repository
+}
+
+func NewRemoveFromCartUseCase(repo order.Repository) *RemoveFromCartUseCase {
+	return &RemoveFromCartUseCase{repo: repo}
+}
+
+func (uc *RemoveFromCartUseCase) Execute(ctx context.Context, bookID string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return uc.repo.RemoveFromCart(p.UserID, bookID)
+}
@@ -0,0 +1,25 @@
+package rating
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/rating"
+)
+
+type DeleteReviewUseCase struct {
+	repo rating.Repository
+}
+
+func NewDeleteReviewUseCase(repo rating.Repository) *DeleteReviewUseCase {
+	return &DeleteReviewUseCase{repo: repo}
+}
+
+// Execute deletes the authenticated user's review of a book.
+func (uc *DeleteReviewUseCase) Execute(ctx context.Context, bookID string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return uc.repo.Delete(bookID, p.UserID)
+}
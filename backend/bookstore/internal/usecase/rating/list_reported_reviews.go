@@ -0,0 +1,29 @@
+package rating
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/rating"
+)
+
+type ListReportedReviewsUseCase struct {
+	repo rating.Repository
+}
+
+func NewListReportedReviewsUseCase(repo rating.Repository) *ListReportedReviewsUseCase {
+	return &ListReportedReviewsUseCase{repo: repo}
+}
+
+// Execute returns the moderation queue. Only admins may see it.
+func (uc *ListReportedReviewsUseCase) Execute(ctx context.Context, limit, offset int) ([]*rating.Review, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsAdmin() {
+		return nil, identity.ErrForbidden
+	}
+	limit, offset = pageBounds(limit, offset)
+	return uc.repo.ListReported(limit, offset)
+}
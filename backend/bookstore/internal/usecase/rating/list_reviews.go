@@ -0,0 +1,74 @@
+package rating
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/rating"
+)
+
+type ListReviewsUseCase struct {
+	repo  rating.Repository
+	books book.BookRepository
+}
+
+func NewListReviewsUseCase(repo rating.Repository, books book.BookRepository) *ListReviewsUseCase {
+	return &ListReviewsUseCase{repo: repo, books: books}
+}
+
+// Execute returns a page of a book's visible reviews with its rating
+// summary and the caller's own review.
+func (uc *ListReviewsUseCase) Execute(ctx context.Context, bookID string, limit, offset int) (*rating.Page, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset = pageBounds(limit, offset)
+
+	b, err := uc.books.GetBookByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, book.ErrBookNotFound
+	}
+
+	summary, err := uc.repo.Summary(bookID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := uc.repo.ListByBook(bookID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsAdmin() {
+		for _, r := range reviews {
+			if r.UserID != p.UserID {
+				r.Redact()
+			}
+		}
+	}
+
+	mine, err := uc.repo.GetByUser(bookID, p.UserID)
+	if err != nil && !errors.Is(err, rating.ErrReviewNotFound) {
+		return nil, err
+	}
+	return &rating.Page{Summary: summary, Reviews: reviews, Mine: mine}, nil
+}
+
+// pageBounds applies the default page size and clamps the page to sane
+// bounds.
+func pageBounds(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = rating.DefaultPageSize
+	}
+	if limit > rating.MaxPageSize {
+		limit = rating.MaxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,44 @@
+package rating
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/rating"
+)
+
+type ModerateReviewUseCase struct {
+	repo rating.Repository
+}
+
+func NewModerateReviewUseCase(repo rating.Repository) *ModerateReviewUseCase {
+	return &ModerateReviewUseCase{repo: repo}
+}
+
+// Execute hides, restores or deletes a review. Only admins may moderate.
+// It returns nil for a deleted review.
+func (uc *ModerateReviewUseCase) Execute(ctx context.Context, reviewID, action, reason string) (*rating.Review, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsAdmin() {
+		return nil, identity.ErrForbidden
+	}
+	reason, err = rating.NormalizeReason(reason)
+	if err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case rating.ActionHide:
+		return uc.repo.Moderate(reviewID, rating.StatusHidden, reason)
+	case rating.ActionRestore:
+		return uc.repo.Moderate(reviewID, rating.StatusVisible, reason)
+	case rating.ActionDelete:
+		return nil, uc.repo.DeleteByID(reviewID)
+	default:
+		return nil, fmt.Errorf("%w: action must be hide, restore or delete", rating.ErrInvalidReview)
+	}
+}
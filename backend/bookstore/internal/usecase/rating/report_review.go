@@ -0,0 +1,29 @@
+package rating
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/rating"
+)
+
+type ReportReviewUseCase struct {
+	repo rating.Repository
+}
+
+func NewReportReviewUseCase(repo rating.Repository) *ReportReviewUseCase {
+	return &ReportReviewUseCase{repo: repo}
+}
+
+// Execute flags another user's review as abusive so admins can moderate it.
+func (uc *ReportReviewUseCase) Execute(ctx context.Context, reviewID, reason string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	reason, err = rating.NormalizeReason(reason)
+	if err != nil {
+		return err
+	}
+	return uc.repo.Report(reviewID, p.UserID, reason)
+}
@@ -0,0 +1,45 @@
+package rating
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/rating"
+)
+
+type SubmitReviewUseCase struct {
+	repo  rating.Repository
+	books book.BookRepository
+}
+
+func NewSubmitReviewUseCase(repo rating.Repository, books book.BookRepository) *SubmitReviewUseCase {
+	return &SubmitReviewUseCase{repo: repo, books: books}
+}
+
+// Execute creates or edits the authenticated user's review of a book, and
+// reports whether it was created.
+func (uc *SubmitReviewUseCase) Execute(ctx context.Context, bookID string, d rating.Draft) (*rating.Review, bool, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := d.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	b, err := uc.books.GetBookByID(bookID)
+	if err != nil {
+		return nil, false, err
+	}
+	if b == nil {
+		return nil, false, book.ErrBookNotFound
+	}
+
+	return uc.repo.Upsert(&rating.Review{
+		BookID: bookID,
+		UserID: p.UserID,
+		Rating: d.Rating,
+		Text:   d.Text,
+	})
+}
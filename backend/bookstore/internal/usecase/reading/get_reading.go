@@ -0,0 +1,37 @@
+package reading
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/reading"
+)
+
+type GetReadingUseCase struct {
+	repo reading.ProgressRepository
+}
+
+func NewGetReadingUseCase(repo reading.ProgressRepository) *GetReadingUseCase {
+	return &GetReadingUseCase{repo: repo}
+}
+
+func (uc *GetReadingUseCase) Execute(ctx context.Context) (*reading.List, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.repo.ListByUser(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	list := &reading.List{InProgress: []*reading.Entry{}, Finished: []*reading.Entry{}}
+	for _, e := range entries {
+		if e.Status == reading.StatusFinished {
+			list.Finished = append(list.Finished, e)
+		} else {
+			list.InProgress = append(list.InProgress, e)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,78 @@
+package reading
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/reading"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+	activityuc "github.com/bereke1t2/bookstore/internal/usecase/activity"
+	shelfuc "github.com/bereke1t2/bookstore/internal/usecase/shelf"
+	"github.com/google/uuid"
+)
+
+// UpdateProgressUseCase records where the reader is in a book, rewards the
+// reading time and finishing the book, and keeps the book on the matching
+// built-in shelf. A finished book is only rewarded once enough reading has
+// been recorded in it, which may be on a later update.
+type UpdateProgressUseCase struct {
+	repo     reading.ProgressRepository
+	books    book.BookRepository
+	activity activity.Repository
+	shelves  shelf.Repository
+}
+
+func NewUpdateProgressUseCase(repo reading.ProgressRepository, books book.BookRepository, activity activity.Repository, shelves shelf.Repository) *UpdateProgressUseCase {
+	return &UpdateProgressUseCase{repo: repo, books: books, activity: activity, shelves: shelves}
+}
+
+func (uc *UpdateProgressUseCase) Execute(ctx context.Context, bookID string, update reading.Update) (*reading.Progress, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	b, err := uc.books.GetBookByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, book.ErrBookNotFound
+	}
+
+	progress, err := uc.repo.Get(p.UserID, bookID)
+	if errors.Is(err, reading.ErrProgressNotFound) {
+		progress = &reading.Progress{UserID: p.UserID, BookID: bookID}
+	} else if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	spent, status := progress.TimeSpentSeconds, progress.Status
+	progress.Apply(update, now)
+	saved, err := uc.repo.Save(progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if seconds := saved.TimeSpentSeconds - spent; seconds > 0 {
+		activityuc.Record(uc.activity, activity.ReadingSession(p.UserID, "session:"+uuid.New().String(), seconds, now))
+	}
+	if saved.Status == reading.StatusFinished && saved.ReadEnough(now) {
+		activityuc.Record(uc.activity, activity.BookFinished(p.UserID, bookID, now))
+	}
+	if saved.Status != status {
+		kind := shelf.KindReading
+		if saved.Status == reading.StatusFinished {
+			kind = shelf.KindFinished
+		}
+		shelfuc.Place(uc.shelves, p.UserID, kind, b)
+	}
+	return saved, nil
+}
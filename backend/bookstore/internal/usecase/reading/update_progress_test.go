@@ -0,0 +1,150 @@
+package reading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/activity"
+	"github.com/bereke1t2/bookstore/internal/domain/activity/activitytest"
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/reading"
+)
+
+type fakeProgressRepository struct {
+	progress map[string]reading.Progress
+}
+
+func (f *fakeProgressRepository) Get(userID int, bookID string) (*reading.Progress, error) {
+	p, ok := f.progress[bookID]
+	if !ok || p.UserID != userID {
+		return nil, reading.ErrProgressNotFound
+	}
+	return &p, nil
+}
+
+func (f *fakeProgressRepository) Save(p *reading.Progress) (*reading.Progress, error) {
+	f.progress[p.BookID] = *p
+	saved := *p
+	return &saved, nil
+}
+
+func (f *fakeProgressRepository) ListByUser(userID int) ([]*reading.Entry, error) {
+	return nil, nil
+}
+
+// anyBook finds every book ID; only GetBookByID is used.
+type anyBook struct {
+	book.BookRepository
+}
+
+func (anyBook) GetBookByID(id string) (*book.Book, error) {
+	return &book.Book{ID: id, Title: "Book " + id}, nil
+}
+
+func newProgressFixture() (*UpdateProgressUseCase, *fakeProgressRepository, *activitytest.Recorder) {
+	progress := &fakeProgressRepository{progress: map[string]reading.Progress{}}
+	activityRepo := activitytest.NewRecorder()
+	return NewUpdateProgressUseCase(progress, anyBook{}, activityRepo, nil), progress, activityRepo
+}
+
+func asUser(userID int) context.Context {
+	return identity.WithPrincipal(context.Background(), identity.Principal{UserID: userID, Roles: []string{identity.RoleUser}})
+}
+
+func TestFinishingUnreadBookEarnsNothing(t *testing.T) {
+	uc, _, activityRepo := newProgressFixture()
+
+	saved, err := uc.Execute(asUser(1), "b1", reading.Update{Finished: true, SessionSeconds: reading.MaxSessionSeconds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != reading.StatusFinished {
+		t.Fatalf("got status %s, want the book marked finished", saved.Status)
+	}
+	if events := activityRepo.Events(1, activity.TypeBookFinished); len(events) != 0 {
+		t.Errorf("finishing in a single report earned %d book_finished events", len(events))
+	}
+}
+
+func TestFinishingReadBookEarnsOnce(t *testing.T) {
+	uc, progress, activityRepo := newProgressFixture()
+	progress.progress["b1"] = reading.Progress{
+		UserID:           1,
+		BookID:           "b1",
+		Status:           reading.StatusReading,
+		TimeSpentSeconds: reading.MinFinishedSeconds,
+		StartedAt:        time.Now().Add(-2 * time.Hour),
+	}
+
+	for range 2 {
+		if _, err := uc.Execute(asUser(1), "b1", reading.Update{Percent: 100}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if events := activityRepo.Events(1, activity.TypeBookFinished); len(events) != 1 || events[0].Points != activity.BookFinishedPoints {
+		t.Errorf("got %+v, want one book_finished worth %d", events, activity.BookFinishedPoints)
+	}
+}
+
+func TestFinishedBooksAreCappedPerDay(t *testing.T) {
+	uc, progress, activityRepo := newProgressFixture()
+	books := []string{"b1", "b2", "b3", "b4", "b5"}
+	for _, id := range books {
+		progress.progress[id] = reading.Progress{
+			UserID:           1,
+			BookID:           id,
+			Status:           reading.StatusReading,
+			TimeSpentSeconds: reading.MinFinishedSeconds,
+			StartedAt:        time.Now().Add(-2 * time.Hour),
+		}
+		if _, err := uc.Execute(asUser(1), id, reading.Update{Finished: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, limit := activityRepo.Points(1), activity.DailyPointCap[activity.TypeBookFinished]; got != limit {
+		t.Errorf("got %d points for %d books, want the daily cap %d", got, len(books), limit)
+	}
+}
+
+func TestReadingTimeIsCappedAtTheClock(t *testing.T) {
+	uc, progress, activityRepo := newProgressFixture()
+
+	saved, err := uc.Execute(asUser(1), "b1", reading.Update{SessionSeconds: reading.MaxSessionSeconds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.TimeSpentSeconds != 0 {
+		t.Errorf("first report added %ds, want it to only start the clock", saved.TimeSpentSeconds)
+	}
+
+	// ten minutes later the reader claims the full session
+	p := progress.progress["b1"]
+	p.UpdatedAt = p.UpdatedAt.Add(-10 * time.Minute)
+	progress.progress["b1"] = p
+	saved, err = uc.Execute(asUser(1), "b1", reading.Update{SessionSeconds: reading.MaxSessionSeconds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.TimeSpentSeconds; got < 10*60 || got > 10*60+5 {
+		t.Errorf("got %ds after ten minutes, want about 600", got)
+	}
+
+	// and again at once
+	saved, err = uc.Execute(asUser(1), "b1", reading.Update{SessionSeconds: reading.MaxSessionSeconds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.TimeSpentSeconds; got > 10*60+5 {
+		t.Errorf("an immediate second report raised the time to %ds", got)
+	}
+
+	var rewarded int64
+	for _, e := range activityRepo.Events(1, activity.TypeReadingSession) {
+		rewarded += int64(e.Value)
+	}
+	if rewarded != saved.TimeSpentSeconds {
+		t.Errorf("rewarded %ds of reading, want the %ds recorded", rewarded, saved.TimeSpentSeconds)
+	}
+}
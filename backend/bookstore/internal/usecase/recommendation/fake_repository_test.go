@@ -0,0 +1,64 @@
+package recommendation
+
+import (
+	"slices"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/entitlement"
+	"github.com/bereke1t2/bookstore/internal/domain/recommendation"
+)
+
+// fakeRecommendationRepository has no signals or neighbours, so every user
+// gets the popular books.
+type fakeRecommendationRepository struct {
+	popular []recommendation.Popularity
+}
+
+func (f *fakeRecommendationRepository) Signals(userID int) ([]recommendation.Signal, error) {
+	return nil, nil
+}
+
+func (f *fakeRecommendationRepository) Neighbors(seedIDs []string, userID int, limit int) ([]recommendation.Neighbor, error) {
+	return nil, nil
+}
+
+func (f *fakeRecommendationRepository) Popular(limit int) ([]recommendation.Popularity, error) {
+	return f.popular, nil
+}
+
+// fakeBookRepository lists a fixed catalog, handing out copies like the
+// database would.
+type fakeBookRepository struct {
+	book.BookRepository
+	books []*book.Book
+}
+
+func (f *fakeBookRepository) GetAllBooks() ([]*book.Book, error) {
+	books := make([]*book.Book, 0, len(f.books))
+	for _, b := range f.books {
+		copied := *b
+		books = append(books, &copied)
+	}
+	return books, nil
+}
+
+// fakeEntitlementRepository records which users bought which books. It has
+// no licenses.
+type fakeEntitlementRepository struct {
+	entitlement.Repository
+	purchased map[int][]string
+}
+
+func (f *fakeEntitlementRepository) Purchased(userID int, bookIDs []string) (map[string]bool, error) {
+	bought := map[string]bool{}
+	for _, id := range bookIDs {
+		if slices.Contains(f.purchased[userID], id) {
+			bought[id] = true
+		}
+	}
+	return bought, nil
+}
+
+func (f *fakeEntitlementRepository) Licensed(userID int, bookIDs []string) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
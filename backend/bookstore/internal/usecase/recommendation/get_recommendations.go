@@ -0,0 +1,266 @@
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/recommendation"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
+)
+
+// Blend weights for the three scores, each normalised to [0, 1] first.
+const (
+	collaborativeWeight = 0.6
+	contentWeight       = 0.3
+	popularityWeight    = 0.1
+
+	// Content similarity credits a shared author fully and a shared
+	// category at half.
+	sameAuthorScore   = 1.0
+	sameCategoryScore = 0.5
+)
+
+type GetRecommendationsUseCase struct {
+	repo   recommendation.Repository
+	books  book.BookRepository
+	access *entitlementuc.CheckAccessUseCase
+}
+
+func NewGetRecommendationsUseCase(repo recommendation.Repository, books book.BookRepository, access *entitlementuc.CheckAccessUseCase) *GetRecommendationsUseCase {
+	return &GetRecommendationsUseCase{repo: repo, books: books, access: access}
+}
+
+// candidate accumulates the scores of one recommendable book.
+type candidate struct {
+	book          *book.Book
+	collaborative float64
+	content       float64
+	popularity    float64
+	// best* track the strongest contribution of each kind, for the
+	// explanation.
+	bestNeighbor *recommendation.Reason
+	bestCF       float64
+	bestContent  *recommendation.Reason
+	bestCB       float64
+}
+
+// Execute recommends catalog books the authenticated user has not engaged
+// with yet. Books similar readers liked and books sharing an author or
+// category with the user's favourites rank first; users with no history
+// get the most popular books. Books the user may not read are returned
+// without their file URL.
+func (uc *GetRecommendationsUseCase) Execute(ctx context.Context, limit int) ([]*recommendation.Recommendation, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = recommendation.DefaultLimit
+	}
+	if limit > recommendation.MaxLimit {
+		limit = recommendation.MaxLimit
+	}
+
+	all, err := uc.books.GetAllBooks()
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[string]*book.Book, len(all))
+	for _, b := range all {
+		catalog[b.ID] = b
+	}
+
+	signals, err := uc.repo.Signals(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(signals))
+	var seeds []recommendation.Signal
+	for _, s := range signals {
+		seen[s.BookID] = true
+		if s.Weight > 0 && catalog[s.BookID] != nil && len(seeds) < recommendation.MaxSeeds {
+			seeds = append(seeds, s)
+		}
+	}
+
+	candidates := map[string]*candidate{}
+	get := func(id string) *candidate {
+		if seen[id] || catalog[id] == nil {
+			return nil
+		}
+		c := candidates[id]
+		if c == nil {
+			c = &candidate{book: catalog[id]}
+			candidates[id] = c
+		}
+		return c
+	}
+
+	// Item-to-item collaborative filtering
+	if len(seeds) > 0 {
+		weights := make(map[string]recommendation.Signal, len(seeds))
+		ids := make([]string, 0, len(seeds))
+		for _, s := range seeds {
+			weights[s.BookID] = s
+			ids = append(ids, s.BookID)
+		}
+		neighbors, err := uc.repo.Neighbors(ids, p.UserID, limit*len(seeds))
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range neighbors {
+			c := get(n.BookID)
+			if c == nil {
+				continue
+			}
+			seed := weights[n.SeedID]
+			score := seed.Weight * n.Similarity
+			c.collaborative += score
+			if score > c.bestCF {
+				c.bestCF = score
+				c.bestNeighbor = &recommendation.Reason{
+					Kind:      recommendation.ReasonSimilarReaders,
+					Text:      becauseYou(seed.Kind, catalog[seed.BookID].Title),
+					BookID:    seed.BookID,
+					BookTitle: catalog[seed.BookID].Title,
+				}
+			}
+		}
+	}
+
+	// Category and author similarity
+	for _, s := range seeds {
+		seed := catalog[s.BookID]
+		for _, b := range all {
+			score, kind := contentSimilarity(seed, b)
+			if score == 0 {
+				continue
+			}
+			c := get(b.ID)
+			if c == nil {
+				continue
+			}
+			score *= s.Weight
+			c.content += score
+			if score > c.bestCB {
+				c.bestCB = score
+				c.bestContent = &recommendation.Reason{Kind: kind, BookID: seed.ID, BookTitle: seed.Title}
+				if kind == recommendation.ReasonSameAuthor {
+					c.bestContent.Text = fmt.Sprintf("By %s, who also wrote %s", strings.TrimSpace(b.Author), seed.Title)
+				} else {
+					c.bestContent.Text = fmt.Sprintf("More %s, like %s", strings.TrimSpace(b.Category), seed.Title)
+				}
+			}
+		}
+	}
+
+	// Popularity, both as a tie-breaker and as the cold-start fallback
+	popular, err := uc.repo.Popular(limit * 3)
+	if err != nil {
+		return nil, err
+	}
+	for _, pop := range popular {
+		if c := get(pop.BookID); c != nil {
+			c.popularity = pop.Score
+		}
+	}
+
+	ranked := rank(candidates)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	books := make([]*book.Book, len(ranked))
+	for i, r := range ranked {
+		books[i] = r.Book
+	}
+	if err := uc.access.MarkOwned(ctx, books); err != nil {
+		return nil, err
+	}
+	return ranked, nil
+}
+
+// rank blends each candidate's normalised scores and orders them, dropping
+// candidates with no score at all.
+func rank(candidates map[string]*candidate) []*recommendation.Recommendation {
+	var maxCF, maxCB, maxPop float64
+	for _, c := range candidates {
+		maxCF = max(maxCF, c.collaborative)
+		maxCB = max(maxCB, c.content)
+		maxPop = max(maxPop, c.popularity)
+	}
+
+	recs := []*recommendation.Recommendation{}
+	for _, c := range candidates {
+		score := collaborativeWeight*normalize(c.collaborative, maxCF) +
+			contentWeight*normalize(c.content, maxCB) +
+			popularityWeight*normalize(c.popularity, maxPop)
+		if score == 0 {
+			continue
+		}
+
+		reasons := []recommendation.Reason{}
+		if c.bestNeighbor != nil {
+			reasons = append(reasons, *c.bestNeighbor)
+		}
+		if c.bestContent != nil {
+			reasons = append(reasons, *c.bestContent)
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, recommendation.Reason{
+				Kind: recommendation.ReasonPopular,
+				Text: "Popular with readers",
+			})
+		}
+		recs = append(recs, &recommendation.Recommendation{Book: c.book, Score: score, Reasons: reasons})
+	}
+
+	sort.Slice(recs, func(i, j int) bool {
+		if recs[i].Score != recs[j].Score {
+			return recs[i].Score > recs[j].Score
+		}
+		return recs[i].Book.ID < recs[j].Book.ID
+	})
+	return recs
+}
+
+func normalize(v, maxV float64) float64 {
+	if maxV <= 0 {
+		return 0
+	}
+	return v / maxV
+}
+
+// contentSimilarity compares a candidate to one of the user's books by
+// author, then category.
+func contentSimilarity(seed, b *book.Book) (float64, string) {
+	if seed.ID == b.ID {
+		return 0, ""
+	}
+	if a := strings.TrimSpace(seed.Author); a != "" && strings.EqualFold(a, strings.TrimSpace(b.Author)) {
+		return sameAuthorScore, recommendation.ReasonSameAuthor
+	}
+	if cat := strings.TrimSpace(seed.Category); cat != "" && strings.EqualFold(cat, strings.TrimSpace(b.Category)) {
+		return sameCategoryScore, recommendation.ReasonSameCategory
+	}
+	return 0, ""
+}
+
+// becauseYou phrases an explanation after what the user did with title.
+func becauseYou(kind, title string) string {
+	switch kind {
+	case recommendation.SignalRated:
+		return fmt.Sprintf("Because you rated %s highly", title)
+	case recommendation.SignalFinished:
+		return fmt.Sprintf("Because you read %s", title)
+	case recommendation.SignalReading:
+		return fmt.Sprintf("Because you're reading %s", title)
+	case recommendation.SignalNoted:
+		return fmt.Sprintf("Because you took notes on %s", title)
+	default:
+		return fmt.Sprintf("Because you saved %s", title)
+	}
+}
@@ -0,0 +1,64 @@
+package recommendation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/recommendation"
+	entitlementuc "github.com/bereke1t2/bookstore/internal/usecase/entitlement"
+)
+
+func asUser(userID int, roles ...string) context.Context {
+	if len(roles) == 0 {
+		roles = []string{identity.RoleUser}
+	}
+	return identity.WithPrincipal(context.Background(), identity.Principal{UserID: userID, Roles: roles})
+}
+
+func price(p float32) *float32 { return &p }
+
+func TestRecommendationsHideFilesFromNonReaders(t *testing.T) {
+	books := &fakeBookRepository{books: []*book.Book{
+		{ID: "dune", Title: "Dune", Price: price(5), UploadedBy: 1, BookURL: "/uploads/books/dune.pdf"},
+		{ID: "emma", Title: "Emma", UploadedBy: 1, BookURL: "/uploads/books/emma.pdf"},
+	}}
+	repo := &fakeRecommendationRepository{popular: []recommendation.Popularity{
+		{BookID: "dune", Readers: 2, Score: 2},
+		{BookID: "emma", Readers: 1, Score: 1},
+	}}
+	access := entitlementuc.NewCheckAccessUseCase(&fakeEntitlementRepository{purchased: map[int][]string{2: {"dune"}}}, books)
+	uc := NewGetRecommendationsUseCase(repo, books, access)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		readDune bool
+	}{
+		{"uploader", asUser(1), true},
+		{"buyer", asUser(2), true},
+		{"admin", asUser(9, identity.RoleUser, identity.RoleAdmin), true},
+		{"other user", asUser(3), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs, err := uc.Execute(tt.ctx, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(recs) != 2 {
+				t.Fatalf("got %d recommendations, want 2", len(recs))
+			}
+			for _, r := range recs {
+				readable := r.Book.ID == "emma" || tt.readDune
+				if r.Book.Owned == nil || *r.Book.Owned != readable {
+					t.Errorf("%s: owned = %v, want %v", r.Book.ID, r.Book.Owned, readable)
+				}
+				if shown := r.Book.BookURL != ""; shown != readable {
+					t.Errorf("%s: book_url %q shown = %v, want %v", r.Book.ID, r.Book.BookURL, shown, readable)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package review
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/note"
+	"github.com/bereke1t2/bookstore/internal/domain/review"
+)
+
+// maxNoteCardFront caps the prompt side of a note card.
+const maxNoteCardFront = 160
+
+// CreateCardsFromNotesUseCase turns a reader's notes on a book into review
+// cards. The first sentence of a note is the prompt and the whole note the answer.
+type CreateCardsFromNotesUseCase struct {
+	repo     review.CardRepository
+	noteRepo note.NoteRepository
+}
+
+func NewCreateCardsFromNotesUseCase(repo review.CardRepository, noteRepo note.NoteRepository) *CreateCardsFromNotesUseCase {
+	return &CreateCardsFromNotesUseCase{repo: repo, noteRepo: noteRepo}
+}
+
+func (uc *CreateCardsFromNotesUseCase) Execute(ctx context.Context, bookID string) ([]*review.Card, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if bookID == "" {
+		return nil, review.ErrInvalidCard
+	}
+
+	notes, err := uc.noteRepo.GetByBookID(bookID, p.UserID, note.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var cards []*review.Card
+	for _, n := range notes {
+		content := strings.TrimSpace(n.Content)
+		if content == "" {
+			continue
+		}
+		front := "Recall the note: " + firstSentence(content)
+		cards = append(cards, review.NewCard(p.UserID, bookID, front, content,
+			review.SourceNote, sourceKey(review.SourceNote, bookID, n.ID), now))
+	}
+
+	if len(cards) == 0 {
+		return []*review.Card{}, nil
+	}
+	return uc.repo.CreateMany(cards)
+}
+
+func firstSentence(s string) string {
+	if i := strings.IndexAny(s, ".!?\n"); i > 0 {
+		s = s[:i+1]
+	}
+	if len([]rune(s)) > maxNoteCardFront {
+		s = string([]rune(s)[:maxNoteCardFront]) + "…"
+	}
+	return strings.TrimSpace(s)
+}
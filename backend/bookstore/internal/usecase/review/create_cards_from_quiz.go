@@ -0,0 +1,93 @@
+package review
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/chat"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/review"
+)
+
+// MissedQuestions holds the quiz questions a reader got wrong.
+type MissedQuestions struct {
+	MultipleChoice []*chat.MultipleQuiz `json:"multiple_choice"`
+	TrueFalse      []*chat.TrueFalse    `json:"true_false"`
+	ShortAnswer    []*chat.ShortAnswer  `json:"short_answer"`
+}
+
+// CreateCardsFromQuizUseCase turns missed quiz questions into review cards.
+type CreateCardsFromQuizUseCase struct {
+	repo review.CardRepository
+}
+
+func NewCreateCardsFromQuizUseCase(repo review.CardRepository) *CreateCardsFromQuizUseCase {
+	return &CreateCardsFromQuizUseCase{repo: repo}
+}
+
+func (uc *CreateCardsFromQuizUseCase) Execute(ctx context.Context, bookID string, missed MissedQuestions) ([]*review.Card, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if bookID == "" {
+		return nil, review.ErrInvalidCard
+	}
+
+	now := time.Now()
+	var cards []*review.Card
+	for _, q := range missed.MultipleChoice {
+		if q == nil || q.Validate() != nil {
+			continue
+		}
+		var front strings.Builder
+		front.WriteString(q.Question)
+		for i, option := range q.Options {
+			fmt.Fprintf(&front, "\n%c) %s", 'A'+i, option)
+		}
+		back := fmt.Sprintf("%c) %s", 'A'+q.CorrectIndex, q.Options[q.CorrectIndex])
+		cards = append(cards, review.NewCard(p.UserID, bookID, front.String(), withExplanation(back, q.Explanation),
+			review.SourceMultipleChoice, sourceKey(review.SourceMultipleChoice, bookID, q.Question), now))
+	}
+	for _, q := range missed.TrueFalse {
+		if q == nil || q.Validate() != nil {
+			continue
+		}
+		front := "True or false: " + q.Question
+		back := strings.ToUpper(q.Answer[:1]) + q.Answer[1:]
+		cards = append(cards, review.NewCard(p.UserID, bookID, front, withExplanation(back, q.Explanation),
+			review.SourceTrueFalse, sourceKey(review.SourceTrueFalse, bookID, q.Question), now))
+	}
+	for _, q := range missed.ShortAnswer {
+		if q == nil || q.Validate() != nil {
+			continue
+		}
+		cards = append(cards, review.NewCard(p.UserID, bookID, q.Question, withExplanation(q.CorrectAnswer, q.Explanation),
+			review.SourceShortAnswer, sourceKey(review.SourceShortAnswer, bookID, q.Question), now))
+	}
+
+	if len(cards) == 0 {
+		return []*review.Card{}, nil
+	}
+	return uc.repo.CreateMany(cards)
+}
+
+func withExplanation(answer, explanation string) string {
+	if strings.TrimSpace(explanation) == "" {
+		return answer
+	}
+	return answer + "\n\n" + explanation
+}
+
+// sourceKey identifies the material a card was built from so the same
+// question or note never produces two cards for one user.
+func sourceKey(sourceType, bookID, content string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(content), " "))
+	sum := sha256.Sum256([]byte(sourceType + "\x00" + bookID + "\x00" + normalized))
+	return hex.EncodeToString(sum[:])
+}
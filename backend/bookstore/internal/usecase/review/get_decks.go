@@ -0,0 +1,25 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/review"
+)
+
+type GetDecksUseCase struct {
+	repo review.CardRepository
+}
+
+func NewGetDecksUseCase(repo review.CardRepository) *GetDecksUseCase {
+	return &GetDecksUseCase{repo: repo}
+}
+
+func (uc *GetDecksUseCase) Execute(ctx context.Context) ([]*review.Deck, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetDecks(p.UserID, time.Now())
+}
@@ -0,0 +1,37 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/review"
+)
+
+const (
+	DefaultDueLimit = 20
+	MaxDueLimit     = 100
+)
+
+type GetDueCardsUseCase struct {
+	repo review.CardRepository
+}
+
+func NewGetDueCardsUseCase(repo review.CardRepository) *GetDueCardsUseCase {
+	return &GetDueCardsUseCase{repo: repo}
+}
+
+// Execute lists cards due now. An empty bookID covers every deck.
+func (uc *GetDueCardsUseCase) Execute(ctx context.Context, bookID string, limit int) ([]*review.Card, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultDueLimit
+	}
+	if limit > MaxDueLimit {
+		limit = MaxDueLimit
+	}
+	return uc.repo.GetDue(p.UserID, bookID, time.Now(), limit)
+}
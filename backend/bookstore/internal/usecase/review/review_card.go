@@ -0,0 +1,34 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/review"
+)
+
+// ReviewCardUseCase records a recall grade and reschedules the card.
+type ReviewCardUseCase struct {
+	repo review.CardRepository
+}
+
+func NewReviewCardUseCase(repo review.CardRepository) *ReviewCardUseCase {
+	return &ReviewCardUseCase{repo: repo}
+}
+
+func (uc *ReviewCardUseCase) Execute(ctx context.Context, cardID string, grade int) (*review.Card, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := uc.repo.GetByID(cardID, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := card.Schedule(grade, time.Now()); err != nil {
+		return nil, err
+	}
+	return uc.repo.Update(card)
+}
@@ -0,0 +1,49 @@
+package shelf
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+type AddToShelfUseCase struct {
+	repo  shelf.Repository
+	books book.BookRepository
+}
+
+func NewAddToShelfUseCase(repo shelf.Repository, books book.BookRepository) *AddToShelfUseCase {
+	return &AddToShelfUseCase{repo: repo, books: books}
+}
+
+// Execute puts a book on one of the authenticated user's shelves. Local
+// books are looked up in the catalog; external books keep the details the
+// client sent.
+func (uc *AddToShelfUseCase) Execute(ctx context.Context, ref string, item *shelf.Item) (*shelf.Item, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Source == shelf.SourceLocal {
+		b, err := uc.books.GetBookByID(item.ExternalID)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil {
+			return nil, book.ErrBookNotFound
+		}
+		item.Title, item.Author, item.CoverUrl = b.Title, b.Author, b.CoverUrl
+	}
+	if err := item.Normalize(); err != nil {
+		return nil, err
+	}
+
+	s, err := resolveShelf(uc.repo, p.UserID, ref)
+	if err != nil {
+		return nil, err
+	}
+	item.ShelfID = s.ID
+	return uc.repo.AddItem(p.UserID, item)
+}
@@ -0,0 +1,32 @@
+package shelf
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+type CreateShelfUseCase struct {
+	repo shelf.Repository
+}
+
+func NewCreateShelfUseCase(repo shelf.Repository) *CreateShelfUseCase {
+	return &CreateShelfUseCase{repo: repo}
+}
+
+// Execute adds a custom shelf after the authenticated user's other shelves.
+func (uc *CreateShelfUseCase) Execute(ctx context.Context, name string) (*shelf.Shelf, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err = shelf.NormalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.EnsureBuiltins(p.UserID); err != nil {
+		return nil, err
+	}
+	return uc.repo.CreateShelf(&shelf.Shelf{UserID: p.UserID, Name: name})
+}
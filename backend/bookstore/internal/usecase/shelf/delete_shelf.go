@@ -0,0 +1,26 @@
+package shelf
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+type DeleteShelfUseCase struct {
+	repo shelf.Repository
+}
+
+func NewDeleteShelfUseCase(repo shelf.Repository) *DeleteShelfUseCase {
+	return &DeleteShelfUseCase{repo: repo}
+}
+
+// Execute deletes one of the authenticated user's custom shelves and the
+// items on it.
+func (uc *DeleteShelfUseCase) Execute(ctx context.Context, id string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return uc.repo.DeleteShelf(p.UserID, id)
+}
@@ -0,0 +1,152 @@
+package shelf
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+// goodreadsHeader is the column layout of a Goodreads library export, which
+// Goodreads and most other reading apps can import.
+var goodreadsHeader = []string{
+	"Book Id", "Title", "Author", "Author l-f", "Additional Authors", "ISBN", "ISBN13",
+	"My Rating", "Average Rating", "Publisher", "Binding", "Number of Pages", "Year Published",
+	"Original Publication Year", "Date Read", "Date Added", "Bookshelves", "Bookshelves with positions",
+	"Exclusive Shelf", "My Review", "Spoiler", "Private Notes", "Read Count", "Owned Copies",
+}
+
+// goodreadsShelves maps built-in shelves to Goodreads' exclusive shelves.
+var goodreadsShelves = map[string]string{
+	shelf.KindWantToRead: "to-read",
+	shelf.KindReading:    "currently-reading",
+	shelf.KindFinished:   "read",
+}
+
+const goodreadsDate = "2006/01/02"
+
+var nonShelfTag = regexp.MustCompile(`[^a-z0-9_]+`)
+
+type ExportShelvesUseCase struct {
+	repo shelf.Repository
+}
+
+func NewExportShelvesUseCase(repo shelf.Repository) *ExportShelvesUseCase {
+	return &ExportShelvesUseCase{repo: repo}
+}
+
+// Execute renders the books on one of the authenticated user's shelves, or
+// on all of them when ref is empty, as a Goodreads CSV. Each book is one
+// row listing every shelf it is on.
+func (uc *ExportShelvesUseCase) Execute(ctx context.Context, ref string) (*shelf.Export, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name, shelfID := "all", ""
+	if ref != "" {
+		s, err := resolveShelf(uc.repo, p.UserID, ref)
+		if err != nil {
+			return nil, err
+		}
+		name, shelfID = shelfTag(s.Name), s.ID
+	}
+	rows, err := uc.repo.ListExportRows(p.UserID, shelfID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(goodreadsHeader)
+	for _, row := range rows {
+		w.Write(goodreadsRow(row))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return &shelf.Export{
+		Filename:    fmt.Sprintf("shelves-%s-%s.csv", name, time.Now().UTC().Format("2006-01-02")),
+		ContentType: "text/csv; charset=utf-8",
+		Body:        buf.Bytes(),
+	}, nil
+}
+
+func goodreadsRow(row *shelf.ExportRow) []string {
+	authors := strings.Split(row.Author, ", ")
+	isbn, isbn13 := "", ""
+	switch len(row.ISBN) {
+	case 10:
+		isbn = row.ISBN
+	case 13:
+		isbn13 = row.ISBN
+	}
+
+	exclusive := ""
+	var shelves []string
+	for _, s := range row.Shelves {
+		if gr, ok := goodreadsShelves[s]; ok {
+			exclusive = gr
+			shelves = append(shelves, gr)
+		} else {
+			shelves = append(shelves, shelfTag(s))
+		}
+	}
+	if exclusive == "" {
+		exclusive = goodreadsShelves[shelf.KindWantToRead]
+	}
+
+	dateRead, readCount := "", "0"
+	if exclusive == goodreadsShelves[shelf.KindFinished] {
+		readCount = "1"
+		if row.DateRead != nil {
+			dateRead = row.DateRead.Format(goodreadsDate)
+		}
+	}
+
+	return []string{
+		"", row.Title, authors[0], lastFirst(authors[0]), strings.Join(authors[1:], ", "),
+		goodreadsISBN(isbn), goodreadsISBN(isbn13),
+		strconv.Itoa(row.Rating), "", "", "", "", "",
+		"", dateRead, row.AddedAt.Format(goodreadsDate), strings.Join(shelves, ", "), "",
+		exclusive, "", "", "", readCount, "0",
+	}
+}
+
+// lastFirst turns "Ursula K. Le Guin" into "Guin, Ursula K. Le"; Goodreads
+// itself only splits off the last word.
+func lastFirst(author string) string {
+	i := strings.LastIndex(author, " ")
+	if i < 0 {
+		return author
+	}
+	return author[i+1:] + ", " + author[:i]
+}
+
+// goodreadsISBN quotes an ISBN the way Goodreads does, so spreadsheets keep
+// leading zeros.
+func goodreadsISBN(isbn string) string {
+	if isbn == "" {
+		return `=""`
+	}
+	return `="` + isbn + `"`
+}
+
+// shelfTag turns a shelf name into a Goodreads shelf name.
+func shelfTag(name string) string {
+	tag := strings.Trim(nonShelfTag.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if tag == "" {
+		return "shelf"
+	}
+	return tag
+}
@@ -0,0 +1,34 @@
+package shelf
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+type GetShelfUseCase struct {
+	repo shelf.Repository
+}
+
+func NewGetShelfUseCase(repo shelf.Repository) *GetShelfUseCase {
+	return &GetShelfUseCase{repo: repo}
+}
+
+// Execute returns one of the authenticated user's shelves, by ID or
+// built-in kind, with its items in order.
+func (uc *GetShelfUseCase) Execute(ctx context.Context, ref string) (*shelf.Contents, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s, err := resolveShelf(uc.repo, p.UserID, ref)
+	if err != nil {
+		return nil, err
+	}
+	items, err := uc.repo.ListItems(s.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &shelf.Contents{Shelf: s, Items: items}, nil
+}
@@ -0,0 +1,29 @@
+package shelf
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+type ListShelvesUseCase struct {
+	repo shelf.Repository
+}
+
+func NewListShelvesUseCase(repo shelf.Repository) *ListShelvesUseCase {
+	return &ListShelvesUseCase{repo: repo}
+}
+
+// Execute lists the authenticated user's shelves in order, built-in shelves
+// included.
+func (uc *ListShelvesUseCase) Execute(ctx context.Context) ([]*shelf.Shelf, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.EnsureBuiltins(p.UserID); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListShelves(p.UserID)
+}
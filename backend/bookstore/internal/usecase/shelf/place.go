@@ -0,0 +1,32 @@
+package shelf
+
+import (
+	"log"
+
+	"github.com/bereke1t2/bookstore/internal/domain/book"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+// Place moves a catalog book onto one of the user's built-in shelves on
+// behalf of another usecase, such as reading progress. Like activity.Record
+// it logs failures rather than failing that action, and a nil repo does
+// nothing.
+func Place(repo shelf.Repository, userID int, kind string, b *book.Book) {
+	if repo == nil {
+		return
+	}
+	s, err := resolveShelf(repo, userID, kind)
+	if err == nil {
+		_, err = repo.AddItem(userID, &shelf.Item{
+			ShelfID:    s.ID,
+			Source:     shelf.SourceLocal,
+			ExternalID: b.ID,
+			Title:      b.Title,
+			Author:     b.Author,
+			CoverUrl:   b.CoverUrl,
+		})
+	}
+	if err != nil {
+		log.Printf("shelves: place book %s on %s for user %d: %v", b.ID, kind, userID, err)
+	}
+}
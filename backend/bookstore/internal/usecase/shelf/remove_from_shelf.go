@@ -0,0 +1,29 @@
+package shelf
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+type RemoveFromShelfUseCase struct {
+	repo shelf.Repository
+}
+
+func NewRemoveFromShelfUseCase(repo shelf.Repository) *RemoveFromShelfUseCase {
+	return &RemoveFromShelfUseCase{repo: repo}
+}
+
+// Execute takes an item off one of the authenticated user's shelves.
+func (uc *RemoveFromShelfUseCase) Execute(ctx context.Context, ref, itemID string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	s, err := resolveShelf(uc.repo, p.UserID, ref)
+	if err != nil {
+		return err
+	}
+	return uc.repo.RemoveItem(s.ID, itemID)
+}
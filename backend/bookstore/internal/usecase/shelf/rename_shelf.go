@@ -0,0 +1,29 @@
+package shelf
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+type RenameShelfUseCase struct {
+	repo shelf.Repository
+}
+
+func NewRenameShelfUseCase(repo shelf.Repository) *RenameShelfUseCase {
+	return &RenameShelfUseCase{repo: repo}
+}
+
+// Execute renames one of the authenticated user's custom shelves.
+func (uc *RenameShelfUseCase) Execute(ctx context.Context, id, name string) (*shelf.Shelf, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err = shelf.NormalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.RenameShelf(p.UserID, id, name)
+}
@@ -0,0 +1,37 @@
+package shelf
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+type ReorderShelfUseCase struct {
+	repo shelf.Repository
+}
+
+func NewReorderShelfUseCase(repo shelf.Repository) *ReorderShelfUseCase {
+	return &ReorderShelfUseCase{repo: repo}
+}
+
+// Execute puts a shelf's items in the order of itemIDs, which must list
+// every item on the shelf, and returns the reordered shelf.
+func (uc *ReorderShelfUseCase) Execute(ctx context.Context, ref string, itemIDs []string) (*shelf.Contents, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s, err := resolveShelf(uc.repo, p.UserID, ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.ReorderItems(s.ID, itemIDs); err != nil {
+		return nil, err
+	}
+	items, err := uc.repo.ListItems(s.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &shelf.Contents{Shelf: s, Items: items}, nil
+}
@@ -0,0 +1,29 @@
+package shelf
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+type ReorderShelvesUseCase struct {
+	repo shelf.Repository
+}
+
+func NewReorderShelvesUseCase(repo shelf.Repository) *ReorderShelvesUseCase {
+	return &ReorderShelvesUseCase{repo: repo}
+}
+
+// Execute puts the authenticated user's shelves in the order of ids, which
+// must list every shelf, and returns the reordered shelves.
+func (uc *ReorderShelvesUseCase) Execute(ctx context.Context, ids []string) ([]*shelf.Shelf, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.ReorderShelves(p.UserID, ids); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListShelves(p.UserID)
+}
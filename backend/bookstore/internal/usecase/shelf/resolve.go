@@ -0,0 +1,20 @@
+package shelf
+
+import (
+	"errors"
+
+	"github.com/bereke1t2/bookstore/internal/domain/shelf"
+)
+
+// resolveShelf finds the user's shelf by ID or built-in kind. Built-in
+// shelves are created the first time they are asked for.
+func resolveShelf(repo shelf.Repository, userID int, ref string) (*shelf.Shelf, error) {
+	s, err := repo.GetShelf(userID, ref)
+	if !errors.Is(err, shelf.ErrShelfNotFound) || !shelf.IsBuiltin(ref) {
+		return s, err
+	}
+	if err := repo.EnsureBuiltins(userID); err != nil {
+		return nil, err
+	}
+	return repo.GetShelf(userID, ref)
+}
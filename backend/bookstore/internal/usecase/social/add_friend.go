@@ -0,0 +1,28 @@
+package social
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/social"
+)
+
+type AddFriendUseCase struct {
+	repo social.FriendRepository
+}
+
+func NewAddFriendUseCase(repo social.FriendRepository) *AddFriendUseCase {
+	return &AddFriendUseCase{repo: repo}
+}
+
+// Execute adds friendID to the authenticated user's friends list.
+func (uc *AddFriendUseCase) Execute(ctx context.Context, friendID int) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	if friendID == p.UserID {
+		return social.ErrInvalidFriend
+	}
+	return uc.repo.AddFriend(p.UserID, friendID)
+}
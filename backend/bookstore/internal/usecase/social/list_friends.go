@@ -0,0 +1,25 @@
+package social
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/social"
+)
+
+type ListFriendsUseCase struct {
+	repo social.FriendRepository
+}
+
+func NewListFriendsUseCase(repo social.FriendRepository) *ListFriendsUseCase {
+	return &ListFriendsUseCase{repo: repo}
+}
+
+// Execute lists the authenticated user's friends.
+func (uc *ListFriendsUseCase) Execute(ctx context.Context) ([]*social.Friend, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.ListFriends(p.UserID)
+}
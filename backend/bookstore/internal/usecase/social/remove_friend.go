@@ -0,0 +1,25 @@
+package social
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/social"
+)
+
+type RemoveFriendUseCase struct {
+	repo social.FriendRepository
+}
+
+func NewRemoveFriendUseCase(repo social.FriendRepository) *RemoveFriendUseCase {
+	return &RemoveFriendUseCase{repo: repo}
+}
+
+// Execute removes friendID from the authenticated user's friends list.
+func (uc *RemoveFriendUseCase) Execute(ctx context.Context, friendID int) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return uc.repo.RemoveFriend(p.UserID, friendID)
+}
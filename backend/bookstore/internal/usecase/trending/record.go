@@ -0,0 +1,23 @@
+package trending
+
+import (
+	"log"
+
+	"github.com/bereke1t2/bookstore/internal/domain/trending"
+)
+
+// Record counts e toward the trending lists. The counts only rank books, so
+// a view or download still succeeds when its event cannot be stored; the
+// error is logged with the book it was for. A nil repo counts nothing.
+func Record(repo trending.Repository, e *trending.Event) {
+	if repo == nil {
+		return
+	}
+	if err := repo.Record(e); err != nil {
+		ref := e.BookID
+		if ref == "" {
+			ref = e.BookTitle
+		}
+		log.Printf("trending: record %s for book %q: %v", e.Kind, ref, err)
+	}
+}
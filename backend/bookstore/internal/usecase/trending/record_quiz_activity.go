@@ -0,0 +1,33 @@
+package trending
+
+import (
+	"context"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/trending"
+)
+
+type RecordQuizActivityUseCase struct {
+	repo trending.Repository
+}
+
+func NewRecordQuizActivityUseCase(repo trending.Repository) *RecordQuizActivityUseCase {
+	return &RecordQuizActivityUseCase{repo: repo}
+}
+
+// Execute counts a quiz about bookName towards the book's trending score.
+// Quizzes name books by title, so quizzes on books outside the catalog are
+// ignored.
+func (uc *RecordQuizActivityUseCase) Execute(ctx context.Context, bookName string) {
+	p, ok := identity.FromContext(ctx)
+	if !ok {
+		return
+	}
+	Record(uc.repo, &trending.Event{
+		BookTitle:  bookName,
+		UserID:     p.UserID,
+		Kind:       trending.KindQuiz,
+		OccurredAt: time.Now(),
+	})
+}
@@ -0,0 +1,42 @@
+package trending
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/bereke1t2/bookstore/internal/domain/trending"
+)
+
+type RefreshTrendingUseCase struct {
+	repo trending.Repository
+	cfg  trending.Config
+}
+
+func NewRefreshTrendingUseCase(repo trending.Repository, cfg trending.Config) *RefreshTrendingUseCase {
+	return &RefreshTrendingUseCase{repo: repo, cfg: cfg}
+}
+
+// Execute recomputes the trending scores and returns how many books have one.
+func (uc *RefreshTrendingUseCase) Execute() (int, error) {
+	return uc.repo.Refresh(uc.cfg, time.Now())
+}
+
+// Run refreshes the scores straight away and then every refresh interval
+// until ctx is done.
+func (uc *RefreshTrendingUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(uc.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		if n, err := uc.Execute(); err != nil {
+			log.Printf("trending: refresh: %v", err)
+		} else {
+			log.Printf("trending: refreshed scores for %d books", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package user
 
-import "github.com/bereke1t2/bookstore/internal/domain/user"
+import (
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+)
 
 
 type CreateUserUseCase struct {
@@ -12,10 +16,16 @@ func NewCreateUserUseCase(userRepo user.UserRepository) *CreateUserUseCase {
 		userRepo: userRepo,
 	}
 }
+// Execute creates newUser with the user role. It returns ErrAlreadyExists
+// if the email is taken, whatever its case.
 func (uc *CreateUserUseCase) Execute(newUser user.User) (user.User, error) {
+	newUser.Email = strings.TrimSpace(newUser.Email)
 	us , err := uc.userRepo.GetUserByEmail(newUser.Email)
-	if err == nil && us.ID != 0 {
-		return user.User{},  nil
+	if err != nil {
+		return user.User{}, err
+	}
+	if us.ID != 0 {
+		return user.User{}, user.ErrAlreadyExists
 	}
 	createdUser, err := uc.userRepo.CreateUser(newUser)
 	if err != nil {
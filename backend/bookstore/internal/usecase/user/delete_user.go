@@ -1,6 +1,11 @@
 package user
 
-import "github.com/bereke1t2/bookstore/internal/domain/user"
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+)
 
 
 type DeleteUserUseCase struct {
@@ -12,7 +17,14 @@ func NewDeleteUserUsecase(userRepo user.UserRepository) *DeleteUserUseCase {
 		userRepo: userRepo,
 	}
 }
-func (uc *DeleteUserUseCase) Execute(updatedUser user.User) (user.User, error) {
+func (uc *DeleteUserUseCase) Execute(ctx context.Context, updatedUser user.User) (user.User, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return user.User{}, err
+	}
+	if !p.CanActAs(updatedUser.ID) {
+		return user.User{}, identity.ErrForbidden
+	}
 	updated, err := uc.userRepo.UpdateUser(updatedUser)
 	if err != nil {
 		return user.User{}, err
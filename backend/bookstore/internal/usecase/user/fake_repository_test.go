@@ -0,0 +1,82 @@
+package user
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+)
+
+// fakeUserRepository keeps users in memory. Like the Postgres store it
+// matches emails regardless of case, keeps them unique and never writes
+// roles outside SetRole.
+type fakeUserRepository struct {
+	users map[int]user.User
+	next  int
+}
+
+func newFakeUserRepository() *fakeUserRepository {
+	return &fakeUserRepository{users: map[int]user.User{}}
+}
+
+func (f *fakeUserRepository) CreateUser(u user.User) (user.User, error) {
+	if existing, _ := f.GetUserByEmail(u.Email); existing.ID != 0 {
+		return user.User{}, user.ErrAlreadyExists
+	}
+	f.next++
+	u.ID, u.Role = f.next, identity.RoleUser
+	f.users[u.ID] = u
+	return u, nil
+}
+
+func (f *fakeUserRepository) GetUserByID(id string) (user.User, error) {
+	n, _ := strconv.Atoi(id)
+	return f.users[n], nil
+}
+
+func (f *fakeUserRepository) UpdateUser(u user.User) (user.User, error) {
+	stored, ok := f.users[u.ID]
+	if !ok {
+		return user.User{}, user.ErrNotFound
+	}
+	if existing, _ := f.GetUserByEmail(u.Email); existing.ID != 0 && existing.ID != u.ID {
+		return user.User{}, user.ErrAlreadyExists
+	}
+	u.Role = stored.Role
+	f.users[u.ID] = u
+	return u, nil
+}
+
+func (f *fakeUserRepository) DeleteUser(id string) error {
+	n, _ := strconv.Atoi(id)
+	delete(f.users, n)
+	return nil
+}
+
+func (f *fakeUserRepository) GetAllUsers() ([]user.User, error) {
+	var users []user.User
+	for _, u := range f.users {
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (f *fakeUserRepository) GetUserByEmail(email string) (user.User, error) {
+	for _, u := range f.users {
+		if strings.EqualFold(u.Email, strings.TrimSpace(email)) {
+			return u, nil
+		}
+	}
+	return user.User{}, nil
+}
+
+func (f *fakeUserRepository) SetRole(id int, role string) error {
+	u, ok := f.users[id]
+	if !ok {
+		return user.ErrNotFound
+	}
+	u.Role = role
+	f.users[id] = u
+	return nil
+}
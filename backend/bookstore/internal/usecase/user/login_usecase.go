@@ -4,6 +4,7 @@ import (
 	"errors"
 	// "go/token"
 
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
 	"github.com/bereke1t2/bookstore/internal/domain/user"
 	"github.com/bereke1t2/bookstore/internal/infrastructure/security"
 )
@@ -17,14 +18,43 @@ func NewLoginUseCase(userRepo user.UserRepository) *LoginUseCase {
 }
 
 func (uc *LoginUseCase) Execute(email, password string) (user.User, string, error) {
-	u, err := uc.userRepo.GetUserByEmail(email)
+	u, roles, err := uc.check(email, password)
 	if err != nil {
 		return user.User{}, "", err
 	}
+	token, err := security.GenerateJWT(u.ID, roles...)
+	return u, token, nil
+}
+
+// Authenticate checks a user's credentials and returns them as a principal,
+// for clients such as OPDS readers that send them with every request.
+func (uc *LoginUseCase) Authenticate(email, password string) (identity.Principal, error) {
+	u, roles, err := uc.check(email, password)
+	if err != nil {
+		return identity.Principal{}, err
+	}
+	return identity.Principal{UserID: u.ID, Roles: roles}, nil
+}
+
+// Roles returns the token roles for u. The admin role comes only from the
+// user's stored role, which only an admin can set.
+func Roles(u user.User) []string {
+	roles := []string{identity.RoleUser}
+	if u.Role == identity.RoleAdmin {
+		roles = append(roles, identity.RoleAdmin)
+	}
+	return roles
+}
+
+// check verifies the password and returns the user with their roles.
+func (uc *LoginUseCase) check(email, password string) (user.User, []string, error) {
+	u, err := uc.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return user.User{}, nil, err
+	}
 	check := security.CheckPasswordHash(password, u.PasswordHash)
 	if !check {
-		return user.User{}, "", errors.New("invalid credentials")
+		return user.User{}, nil, errors.New("invalid credentials")
 	}
-	token, err := security.GenerateJWT(u.ID)
-	return u, token, nil
-}
\ No newline at end of file
+	return u, Roles(u), nil
+}
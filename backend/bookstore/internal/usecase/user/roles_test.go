@@ -0,0 +1,107 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+	"github.com/bereke1t2/bookstore/internal/infrastructure/security"
+)
+
+func asUser(userID int, roles ...string) context.Context {
+	if len(roles) == 0 {
+		roles = []string{identity.RoleUser}
+	}
+	return identity.WithPrincipal(context.Background(), identity.Principal{UserID: userID, Roles: roles})
+}
+
+func signUp(t *testing.T, repo *fakeUserRepository, email string) user.User {
+	t.Helper()
+	hash, err := security.HashPassword("secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := NewCreateUserUseCase(repo).Execute(user.User{Username: email, Email: email, PasswordHash: hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func loginRoles(t *testing.T, repo *fakeUserRepository, email string) []string {
+	t.Helper()
+	p, err := NewLoginUseCase(repo).Authenticate(email, "secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.Roles
+}
+
+func TestEmailsAreUniqueRegardlessOfCase(t *testing.T) {
+	repo := newFakeUserRepository()
+	alice := signUp(t, repo, "alice@example.com")
+
+	if _, err := NewCreateUserUseCase(repo).Execute(user.User{Email: " Alice@Example.COM"}); !errors.Is(err, user.ErrAlreadyExists) {
+		t.Errorf("sign up with alice's email in capitals: got %v, want ErrAlreadyExists", err)
+	}
+
+	bob := signUp(t, repo, "bob@example.com")
+	bob.Email = "ALICE@example.com"
+	if _, err := NewUpdateUserUseCase(repo).Execute(asUser(bob.ID), bob); !errors.Is(err, user.ErrAlreadyExists) {
+		t.Errorf("bob taking alice's email: got %v, want ErrAlreadyExists", err)
+	}
+
+	alice.Email = "Alice@Example.com"
+	if _, err := NewUpdateUserUseCase(repo).Execute(asUser(alice.ID), alice); err != nil {
+		t.Errorf("alice changing the case of her own email: %v", err)
+	}
+	if _, err := NewLoginUseCase(repo).Authenticate("ALICE@EXAMPLE.COM", "secret123"); err != nil {
+		t.Errorf("login ignores email case: %v", err)
+	}
+}
+
+func TestAdminRoleComesOnlyFromTheStoredRole(t *testing.T) {
+	repo := newFakeUserRepository()
+	admin := signUp(t, repo, "admin@example.com")
+	mallory := signUp(t, repo, "mallory@example.com")
+
+	if roles := loginRoles(t, repo, "admin@example.com"); slices.Contains(roles, identity.RoleAdmin) {
+		t.Fatalf("a new user logged in with roles %v", roles)
+	}
+	if err := repo.SetRole(admin.ID, identity.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if roles := loginRoles(t, repo, "admin@example.com"); !slices.Contains(roles, identity.RoleAdmin) {
+		t.Errorf("an admin logged in with roles %v", roles)
+	}
+
+	setRole := NewSetUserRoleUseCase(repo)
+	if err := setRole.Execute(asUser(mallory.ID), mallory.ID, identity.RoleAdmin); !errors.Is(err, identity.ErrForbidden) {
+		t.Errorf("user promoting themselves: got %v, want ErrForbidden", err)
+	}
+	if err := setRole.Execute(asUser(admin.ID, identity.RoleUser, identity.RoleAdmin), admin.ID, identity.RoleUser); !errors.Is(err, identity.ErrForbidden) {
+		t.Errorf("admin demoting themselves: got %v, want ErrForbidden", err)
+	}
+	if err := setRole.Execute(asUser(admin.ID, identity.RoleUser, identity.RoleAdmin), mallory.ID, "owner"); !errors.Is(err, user.ErrInvalidInput) {
+		t.Errorf("unknown role: got %v, want ErrInvalidInput", err)
+	}
+
+	// Updating the profile, even to an admin's former email, grants nothing.
+	mallory.Email, mallory.Role = "ADMIN@example.org", identity.RoleAdmin
+	if _, err := NewUpdateUserUseCase(repo).Execute(asUser(mallory.ID), mallory); err != nil {
+		t.Fatal(err)
+	}
+	if roles := loginRoles(t, repo, "admin@example.org"); slices.Contains(roles, identity.RoleAdmin) {
+		t.Errorf("profile update granted roles %v", roles)
+	}
+
+	if err := setRole.Execute(asUser(admin.ID, identity.RoleUser, identity.RoleAdmin), mallory.ID, identity.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if roles := loginRoles(t, repo, "admin@example.org"); !slices.Contains(roles, identity.RoleAdmin) {
+		t.Errorf("promoted user logged in with roles %v", roles)
+	}
+}
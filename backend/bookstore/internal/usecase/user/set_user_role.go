@@ -0,0 +1,37 @@
+package user
+
+import (
+	"context"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+)
+
+type SetUserRoleUseCase struct {
+	userRepo user.UserRepository
+}
+
+func NewSetUserRoleUseCase(userRepo user.UserRepository) *SetUserRoleUseCase {
+	return &SetUserRoleUseCase{userRepo: userRepo}
+}
+
+// Execute gives the user with userID the role identity.RoleUser or
+// identity.RoleAdmin. Only admins may change roles, and an admin cannot
+// drop their own admin role, so there is always one left. The change takes
+// effect when the user next logs in.
+func (uc *SetUserRoleUseCase) Execute(ctx context.Context, userID int, role string) error {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	if !p.IsAdmin() {
+		return identity.ErrForbidden
+	}
+	if role != identity.RoleUser && role != identity.RoleAdmin {
+		return user.ErrInvalidInput
+	}
+	if userID == p.UserID && role != identity.RoleAdmin {
+		return identity.ErrForbidden
+	}
+	return uc.userRepo.SetRole(userID, role)
+}
@@ -1,6 +1,12 @@
 package user
 
-import "github.com/bereke1t2/bookstore/internal/domain/user"
+import (
+	"context"
+	"strings"
+
+	"github.com/bereke1t2/bookstore/internal/domain/identity"
+	"github.com/bereke1t2/bookstore/internal/domain/user"
+)
 
 
 type UpdateUserUseCase struct {
@@ -12,7 +18,25 @@ func NewUpdateUserUseCase(userRepo user.UserRepository) *UpdateUserUseCase {
 		userRepo: userRepo,
 	}
 }
-func (uc *UpdateUserUseCase) Execute(updatedUser user.User) (user.User, error) {
+// Execute saves updatedUser. Only the user themselves or an admin may update a user.
+// The email must not belong to another user, whatever its case; it returns
+// ErrAlreadyExists if it does.
+func (uc *UpdateUserUseCase) Execute(ctx context.Context, updatedUser user.User) (user.User, error) {
+	p, err := identity.Require(ctx)
+	if err != nil {
+		return user.User{}, err
+	}
+	if !p.CanActAs(updatedUser.ID) {
+		return user.User{}, identity.ErrForbidden
+	}
+	updatedUser.Email = strings.TrimSpace(updatedUser.Email)
+	owner, err := uc.userRepo.GetUserByEmail(updatedUser.Email)
+	if err != nil {
+		return user.User{}, err
+	}
+	if owner.ID != 0 && owner.ID != updatedUser.ID {
+		return user.User{}, user.ErrAlreadyExists
+	}
 	updated, err := uc.userRepo.UpdateUser(updatedUser)
 	if err != nil {
 		return user.User{}, err
@@ -18,6 +18,7 @@ class UrlConst {
   static const String trueFalseQuestionsEndpoint = "/chats/questions/true-false";
   static const String multipleChoiceQuestionsEndpoint = "/chats/questions/multiple-choice";
   static const String shortAnswerQuestionsEndpoint = "/chats/questions/short-answer";
+  static const String gradeShortAnswerEndpoint = "/quizzes/short-answer";
 
   static const String userProfileEndpoint ="/users";
   static const String updateProfileEndpoint ="/users";
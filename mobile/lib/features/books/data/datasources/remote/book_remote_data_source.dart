@@ -185,13 +185,10 @@ class BookRemoteDataSourceImpl implements BookRemoteDataSource {
       final file = File(bookPath);
       final coverFile = File(coverPath);
 
-      String url = book.bookUrl;
-      if (!url.startsWith('http')) {
-        if (!url.startsWith('/')) {
-          url = "/$url";
-        }
-        url = "${UrlConst.baseUrl}$url";
-      }
+      // Book files are only served through the download route, which
+      // checks the user may read the book
+      final String url =
+          "${UrlConst.baseUrl}${UrlConst.booksEndpoint}/${book.id}/download";
       String coverUrl = book.coverUrl;
       if (!coverUrl.startsWith('http')) {
         if (!coverUrl.startsWith('/')) {
@@ -213,7 +210,9 @@ class BookRemoteDataSourceImpl implements BookRemoteDataSource {
       }
 
       // 2. Download book with progress
+      final token = await localdata.getToken();
       final request = http.Request('GET', Uri.parse(url));
+      request.headers["Authorization"] = "$token";
       final response = await httpClient.send(request);
 
       if (response.statusCode == 200) {
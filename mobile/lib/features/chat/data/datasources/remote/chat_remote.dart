@@ -5,6 +5,8 @@ import 'package:ethio_book_store/features/auth/data/datasources/local/localdata.
 import 'package:ethio_book_store/features/chat/data/datasources/local/chat_local.dart';
 import 'package:ethio_book_store/features/chat/domain/entities/multiple_questions.dart';
 import 'package:ethio_book_store/features/chat/domain/entities/short_answer.dart';
+import 'package:ethio_book_store/features/chat/domain/entities/short_answer_grade.dart';
+import 'package:ethio_book_store/features/chat/data/models/short_answer_grade_model.dart';
 import 'package:ethio_book_store/features/chat/domain/entities/true_false.dart';
 import 'package:ethio_book_store/features/chat/data/models/short_answer_model.dart';
 import 'package:ethio_book_store/features/chat/data/models/true_false_model.dart';
@@ -17,6 +19,7 @@ abstract class ChatRemoteDataSource {
   Future<List<TrueFalse>> getTrueFalseQuestion(String bookName);
   Future<List<MultipleQuestions>> getMultipleQuestions(String bookName);
   Future<List<ShortAnswer>> getShortAnswerQuestion(String bookName);
+  Future<ShortAnswerGrade> gradeShortAnswer(String bookName, ShortAnswer question, String answer);
 }
 
 class ChatRemoteDataSourceImpl implements ChatRemoteDataSource {
@@ -165,4 +168,31 @@ class ChatRemoteDataSourceImpl implements ChatRemoteDataSource {
       rethrow;
     }
   }
-}
\ No newline at end of file
+
+  @override
+  Future<ShortAnswerGrade> gradeShortAnswer(String bookName, ShortAnswer question, String answer) async {
+    try {
+      final token = await localData.getToken();
+      final response = await client.post(
+        Uri.parse('${UrlConst.baseUrl}${UrlConst.gradeShortAnswerEndpoint}/1/grade'),
+        headers: {
+          'Authorization': 'Bearer $token',
+          'Content-Type': 'application/json',
+        },
+        body: jsonEncode({
+          'book_name': bookName,
+          'question_id': question.questionId,
+          'answer': answer,
+        }),
+      );
+      if (response.statusCode == 200) {
+        return ShortAnswerGradeModel.fromJson(jsonDecode(response.body));
+      } else {
+        throw Exception('Failed to grade short answer');
+      }
+    } catch (e) {
+      print('Error grading short answer: $e');
+      rethrow;
+    }
+  }
+}
@@ -0,0 +1,21 @@
+import 'package:ethio_book_store/features/chat/domain/entities/short_answer_grade.dart';
+
+class ShortAnswerGradeModel extends ShortAnswerGrade {
+  const ShortAnswerGradeModel({
+    required super.score,
+    required super.verdict,
+    required super.feedback,
+    required super.answer,
+    required super.explanation,
+  });
+
+  factory ShortAnswerGradeModel.fromJson(Map<String, dynamic> json) {
+    return ShortAnswerGradeModel(
+      score: (json['score'] as num? ?? 0).toDouble(),
+      verdict: json['verdict'] ?? '',
+      feedback: json['feedback'] ?? '',
+      answer: json['answer'] ?? '',
+      explanation: json['explanation'] ?? '',
+    );
+  }
+}
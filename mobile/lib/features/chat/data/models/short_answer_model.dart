@@ -2,24 +2,27 @@ import 'package:ethio_book_store/features/chat/domain/entities/short_answer.dart
 
 class ShortAnswerModel extends ShortAnswer{
   const ShortAnswerModel({
+    super.questionId,
     required super.question,
-    required super.answer,
-    required super.explanation,
+    super.answer,
+    super.explanation,
   });
 
   factory ShortAnswerModel.fromJson(Map<String, dynamic> json) {
     return ShortAnswerModel(
+      questionId: json['question_id'] ?? '',
       question: json['question'],
-      answer: json['answer'],
-      explanation: json['explanation'],
+      answer: json['answer'] ?? '',
+      explanation: json['explanation'] ?? '',
     );
   }
 
   Map<String, dynamic> toJson() {
     return {
+      'question_id': questionId,
       'question': question,
       'answer': answer,
       'explanation': explanation,
     };
   }
-}
\ No newline at end of file
+}
@@ -5,6 +5,7 @@ import 'package:ethio_book_store/features/chat/data/datasources/local/chat_local
 import 'package:ethio_book_store/features/chat/data/datasources/remote/chat_remote.dart';
 import 'package:ethio_book_store/features/chat/domain/entities/multiple_questions.dart';
 import 'package:ethio_book_store/features/chat/domain/entities/short_answer.dart';
+import 'package:ethio_book_store/features/chat/domain/entities/short_answer_grade.dart';
 import 'package:ethio_book_store/features/chat/domain/entities/true_false.dart';
 import 'package:ethio_book_store/features/chat/domain/repositories/chat_repository.dart';
 
@@ -116,4 +117,16 @@ class ChatRepositoryImpl implements ChatRepository {
       }
     }
   }
-}
\ No newline at end of file
+
+  @override
+  Future<Either<Failure, ShortAnswerGrade>> gradeShortAnswer(String bookName, ShortAnswer question, String answer) async {
+    if (!await networkInfo.isConnected) {
+      return Left(Failure('Answers are graded online. Check your connection and try again.'));
+    }
+    try {
+      return Right(await remoteDataSource.gradeShortAnswer(bookName, question, answer));
+    } catch (e) {
+      return Left(Failure(e.toString()));
+    }
+  }
+}
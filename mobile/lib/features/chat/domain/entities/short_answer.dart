@@ -1,16 +1,20 @@
 import 'package:equatable/equatable.dart';
 
+/// A short-answer question issued by the server. The answer and explanation
+/// are withheld until the question is graded, so they start out empty.
 class ShortAnswer extends Equatable {
+  final String questionId;
   final String question;
   final String answer;
   final String explanation;
 
   const ShortAnswer({
+    this.questionId = '',
     required this.question,
-    required this.answer,
-    required this.explanation,
+    this.answer = '',
+    this.explanation = '',
   });
 
   @override
-  List<Object?> get props => [question, answer, explanation];
+  List<Object?> get props => [questionId, question, answer, explanation];
 }
@@ -0,0 +1,24 @@
+import 'package:equatable/equatable.dart';
+
+/// The server's grade for an answer to a short-answer question, with the
+/// expected answer and its explanation.
+class ShortAnswerGrade extends Equatable {
+  final double score;
+  final String verdict;
+  final String feedback;
+  final String answer;
+  final String explanation;
+
+  const ShortAnswerGrade({
+    required this.score,
+    required this.verdict,
+    required this.feedback,
+    required this.answer,
+    required this.explanation,
+  });
+
+  bool get isCorrect => verdict == 'correct';
+
+  @override
+  List<Object?> get props => [score, verdict, feedback, answer, explanation];
+}
@@ -0,0 +1,15 @@
+import 'package:dartz/dartz.dart';
+import 'package:ethio_book_store/core/errors/failure.dart';
+import 'package:ethio_book_store/features/chat/domain/entities/short_answer.dart';
+import 'package:ethio_book_store/features/chat/domain/entities/short_answer_grade.dart';
+import 'package:ethio_book_store/features/chat/domain/repositories/chat_repository.dart';
+
+class GradeShortAnswerUseCase {
+  final ChatRepository repository;
+
+  GradeShortAnswerUseCase(this.repository);
+
+  Future<Either<Failure, ShortAnswerGrade>> call(String bookName, ShortAnswer question, String answer) {
+    return repository.gradeShortAnswer(bookName, question, answer);
+  }
+}
@@ -7,6 +7,11 @@ import 'package:ethio_book_store/features/chat/presentation/bloc/chat_bloc.dart'
 import 'package:ethio_book_store/features/chat/domain/entities/multiple_questions.dart';
 import 'package:ethio_book_store/features/chat/domain/entities/true_false.dart' as tf;
 import 'package:ethio_book_store/features/chat/domain/entities/short_answer.dart' as sa;
+import 'package:ethio_book_store/features/chat/domain/entities/short_answer_grade.dart';
+import 'package:ethio_book_store/features/chat/domain/usecases/gradeShortAnswerUsecase.dart';
+import 'package:ethio_book_store/core/errors/failure.dart';
+import 'package:ethio_book_store/injections.dart' as di;
+import 'package:dartz/dartz.dart' show Either;
 
 enum ChatRole { user, assistant }
 enum QuizType { multipleChoice, trueFalse, shortAnswer }
@@ -446,6 +451,7 @@ class _ChatPageState extends State<ChatPage> with SingleTickerProviderStateMixin
               borderColor: Colors.white.withValues(alpha: 66 / 255),
               child: QuizSheetShortAnswer(
                 questions: state.questions,
+                grade: (q, answer) => di.sl<GradeShortAnswerUseCase>()(widget.bookTitle, q, answer),
                 onSubmit: (score, total) {
                   Navigator.of(context, rootNavigator: true).pop();
                   final result = 'Short answer complete: $score/$total scored. Want sample answers?';
@@ -1245,14 +1251,17 @@ class _QuizSheetTrueFalseState extends State<QuizSheetTrueFalse> {
   }
 }
 
-/// Short Answer sheet (uses domain entity)
+/// Short Answer sheet (uses domain entity). Answers are graded by the
+/// server, which only reveals the expected answer once one is submitted.
 class QuizSheetShortAnswer extends StatefulWidget {
   final List<sa.ShortAnswer> questions;
+  final Future<Either<Failure, ShortAnswerGrade>> Function(sa.ShortAnswer question, String answer) grade;
   final void Function(int score, int total) onSubmit;
 
   const QuizSheetShortAnswer({
     super.key,
     required this.questions,
+    required this.grade,
     required this.onSubmit,
   });
 
@@ -1262,15 +1271,15 @@ class QuizSheetShortAnswer extends StatefulWidget {
 
 class _QuizSheetShortAnswerState extends State<QuizSheetShortAnswer> {
   late final List<TextEditingController> _answers;
-  late final List<bool> _checked;
-  late final List<bool> _corrects;
+  late final List<ShortAnswerGrade?> _grades;
+  late final List<bool> _grading;
 
   @override
   void initState() {
     super.initState();
     _answers = List.generate(widget.questions.length, (_) => TextEditingController());
-    _checked = List.filled(widget.questions.length, false);
-    _corrects = List.filled(widget.questions.length, false);
+    _grades = List.filled(widget.questions.length, null);
+    _grading = List.filled(widget.questions.length, false);
   }
 
   @override
@@ -1281,26 +1290,32 @@ class _QuizSheetShortAnswerState extends State<QuizSheetShortAnswer> {
     super.dispose();
   }
 
-  void _check(int i) {
-    final expected = widget.questions[i].answer.toLowerCase().trim();
-    final got = _answers[i].text.toLowerCase().trim();
-    setState(() {
-      _checked[i] = true;
-      _corrects[i] = got == expected;
-    });
+  Future<void> _check(int i) async {
+    final got = _answers[i].text.trim();
+    if (got.isEmpty) return;
+    setState(() => _grading[i] = true);
+    final result = await widget.grade(widget.questions[i], got);
+    if (!mounted) return;
+    setState(() => _grading[i] = false);
+    result.fold(
+      (failure) => ScaffoldMessenger.of(context).showSnackBar(
+        SnackBar(content: Text(failure.message)),
+      ),
+      (grade) => setState(() => _grades[i] = grade),
+    );
   }
 
   void _submit() {
     int score = 0;
-    for (var i = 0; i < widget.questions.length; i++) {
-      if (_corrects[i]) score++;
+    for (final g in _grades) {
+      if (g != null && g.isCorrect) score++;
     }
     widget.onSubmit(score, widget.questions.length);
   }
 
   @override
   Widget build(BuildContext context) {
-    final allChecked = _checked.every((c) => c);
+    final allChecked = _grades.every((g) => g != null);
     return Scaffold(
       backgroundColor: Colors.transparent,
       appBar: AppBar(
@@ -1341,8 +1356,9 @@ class _QuizSheetShortAnswerState extends State<QuizSheetShortAnswer> {
             );
           }
           final q = widget.questions[index];
-          final checked = _checked[index];
-          final correct = _corrects[index];
+          final grade = _grades[index];
+          final checked = grade != null;
+          final correct = grade != null && grade.isCorrect;
           return GlassContainer(
             margin: const EdgeInsets.only(bottom: 12),
             padding: const EdgeInsets.all(12),
@@ -1377,7 +1393,7 @@ class _QuizSheetShortAnswerState extends State<QuizSheetShortAnswer> {
                 Row(
                   children: [
                     ElevatedButton.icon(
-                      onPressed: checked ? null : () => _check(index),
+                      onPressed: checked || _grading[index] ? null : () => _check(index),
                       style: ElevatedButton.styleFrom(
                         backgroundColor: const Color(0xFFF2C94C),
                         foregroundColor: Colors.black,
@@ -1387,22 +1403,26 @@ class _QuizSheetShortAnswerState extends State<QuizSheetShortAnswer> {
                       label: const Text('Check'),
                     ),
                     const SizedBox(width: 12),
-                    if (checked)
+                    if (grade != null)
                       Row(
                         children: [
                           Icon(correct ? Icons.check_circle : Icons.cancel, color: correct ? Colors.greenAccent : Colors.redAccent),
                           const SizedBox(width: 6),
                           Text(
-                            correct ? 'Correct' : 'Expected: ${q.answer}',
+                            correct ? 'Correct' : 'Expected: ${grade.answer}',
                             style: TextStyle(color: correct ? Colors.greenAccent : Colors.redAccent, fontWeight: FontWeight.w700),
                           ),
                         ],
                       ),
                   ],
                 ),
-                if (checked && q.explanation.isNotEmpty) ...[
+                if (grade != null && grade.feedback.isNotEmpty) ...[
                   const SizedBox(height: 8),
-                  Text('Explanation: ${q.explanation}', style: const TextStyle(color: Colors.white70, fontSize: 13)),
+                  Text(grade.feedback, style: const TextStyle(color: Colors.white70, fontSize: 13)),
+                ],
+                if (grade != null && grade.explanation.isNotEmpty) ...[
+                  const SizedBox(height: 8),
+                  Text('Explanation: ${grade.explanation}', style: const TextStyle(color: Colors.white70, fontSize: 13)),
                 ],
               ],
             ),
@@ -31,6 +31,7 @@ import 'package:ethio_book_store/features/chat/domain/usecases/getResponseUsecas
 import 'package:ethio_book_store/features/chat/domain/usecases/streamChatResponseUsecase.dart';
 import 'package:ethio_book_store/features/chat/domain/usecases/getShortAnswerUseacase.dart';
 import 'package:ethio_book_store/features/chat/domain/usecases/getTrueFalseUsecase.dart';
+import 'package:ethio_book_store/features/chat/domain/usecases/gradeShortAnswerUsecase.dart';
 import 'package:ethio_book_store/features/chat/presentation/bloc/chat_bloc.dart';
 import 'package:ethio_book_store/features/notes/data/datasources/note_remote_datasource.dart';
 import 'package:ethio_book_store/features/notes/data/repositories/note_repository_impl.dart';
@@ -78,6 +79,7 @@ Future<void> init() async {
   sl.registerLazySingleton(() => StreamChatResponseUseCase(sl())); // Added
   sl.registerLazySingleton(() => GetMultipleQuestionsUseCase(sl()));
   sl.registerLazySingleton(() => GetShortAnswerUseCase(sl()));
+  sl.registerLazySingleton(() => GradeShortAnswerUseCase(sl()));
   sl.registerLazySingleton(() => GetTrueFalseUseCase(sl()));
   
   // Book Features